@@ -1,22 +1,38 @@
 package main
 
 import (
+	"context"
+	"net"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 
 	_ "github.com/GunarsK-portfolio/messaging-api/docs"
+	"github.com/GunarsK-portfolio/messaging-api/internal/captcha"
 	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/delivery"
 	"github.com/GunarsK-portfolio/messaging-api/internal/handlers"
+	"github.com/GunarsK-portfolio/messaging-api/internal/idempotency"
+	"github.com/GunarsK-portfolio/messaging-api/internal/maildomain"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/outbox"
+	"github.com/GunarsK-portfolio/messaging-api/internal/ratelimit"
+	"github.com/GunarsK-portfolio/messaging-api/internal/reconciler"
 	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
 	"github.com/GunarsK-portfolio/messaging-api/internal/routes"
+	"github.com/GunarsK-portfolio/messaging-api/internal/scheduler"
+	"github.com/GunarsK-portfolio/messaging-api/internal/spam"
+	"github.com/GunarsK-portfolio/messaging-api/internal/storage"
 	commondb "github.com/GunarsK-portfolio/portfolio-common/database"
 	"github.com/GunarsK-portfolio/portfolio-common/health"
 	"github.com/GunarsK-portfolio/portfolio-common/logger"
-	"github.com/GunarsK-portfolio/portfolio-common/metrics"
+	commonmetrics "github.com/GunarsK-portfolio/portfolio-common/metrics"
 	"github.com/GunarsK-portfolio/portfolio-common/queue"
+	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
 	"github.com/GunarsK-portfolio/portfolio-common/server"
 )
 
@@ -40,7 +56,7 @@ func main() {
 
 	appLogger.Info("Starting messaging API", "version", "1.0")
 
-	metricsCollector := metrics.New(metrics.Config{
+	metricsCollector := metrics.New(commonmetrics.Config{
 		ServiceName: "messaging",
 		Namespace:   "portfolio",
 	})
@@ -83,21 +99,185 @@ func main() {
 	healthAgg.Register(health.NewPostgresChecker(db))
 	healthAgg.Register(health.NewRabbitMQChecker(publisher.Connection()))
 
+	attachmentStore, err := newAttachmentStore(cfg.Attachments)
+	if err != nil {
+		appLogger.Error("Failed to set up attachment storage", "error", err)
+		os.Exit(1)
+	}
+	if s3, ok := attachmentStore.(*storage.MinIOStore); ok {
+		healthAgg.Register(health.NewMinIOChecker(s3.Client(), cfg.Attachments.Bucket))
+	}
+
+	captchaVerifier, err := newCaptchaVerifier(cfg.Captcha)
+	if err != nil {
+		appLogger.Error("Failed to configure CAPTCHA", "error", err)
+		os.Exit(1)
+	}
+
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Enabled && cfg.RateLimit.Backend == "redis" {
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     net.JoinHostPort(cfg.RateLimit.Redis.Host, strconv.Itoa(cfg.RateLimit.Redis.Port)),
+			Password: cfg.RateLimit.Redis.Password,
+		})
+		defer func() {
+			if closeErr := redisClient.Close(); closeErr != nil {
+				appLogger.Error("Failed to close Redis client", "error", closeErr)
+			}
+		}()
+		healthAgg.Register(health.NewRedisChecker(redisClient))
+		rateLimitStore = ratelimit.NewRedisStore(redisClient)
+	}
+	ipLimit, senderLimit := newContactRateLimits(cfg.RateLimit, rateLimitStore, metricsCollector)
+
+	formTokens := spam.NewTimingToken([]byte(cfg.Spam.TokenSecret), cfg.Spam.TokenMinDelay, cfg.Spam.TokenMaxAge,
+		spamMissingTokenScore, spamInvalidTokenScore)
+
 	repo := repository.New(db)
-	handler := handlers.New(repo, publisher)
+	handler := handlers.New(repo, publisher,
+		handlers.WithLegacyEmailList(cfg.LegacyEmailList),
+		handlers.WithIdempotencyTTL(cfg.Idempotency.KeyTTL),
+		handlers.WithMaxScheduleAhead(cfg.Scheduler.MaxAhead),
+		handlers.WithSupportedLocales(cfg.Locales.Supported),
+		handlers.WithActionLog(commonrepo.NewActionLogRepository(db)),
+		handlers.WithMetrics(metricsCollector),
+		handlers.WithAttachments(attachmentStore, handlers.AttachmentLimits{
+			MaxFileSize:  cfg.Attachments.MaxFileSize,
+			MaxTotalSize: cfg.Attachments.MaxTotalSize,
+			MaxCount:     cfg.Attachments.MaxCount,
+			AllowedTypes: cfg.Attachments.AllowedTypes,
+			URLTTL:       cfg.Attachments.URLTTL,
+		}),
+		handlers.WithSpamFilter(newSpamFilter(cfg.Spam, formTokens)),
+		handlers.WithFormTokens(formTokens),
+		handlers.WithCaptcha(captchaVerifier),
+		handlers.WithSenderDomainCheck(newSenderDomainChecker(cfg.SenderDomain), cfg.SenderDomain.Mode == "reject"),
+		handlers.WithSenderRateLimit(senderLimit),
+		handlers.WithContactAck(cfg.ContactAck.Enabled, cfg.ContactAck.Throttle),
+		handlers.WithDuplicateWindow(cfg.ContactDedup.Window),
+		handlers.WithBlocklistCacheTTL(cfg.Blocklist.CacheTTL),
+		handlers.WithDeliveryAdapters(newDeliveryAdapters(cfg.DeliveryWebhook)...),
+	)
 
 	router := gin.New()
+	// Without trusted proxies ClientIP ignores X-Forwarded-For, which clients could otherwise forge
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		appLogger.Error("Invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
 	router.Use(logger.Recovery(appLogger))
 	router.Use(logger.RequestLogger(appLogger))
 	router.Use(metricsCollector.Middleware())
 
-	routes.Setup(router, handler, cfg, metricsCollector, healthAgg)
+	routes.Setup(router, handler, cfg, metricsCollector.Metrics, healthAgg, ipLimit)
 
 	appLogger.Info("Messaging API ready", "port", cfg.ServiceConfig.Port, "environment", os.Getenv("ENVIRONMENT"))
 
+	// Background workers (stopped after the server shuts down)
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	relay := outbox.NewRelay(repo, publisher, cfg.Outbox, appLogger)
+	workers.Go(func() { relay.Run(workerCtx) })
+
+	purger := idempotency.NewPurger(repo, cfg.Idempotency.PurgeInterval, appLogger)
+	workers.Go(func() { purger.Run(workerCtx) })
+
+	if cfg.Reconciler.Enabled {
+		rec := reconciler.New(repo, publisher, cfg.Reconciler, metricsCollector, appLogger)
+		workers.Go(func() { rec.Run(workerCtx) })
+	}
+
+	if cfg.Scheduler.Enabled {
+		sched := scheduler.New(repo, publisher, cfg.Scheduler, metricsCollector, appLogger)
+		workers.Go(func() { sched.Run(workerCtx) })
+	}
+
 	serverCfg := server.DefaultConfig(strconv.Itoa(cfg.ServiceConfig.Port))
-	if err := server.Run(router, serverCfg, appLogger); err != nil {
+	err = server.Run(router, serverCfg, appLogger)
+
+	stopWorkers()
+	workers.Wait()
+
+	if err != nil {
 		appLogger.Error("Server error", "error", err)
 		os.Exit(1)
 	}
 }
+
+// newAttachmentStore creates the configured attachment storage backend (nil when attachments are disabled)
+func newAttachmentStore(cfg config.AttachmentConfig) (storage.Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.Backend == "minio" {
+		return storage.NewMinIOStore(*cfg.S3, cfg.Bucket)
+	}
+	return storage.NewLocalStore(cfg.LocalDir, cfg.DownloadURL, []byte(cfg.SigningKey))
+}
+
+// newDeliveryAdapters creates an adapter for each delivery webhook provider with credentials configured
+func newDeliveryAdapters(cfg config.DeliveryWebhookConfig) []delivery.Adapter {
+	var adapters []delivery.Adapter
+	if len(cfg.SESTopicARNs) > 0 {
+		adapters = append(adapters, delivery.NewSES(cfg.SESTopicARNs, cfg.FetchTimeout))
+	}
+	if cfg.PostmarkUsername != "" {
+		adapters = append(adapters, delivery.NewPostmark(cfg.PostmarkUsername, cfg.PostmarkPassword))
+	}
+	if cfg.MailgunSigningKey != "" {
+		adapters = append(adapters, delivery.NewMailgun(cfg.MailgunSigningKey, cfg.MaxAge))
+	}
+	return adapters
+}
+
+// newCaptchaVerifier creates the configured CAPTCHA verifier (nil when CAPTCHA is disabled)
+func newCaptchaVerifier(cfg config.CaptchaConfig) (captcha.Verifier, error) {
+	if cfg.Provider == "none" {
+		return nil, nil
+	}
+	return captcha.New(cfg.Provider, cfg.Secret, cfg.Endpoint, cfg.MinScore, cfg.Timeout)
+}
+
+// newSenderDomainChecker creates the contact sender domain check using the system resolver (nil when disabled)
+func newSenderDomainChecker(cfg config.SenderDomainConfig) *maildomain.Checker {
+	if cfg.Mode == "off" {
+		return nil
+	}
+	return maildomain.New(net.DefaultResolver, cfg.CacheTTL, cfg.Timeout)
+}
+
+// newContactRateLimits creates the per-IP and per-sender limiters for POST /contact (both nil when disabled)
+func newContactRateLimits(cfg config.RateLimitConfig, store ratelimit.Store, m *metrics.Metrics) (ip, sender *ratelimit.Limiter) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	ip = ratelimit.New(store, "contact_ip", ratelimit.Limit{Burst: cfg.IPBurst, Period: cfg.IPPeriod},
+		m.ContactRateLimited.WithLabelValues("ip"))
+	sender = ratelimit.New(store, "contact_sender", ratelimit.Limit{Burst: cfg.SenderBurst, Period: cfg.SenderPeriod},
+		m.ContactRateLimited.WithLabelValues("sender"))
+	return ip, sender
+}
+
+// Spam scorer weights, relative to the configured quarantine and reject scores
+const (
+	spamPerExtraLinkScore  = 2
+	spamBlockedDomainScore = 5
+	spamKeywordScore       = 3
+	spamDisposableScore    = 4
+	spamMissingTokenScore  = 2
+	spamInvalidTokenScore  = 5
+)
+
+// newSpamFilter builds the contact form spam pipeline from the built-in scorers
+func newSpamFilter(cfg config.SpamConfig, formTokens *spam.TimingToken) *spam.Pipeline {
+	return spam.NewPipeline(cfg.QuarantineScore, cfg.RejectScore,
+		spam.Honeypot{},
+		spam.LinkCount{Allowed: cfg.MaxLinks, PerLink: spamPerExtraLinkScore},
+		spam.NewBlocklist(cfg.BlockedDomains, cfg.BlockedKeywords, spamBlockedDomainScore, spamKeywordScore),
+		spam.NewDisposable(cfg.DisposableDomains, spamDisposableScore),
+		spam.Heuristics{},
+		formTokens,
+	)
+}
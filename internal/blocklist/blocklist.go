@@ -0,0 +1,182 @@
+// Package blocklist matches contact senders against the blocklist, holding the compiled entries in an
+// in-process cache that is reloaded when it expires or is invalidated after a change.
+package blocklist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+// Normalize validates a value for an entry kind and returns the canonical form stored in the database:
+// lower-cased addresses, domains and patterns, and CIDRs with the host bits cleared.
+func Normalize(kind, value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", errors.New("value is required")
+	}
+
+	switch kind {
+	case repository.BlocklistKindAddress:
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return "", fmt.Errorf("invalid email address %q", value)
+		}
+	case repository.BlocklistKindDomain:
+		value = strings.TrimPrefix(strings.TrimSuffix(value, "."), "@")
+		if strings.ContainsAny(value, "@*? ") || !strings.Contains(value, ".") {
+			return "", fmt.Errorf("invalid domain %q", value)
+		}
+	case repository.BlocklistKindPattern:
+		if !strings.ContainsAny(value, "*?") {
+			return "", fmt.Errorf("pattern %q has no wildcard, use an address or domain entry", value)
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return "", fmt.Errorf("invalid pattern %q: %w", value, err)
+		}
+	case repository.BlocklistKindCIDR:
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				return "", fmt.Errorf("invalid CIDR %q: %w", value, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		value = prefix.Masked().String()
+	default:
+		return "", fmt.Errorf("unknown blocklist kind %q", kind)
+	}
+	return value, nil
+}
+
+// Matcher checks senders against a set of compiled entries
+type Matcher struct {
+	addresses map[string]repository.BlocklistEntry
+	domains   map[string]repository.BlocklistEntry
+	patterns  []repository.BlocklistEntry
+	prefixes  []prefixEntry
+}
+
+type prefixEntry struct {
+	prefix netip.Prefix
+	entry  repository.BlocklistEntry
+}
+
+// NewMatcher compiles entries; entries that no longer parse are skipped
+func NewMatcher(entries []repository.BlocklistEntry) *Matcher {
+	m := &Matcher{
+		addresses: make(map[string]repository.BlocklistEntry),
+		domains:   make(map[string]repository.BlocklistEntry),
+	}
+	for _, e := range entries {
+		switch e.Kind {
+		case repository.BlocklistKindAddress:
+			m.addresses[e.Value] = e
+		case repository.BlocklistKindDomain:
+			m.domains[e.Value] = e
+		case repository.BlocklistKindPattern:
+			m.patterns = append(m.patterns, e)
+		case repository.BlocklistKindCIDR:
+			if prefix, err := netip.ParsePrefix(e.Value); err == nil {
+				m.prefixes = append(m.prefixes, prefixEntry{prefix: prefix, entry: e})
+			}
+		}
+	}
+	return m
+}
+
+// Match returns the first entry blocking the sender: the exact address, its domain or a parent domain,
+// a pattern matching the whole address, or a CIDR containing the client IP.
+func (m *Matcher) Match(address, clientIP string) (repository.BlocklistEntry, bool) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if e, ok := m.addresses[address]; ok {
+		return e, true
+	}
+	if at := strings.LastIndexByte(address, '@'); at >= 0 {
+		for domain := address[at+1:]; domain != ""; {
+			if e, ok := m.domains[domain]; ok {
+				return e, true
+			}
+			dot := strings.IndexByte(domain, '.')
+			if dot < 0 {
+				break
+			}
+			domain = domain[dot+1:]
+		}
+	}
+	for _, e := range m.patterns {
+		if ok, _ := path.Match(e.Value, address); ok {
+			return e, true
+		}
+	}
+	if ip, err := netip.ParseAddr(clientIP); err == nil {
+		ip = ip.Unmap()
+		for _, p := range m.prefixes {
+			if p.prefix.Contains(ip) {
+				return p.entry, true
+			}
+		}
+	}
+	return repository.BlocklistEntry{}, false
+}
+
+// Loader reads every blocklist entry
+type Loader func(ctx context.Context) ([]repository.BlocklistEntry, error)
+
+// Cache holds a Matcher built from the loaded entries for up to ttl. Changes made through this
+// instance call Invalidate; changes made by other replicas are picked up when the ttl expires.
+type Cache struct {
+	load Loader
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	matcher *Matcher
+	expires time.Time
+}
+
+// NewCache creates an empty cache that loads entries on first use
+func NewCache(load Loader, ttl time.Duration) *Cache {
+	return &Cache{load: load, ttl: ttl, now: time.Now}
+}
+
+// Match checks a sender against the cached entries, loading them first if needed
+func (c *Cache) Match(ctx context.Context, address, clientIP string) (repository.BlocklistEntry, bool, error) {
+	m, err := c.current(ctx)
+	if err != nil {
+		return repository.BlocklistEntry{}, false, err
+	}
+	e, ok := m.Match(address, clientIP)
+	return e, ok, nil
+}
+
+// Invalidate drops the cached entries so the next Match reloads them
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.matcher = nil
+}
+
+func (c *Cache) current(ctx context.Context) (*Matcher, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.matcher != nil && c.now().Before(c.expires) {
+		return c.matcher, nil
+	}
+	entries, err := c.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blocklist: %w", err)
+	}
+	c.matcher = NewMatcher(entries)
+	c.expires = c.now().Add(c.ttl)
+	return c.matcher, nil
+}
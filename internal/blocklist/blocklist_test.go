@@ -0,0 +1,133 @@
+package blocklist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		kind    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{repository.BlocklistKindAddress, " Spammer@Example.com ", "spammer@example.com", false},
+		{repository.BlocklistKindAddress, "not-an-address", "", true},
+		{repository.BlocklistKindAddress, "Jane <jane@example.com>", "", true},
+		{repository.BlocklistKindDomain, "Example.COM.", "example.com", false},
+		{repository.BlocklistKindDomain, "@example.com", "example.com", false},
+		{repository.BlocklistKindDomain, "localhost", "", true},
+		{repository.BlocklistKindDomain, "*.example.com", "", true},
+		{repository.BlocklistKindPattern, "Seo*@*.example.com", "seo*@*.example.com", false},
+		{repository.BlocklistKindPattern, "jane@example.com", "", true},
+		{repository.BlocklistKindPattern, "[*@example.com", "", true},
+		{repository.BlocklistKindCIDR, "203.0.113.77/24", "203.0.113.0/24", false},
+		{repository.BlocklistKindCIDR, "203.0.113.7", "203.0.113.7/32", false},
+		{repository.BlocklistKindCIDR, "2001:DB8::/32", "2001:db8::/32", false},
+		{repository.BlocklistKindCIDR, "203.0.113.0/33", "", true},
+		{"regex", "x", "", true},
+		{repository.BlocklistKindAddress, "  ", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.kind, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Normalize(%q, %q) error = %v, wantErr %v", tt.kind, tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tt.kind, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatcher_Match(t *testing.T) {
+	m := NewMatcher([]repository.BlocklistEntry{
+		{ID: 1, Kind: repository.BlocklistKindAddress, Value: "spammer@example.com"},
+		{ID: 2, Kind: repository.BlocklistKindDomain, Value: "spam.test"},
+		{ID: 3, Kind: repository.BlocklistKindPattern, Value: "seo*@*"},
+		{ID: 4, Kind: repository.BlocklistKindCIDR, Value: "203.0.113.0/24"},
+		{ID: 5, Kind: repository.BlocklistKindCIDR, Value: "not-a-cidr"},
+	})
+
+	tests := []struct {
+		name    string
+		address string
+		ip      string
+		wantID  int64
+	}{
+		{"exact address", "Spammer@Example.com", "198.51.100.1", 1},
+		{"other address at same domain", "jane@example.com", "198.51.100.1", 0},
+		{"domain", "anyone@spam.test", "198.51.100.1", 2},
+		{"subdomain", "anyone@mail.spam.test", "198.51.100.1", 2},
+		{"lookalike domain", "anyone@notspam.test", "198.51.100.1", 0},
+		{"pattern", "seo-expert@agency.com", "198.51.100.1", 3},
+		{"pattern must match whole address", "jane.seo@agency.com", "198.51.100.1", 0},
+		{"cidr", "jane@example.com", "203.0.113.99", 4},
+		{"ipv4-mapped ipv6", "jane@example.com", "::ffff:203.0.113.99", 4},
+		{"no client ip", "jane@example.com", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := m.Match(tt.address, tt.ip)
+			if ok != (tt.wantID != 0) || entry.ID != tt.wantID {
+				t.Errorf("Match(%q, %q) = %d, %v; want entry %d", tt.address, tt.ip, entry.ID, ok, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	loads := 0
+	entries := []repository.BlocklistEntry{{ID: 1, Kind: repository.BlocklistKindDomain, Value: "spam.test"}}
+	cache := NewCache(func(context.Context) ([]repository.BlocklistEntry, error) {
+		loads++
+		return entries, nil
+	}, time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 3 {
+		if _, ok, _ := cache.Match(ctx, "a@spam.test", ""); !ok {
+			t.Fatal("expected sender to be blocked")
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected one load while cached, got %d", loads)
+	}
+
+	// A change through this instance applies immediately
+	entries = nil
+	cache.Invalidate()
+	if _, ok, _ := cache.Match(ctx, "a@spam.test", ""); ok {
+		t.Error("expected invalidation to reload the entries")
+	}
+
+	// A change elsewhere applies once the ttl expires
+	entries = []repository.BlocklistEntry{{ID: 2, Kind: repository.BlocklistKindAddress, Value: "b@example.com"}}
+	if _, ok, _ := cache.Match(ctx, "b@example.com", ""); ok {
+		t.Error("expected cached entries to be used before the ttl expires")
+	}
+	now = now.Add(time.Minute)
+	if _, ok, _ := cache.Match(ctx, "b@example.com", ""); !ok {
+		t.Error("expected entries to be reloaded after the ttl")
+	}
+	if loads != 3 {
+		t.Errorf("expected 3 loads, got %d", loads)
+	}
+}
+
+func TestCache_LoadError(t *testing.T) {
+	cache := NewCache(func(context.Context) ([]repository.BlocklistEntry, error) {
+		return nil, errors.New("database error")
+	}, time.Minute)
+
+	if _, _, err := cache.Match(context.Background(), "a@example.com", ""); err == nil {
+		t.Error("expected load error to be returned")
+	}
+}
@@ -0,0 +1,117 @@
+// Package captcha verifies CAPTCHA tokens from the contact form with a provider's siteverify API.
+// Turnstile, hCaptcha and reCAPTCHA share the same protocol and differ only in endpoint and response extras.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Providers
+const (
+	ProviderTurnstile = "turnstile"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCAPTCHA = "recaptcha"
+)
+
+// Default siteverify endpoints
+const (
+	TurnstileEndpoint = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	HCaptchaEndpoint  = "https://api.hcaptcha.com/siteverify"
+	ReCAPTCHAEndpoint = "https://www.google.com/recaptcha/api/siteverify"
+)
+
+// ErrRejected is returned when the token is missing or the provider does not accept it.
+// Any other error means the provider could not be asked.
+var ErrRejected = errors.New("captcha rejected")
+
+// Verifier checks a CAPTCHA token solved by the visitor
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// SiteVerifier calls a siteverify endpoint
+type SiteVerifier struct {
+	endpoint string
+	secret   string
+	// minScore rejects reCAPTCHA v3 responses scoring below it (0 disables the check)
+	minScore float64
+	client   *http.Client
+}
+
+// New creates a verifier for a provider. An empty endpoint selects the provider's public API;
+// minScore only applies to reCAPTCHA v3, whose responses carry a score.
+func New(provider, secret, endpoint string, minScore float64, timeout time.Duration) (*SiteVerifier, error) {
+	if endpoint == "" {
+		switch provider {
+		case ProviderTurnstile:
+			endpoint = TurnstileEndpoint
+		case ProviderHCaptcha:
+			endpoint = HCaptchaEndpoint
+		case ProviderReCAPTCHA:
+			endpoint = ReCAPTCHAEndpoint
+		default:
+			return nil, fmt.Errorf("unknown captcha provider %q", provider)
+		}
+	}
+	if provider != ProviderReCAPTCHA {
+		minScore = 0
+	}
+	return &SiteVerifier{
+		endpoint: endpoint,
+		secret:   secret,
+		minScore: minScore,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// siteVerifyResponse is the response shared by all providers; Score is only sent by reCAPTCHA v3
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+	Score      *float64 `json:"score"`
+}
+
+// Verify implements Verifier
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("%w: missing token", ErrRejected)
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build captcha request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call captcha provider: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha provider returned status %d", resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode captcha response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrRejected, strings.Join(result.ErrorCodes, ", "))
+	}
+	if v.minScore > 0 && result.Score != nil && *result.Score < v.minScore {
+		return fmt.Errorf("%w: score %.1f below %.1f", ErrRejected, *result.Score, v.minScore)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stubProvider serves a fixed siteverify response and records the last form it received
+func stubProvider(t *testing.T, status int, body string, got *map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		if got != nil {
+			*got = map[string]string{
+				"secret":   r.PostForm.Get("secret"),
+				"response": r.PostForm.Get("response"),
+				"remoteip": r.PostForm.Get("remoteip"),
+			}
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNew_DefaultEndpoints(t *testing.T) {
+	for provider, want := range map[string]string{
+		ProviderTurnstile: TurnstileEndpoint,
+		ProviderHCaptcha:  HCaptchaEndpoint,
+		ProviderReCAPTCHA: ReCAPTCHAEndpoint,
+	} {
+		v, err := New(provider, "secret", "", 0, time.Second)
+		if err != nil {
+			t.Fatalf("New(%q) error = %v", provider, err)
+		}
+		if v.endpoint != want {
+			t.Errorf("New(%q) endpoint = %q, want %q", provider, v.endpoint, want)
+		}
+	}
+
+	if _, err := New("friendlycaptcha", "secret", "", 0, time.Second); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     string
+		status       int
+		body         string
+		wantErr      bool
+		wantRejected bool
+	}{
+		{"success", ProviderTurnstile, http.StatusOK, `{"success":true}`, false, false},
+		{"rejected", ProviderHCaptcha, http.StatusOK, `{"success":false,"error-codes":["invalid-input-response"]}`, true, true},
+		{"low score", ProviderReCAPTCHA, http.StatusOK, `{"success":true,"score":0.2}`, true, true},
+		{"good score", ProviderReCAPTCHA, http.StatusOK, `{"success":true,"score":0.9}`, false, false},
+		{"score ignored for turnstile", ProviderTurnstile, http.StatusOK, `{"success":true,"score":0.1}`, false, false},
+		{"provider error", ProviderTurnstile, http.StatusInternalServerError, ``, true, false},
+		{"bad json", ProviderTurnstile, http.StatusOK, `<html>`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form map[string]string
+			srv := stubProvider(t, tt.status, tt.body, &form)
+			v, err := New(tt.provider, "s3cret", srv.URL, 0.5, time.Second)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			err = v.Verify(context.Background(), "tok", "203.0.113.7")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrRejected) != tt.wantRejected {
+				t.Errorf("Verify() error = %v, want rejected %v", err, tt.wantRejected)
+			}
+			if form["secret"] != "s3cret" || form["response"] != "tok" || form["remoteip"] != "203.0.113.7" {
+				t.Errorf("unexpected form sent %v", form)
+			}
+		})
+	}
+}
+
+func TestVerify_MissingToken(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) { called = true }))
+	defer srv.Close()
+
+	v, _ := New(ProviderTurnstile, "secret", srv.URL, 0, time.Second)
+	if err := v.Verify(context.Background(), " ", ""); !errors.Is(err, ErrRejected) {
+		t.Errorf("Verify() error = %v, want ErrRejected", err)
+	}
+	if called {
+		t.Error("expected provider not to be called without a token")
+	}
+}
@@ -1,10 +1,17 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 
+	"github.com/GunarsK-portfolio/messaging-api/internal/locale"
 	common "github.com/GunarsK-portfolio/portfolio-common/config"
 )
 
@@ -14,15 +21,294 @@ type Config struct {
 	common.ServiceConfig
 	common.RabbitMQConfig
 	JWTSecret string `validate:"required,min=32"`
+	// TrustedProxies are the IPs/CIDRs (e.g. the Traefik network) whose X-Forwarded-For is believed
+	// for the client IP used by rate limits and IP blocklist entries; empty uses the connection address
+	TrustedProxies []string `validate:"dive,cidr|ip"`
+	// LegacyEmailList keeps GET /emails and /messages returning a bare array instead of a paged envelope
+	LegacyEmailList bool
+	Outbox          OutboxConfig
+	Reconciler      ReconcilerConfig
+	Scheduler       SchedulerConfig
+	Idempotency     IdempotencyConfig
+	Locales         LocaleConfig
+	Attachments     AttachmentConfig
+	ContactAck      ContactAckConfig
+	ContactDedup    ContactDedupConfig
+	Spam            SpamConfig
+	Captcha         CaptchaConfig
+	SenderDomain    SenderDomainConfig
+	Blocklist       BlocklistConfig
+	RateLimit       RateLimitConfig
+	DeliveryWebhook DeliveryWebhookConfig
+}
+
+// OutboxConfig controls the background relay that publishes outbox entries
+type OutboxConfig struct {
+	PollInterval time.Duration `validate:"required"`
+	BatchSize    int           `validate:"min=1,max=1000"`
+	GracePeriod  time.Duration `validate:"min=0"`
+	BaseBackoff  time.Duration `validate:"required"`
+	MaxBackoff   time.Duration `validate:"required,gtefield=BaseBackoff"`
+	// ClaimTimeout is how long a claimed batch is hidden from other replicas' relays before it is due again
+	ClaimTimeout time.Duration `validate:"required"`
+}
+
+// ReconcilerConfig controls the periodic re-publishing of stale pending emails
+type ReconcilerConfig struct {
+	Enabled   bool
+	Interval  time.Duration `validate:"required"`
+	MinAge    time.Duration `validate:"required"`
+	BatchSize int           `validate:"min=1,max=1000"`
+}
+
+// SchedulerConfig controls the release of scheduled emails (POST /emails with send_at) when they are due
+type SchedulerConfig struct {
+	Enabled   bool
+	Interval  time.Duration `validate:"required"`
+	BatchSize int           `validate:"min=1,max=1000"`
+	// MaxAhead is how far in the future send_at may be
+	MaxAhead time.Duration `validate:"required"`
+}
+
+// IdempotencyConfig controls Idempotency-Key handling on POST /emails
+type IdempotencyConfig struct {
+	// KeyTTL is how long a key replays its original response before it can be reused
+	KeyTTL time.Duration `validate:"required"`
+	// PurgeInterval is how often expired keys are deleted
+	PurgeInterval time.Duration `validate:"required"`
+}
+
+// LocaleConfig controls which locales POST /emails and templates accept
+type LocaleConfig struct {
+	// Supported lists canonical language tags; a request locale is accepted if it or a parent is listed
+	Supported []string `validate:"min=1"`
+}
+
+// AttachmentConfig controls attachment uploads on POST /contact and POST /emails
+type AttachmentConfig struct {
+	// Enabled turns uploads on; when off, POST /contact accepts only JSON and POST /emails rejects attachments
+	Enabled bool
+	// Backend is "minio" (S3-compatible, configured via S3_*) or "local" (a directory, for dev and tests)
+	Backend string `validate:"oneof=local minio"`
+	// LocalDir, DownloadURL and SigningKey configure the local backend; DownloadURL is this service's signed download route.
+	// SigningKey must never be JWT_SECRET itself (unset derives a separate key).
+	LocalDir    string `validate:"required_if=Backend local"`
+	DownloadURL string `validate:"required_if=Backend local,omitempty,url"`
+	SigningKey  string `validate:"required_if=Backend local,omitempty,min=32"`
+	// Bucket is the MinIO/S3 bucket for attachment blobs
+	Bucket string `validate:"required_if=Backend minio"`
+	S3     *common.S3Config
+
+	MaxFileSize  int64         `validate:"min=1"`
+	MaxTotalSize int64         `validate:"gtefield=MaxFileSize"`
+	MaxCount     int           `validate:"min=1,max=20"`
+	AllowedTypes []string      `validate:"min=1"`
+	URLTTL       time.Duration `validate:"required"`
+}
+
+// ContactAckConfig controls the contact_ack auto-reply sent to contact form senders
+type ContactAckConfig struct {
+	Enabled bool
+	// Throttle is the minimum time between two acknowledgements to the same address (0 disables throttling)
+	Throttle time.Duration `validate:"min=0"`
+}
+
+// ContactDedupConfig controls duplicate detection on POST /contact
+type ContactDedupConfig struct {
+	// Window is how long a submission with the same sender, subject and message is ignored (0 disables the check)
+	Window time.Duration `validate:"min=0"`
+}
+
+// SpamConfig controls the spam scoring pipeline on POST /contact
+type SpamConfig struct {
+	// Submissions scoring at least QuarantineScore are held for review; at least RejectScore are dropped
+	QuarantineScore int `validate:"min=1"`
+	RejectScore     int `validate:"gtfield=QuarantineScore"`
+	// MaxLinks links are free; each further link adds to the score
+	MaxLinks          int `validate:"min=0"`
+	BlockedDomains    []string
+	BlockedKeywords   []string
+	DisposableDomains []string
+	// TokenSecret signs form timing tokens; submissions sooner than TokenMinDelay after the form loaded score as spam.
+	// Tokens are handed out unauthenticated, so it must never be JWT_SECRET itself (unset derives a separate key).
+	TokenSecret   string        `validate:"required,min=32"`
+	TokenMinDelay time.Duration `validate:"min=0"`
+	TokenMaxAge   time.Duration `validate:"gtfield=TokenMinDelay"`
+}
+
+// CaptchaConfig controls CAPTCHA verification on POST /contact
+type CaptchaConfig struct {
+	// Provider is turnstile, hcaptcha, recaptcha, or none to disable CAPTCHA
+	Provider string `validate:"oneof=none turnstile hcaptcha recaptcha"`
+	Secret   string `validate:"required_unless=Provider none"`
+	// Endpoint overrides the provider's siteverify URL, e.g. with a local stub in tests
+	Endpoint string `validate:"omitempty,url"`
+	// MinScore rejects reCAPTCHA v3 tokens scoring below it
+	MinScore float64       `validate:"min=0,max=1"`
+	Timeout  time.Duration `validate:"required"`
+}
+
+// SenderDomainConfig controls the MX/address record check of contact senders' domains
+type SenderDomainConfig struct {
+	// Mode is off, flag (quarantine messages from domains that accept no mail) or reject (answer 400)
+	Mode string `validate:"oneof=off flag reject"`
+	// CacheTTL is how long a domain's result is reused
+	CacheTTL time.Duration `validate:"required"`
+	Timeout  time.Duration `validate:"required"`
+}
+
+// BlocklistConfig controls the in-process cache of the contact form blocklist
+type BlocklistConfig struct {
+	// CacheTTL bounds how long changes made through another replica take to apply here
+	CacheTTL time.Duration `validate:"required"`
+}
+
+// RateLimitConfig controls the token buckets limiting POST /contact per client IP and per sender address
+type RateLimitConfig struct {
+	Enabled bool
+	// Backend is "memory" (per replica) or "redis" (shared across replicas, configured via REDIS_*)
+	Backend string `validate:"oneof=memory redis"`
+	Redis   *common.RedisConfig
+	// Each bucket allows a burst of *Burst submissions and refills completely over *Period
+	IPBurst      int           `validate:"min=1"`
+	IPPeriod     time.Duration `validate:"required"`
+	SenderBurst  int           `validate:"min=1"`
+	SenderPeriod time.Duration `validate:"required"`
+}
+
+// DeliveryWebhookConfig enables POST /webhooks/delivery/{provider}; each provider is enabled by its credentials
+type DeliveryWebhookConfig struct {
+	// SESTopicARNs are the SNS topics SES notifications may arrive from
+	SESTopicARNs []string
+	// PostmarkUsername and PostmarkPassword are the basic auth credentials in Postmark's webhook URL
+	PostmarkUsername string
+	PostmarkPassword string `validate:"required_with=PostmarkUsername"`
+	// MailgunSigningKey is the webhook signing key of the Mailgun domain
+	MailgunSigningKey string
+	// MaxAge rejects Mailgun signatures older than this
+	MaxAge time.Duration `validate:"required"`
+	// FetchTimeout bounds SNS signing certificate downloads and subscription confirmations
+	FetchTimeout time.Duration `validate:"required"`
 }
 
 // Load loads all configuration from environment variables
 func Load() *Config {
 	cfg := &Config{
-		DatabaseConfig: common.NewDatabaseConfig(),
-		ServiceConfig:  common.NewServiceConfig(8086),
-		RabbitMQConfig: common.NewRabbitMQConfig(),
-		JWTSecret:      common.GetEnvRequired("JWT_SECRET"),
+		DatabaseConfig:  common.NewDatabaseConfig(),
+		ServiceConfig:   common.NewServiceConfig(8086),
+		RabbitMQConfig:  common.NewRabbitMQConfig(),
+		JWTSecret:       common.GetEnvRequired("JWT_SECRET"),
+		TrustedProxies:  splitList(common.GetEnv("TRUSTED_PROXIES", "")),
+		LegacyEmailList: common.GetEnvBool("EMAILS_LEGACY_LIST_RESPONSE", false),
+		Outbox: OutboxConfig{
+			PollInterval: common.GetEnvDuration("OUTBOX_POLL_INTERVAL", 5*time.Second),
+			BatchSize:    common.GetEnvInt("OUTBOX_BATCH_SIZE", 100),
+			GracePeriod:  common.GetEnvDuration("OUTBOX_GRACE_PERIOD", 30*time.Second),
+			BaseBackoff:  common.GetEnvDuration("OUTBOX_BASE_BACKOFF", 5*time.Second),
+			MaxBackoff:   common.GetEnvDuration("OUTBOX_MAX_BACKOFF", 10*time.Minute),
+			ClaimTimeout: common.GetEnvDuration("OUTBOX_CLAIM_TIMEOUT", time.Minute),
+		},
+		Reconciler: ReconcilerConfig{
+			Enabled:   common.GetEnvBool("RECONCILER_ENABLED", true),
+			Interval:  common.GetEnvDuration("RECONCILER_INTERVAL", time.Minute),
+			MinAge:    common.GetEnvDuration("RECONCILER_MIN_AGE", 15*time.Minute),
+			BatchSize: common.GetEnvInt("RECONCILER_BATCH_SIZE", 100),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:   common.GetEnvBool("SCHEDULER_ENABLED", true),
+			Interval:  common.GetEnvDuration("SCHEDULER_INTERVAL", 15*time.Second),
+			BatchSize: common.GetEnvInt("SCHEDULER_BATCH_SIZE", 100),
+			MaxAhead:  common.GetEnvDuration("SCHEDULER_MAX_AHEAD", 90*24*time.Hour),
+		},
+		Idempotency: IdempotencyConfig{
+			KeyTTL:        common.GetEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+			PurgeInterval: common.GetEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour),
+		},
+		Locales: LocaleConfig{
+			Supported: parseLocales(common.GetEnv("SUPPORTED_LOCALES", "en,lv")),
+		},
+		Attachments: AttachmentConfig{
+			Enabled:      common.GetEnvBool("ATTACHMENTS_ENABLED", false),
+			Backend:      common.GetEnv("ATTACHMENT_STORAGE", "local"),
+			LocalDir:     common.GetEnv("ATTACHMENT_LOCAL_DIR", "./data/attachments"),
+			DownloadURL:  common.GetEnv("ATTACHMENT_DOWNLOAD_URL", "http://localhost:8086/api/v1/attachments/files"),
+			SigningKey:   common.GetEnv("ATTACHMENT_SIGNING_KEY", ""),
+			Bucket:       common.GetEnv("S3_ATTACHMENTS_BUCKET", "attachments"),
+			MaxFileSize:  common.GetEnvInt64("ATTACHMENT_MAX_FILE_SIZE", 10<<20),
+			MaxTotalSize: common.GetEnvInt64("ATTACHMENT_MAX_TOTAL_SIZE", 20<<20),
+			MaxCount:     common.GetEnvInt("ATTACHMENT_MAX_COUNT", 5),
+			AllowedTypes: splitList(common.GetEnv("ATTACHMENT_ALLOWED_TYPES", defaultAttachmentTypes)),
+			URLTTL:       common.GetEnvDuration("ATTACHMENT_URL_TTL", 15*time.Minute),
+		},
+		ContactAck: ContactAckConfig{
+			Enabled:  common.GetEnvBool("CONTACT_ACK_ENABLED", false),
+			Throttle: common.GetEnvDuration("CONTACT_ACK_THROTTLE", 24*time.Hour),
+		},
+		ContactDedup: ContactDedupConfig{
+			Window: common.GetEnvDuration("CONTACT_DUPLICATE_WINDOW", 10*time.Minute),
+		},
+		Spam: SpamConfig{
+			QuarantineScore:   common.GetEnvInt("SPAM_QUARANTINE_SCORE", 5),
+			RejectScore:       common.GetEnvInt("SPAM_REJECT_SCORE", 10),
+			MaxLinks:          common.GetEnvInt("SPAM_MAX_LINKS", 2),
+			BlockedDomains:    splitList(common.GetEnv("SPAM_BLOCKED_DOMAINS", "")),
+			BlockedKeywords:   splitList(common.GetEnv("SPAM_BLOCKED_KEYWORDS", "")),
+			DisposableDomains: splitList(common.GetEnv("SPAM_DISPOSABLE_DOMAINS", "")),
+			TokenSecret:       common.GetEnv("SPAM_TOKEN_SECRET", ""),
+			TokenMinDelay:     common.GetEnvDuration("SPAM_TOKEN_MIN_DELAY", 3*time.Second),
+			TokenMaxAge:       common.GetEnvDuration("SPAM_TOKEN_MAX_AGE", 24*time.Hour),
+		},
+		Captcha: CaptchaConfig{
+			Provider: common.GetEnv("CAPTCHA_PROVIDER", "none"),
+			Secret:   common.GetEnv("CAPTCHA_SECRET", ""),
+			Endpoint: common.GetEnv("CAPTCHA_VERIFY_URL", ""),
+			MinScore: parseFloat("CAPTCHA_MIN_SCORE", common.GetEnv("CAPTCHA_MIN_SCORE", "0.5")),
+			Timeout:  common.GetEnvDuration("CAPTCHA_TIMEOUT", 5*time.Second),
+		},
+		SenderDomain: SenderDomainConfig{
+			Mode:     common.GetEnv("SENDER_DOMAIN_CHECK", "off"),
+			CacheTTL: common.GetEnvDuration("SENDER_DOMAIN_CACHE_TTL", time.Hour),
+			Timeout:  common.GetEnvDuration("SENDER_DOMAIN_TIMEOUT", 3*time.Second),
+		},
+		Blocklist: BlocklistConfig{
+			CacheTTL: common.GetEnvDuration("BLOCKLIST_CACHE_TTL", time.Minute),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:      common.GetEnvBool("RATE_LIMIT_ENABLED", true),
+			Backend:      common.GetEnv("RATE_LIMIT_BACKEND", "memory"),
+			IPBurst:      common.GetEnvInt("RATE_LIMIT_IP_BURST", 10),
+			IPPeriod:     common.GetEnvDuration("RATE_LIMIT_IP_PERIOD", time.Hour),
+			SenderBurst:  common.GetEnvInt("RATE_LIMIT_SENDER_BURST", 3),
+			SenderPeriod: common.GetEnvDuration("RATE_LIMIT_SENDER_PERIOD", time.Hour),
+		},
+		DeliveryWebhook: DeliveryWebhookConfig{
+			SESTopicARNs:      splitList(common.GetEnv("DELIVERY_WEBHOOK_SES_TOPIC_ARNS", "")),
+			PostmarkUsername:  common.GetEnv("DELIVERY_WEBHOOK_POSTMARK_USERNAME", ""),
+			PostmarkPassword:  common.GetEnv("DELIVERY_WEBHOOK_POSTMARK_PASSWORD", ""),
+			MailgunSigningKey: common.GetEnv("DELIVERY_WEBHOOK_MAILGUN_SIGNING_KEY", ""),
+			MaxAge:            common.GetEnvDuration("DELIVERY_WEBHOOK_MAX_AGE", 15*time.Minute),
+			FetchTimeout:      common.GetEnvDuration("DELIVERY_WEBHOOK_FETCH_TIMEOUT", 5*time.Second),
+		},
+	}
+	if cfg.Attachments.SigningKey == "" {
+		cfg.Attachments.SigningKey = deriveKey(cfg.JWTSecret, "attachment-download-url")
+	}
+	if cfg.Attachments.SigningKey == cfg.JWTSecret {
+		panic("Invalid configuration: ATTACHMENT_SIGNING_KEY must differ from JWT_SECRET")
+	}
+	if cfg.Spam.TokenSecret == "" {
+		cfg.Spam.TokenSecret = deriveKey(cfg.JWTSecret, "spam-timing-token")
+	}
+	if cfg.Spam.TokenSecret == cfg.JWTSecret {
+		panic("Invalid configuration: SPAM_TOKEN_SECRET must differ from JWT_SECRET")
+	}
+	if cfg.Attachments.Enabled && cfg.Attachments.Backend == "minio" {
+		s3 := common.NewS3Config()
+		cfg.Attachments.S3 = &s3
+	}
+	if cfg.RateLimit.Enabled && cfg.RateLimit.Backend == "redis" {
+		redisCfg := common.NewRedisConfig()
+		cfg.RateLimit.Redis = &redisCfg
 	}
 
 	// Validate service-specific fields
@@ -33,3 +319,51 @@ func Load() *Config {
 
 	return cfg
 }
+
+// defaultAttachmentTypes covers CVs, briefs and invoices: PDF, Word, plain text and images
+const defaultAttachmentTypes = "application/pdf," +
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document," +
+	"text/plain,image/png,image/jpeg"
+
+// deriveKey derives a hex-encoded key for one purpose from secret with HKDF-SHA256,
+// so a fallback key never exposes or reuses the secret itself
+func deriveKey(secret, label string) string {
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, label, sha256.Size)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to derive %s key: %v", label, err))
+	}
+	return hex.EncodeToString(key)
+}
+
+// splitList splits a comma-separated value, dropping blanks
+func splitList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// parseFloat parses a numeric environment value, panicking with the variable name if it is invalid
+func parseFloat(name, s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid %s: %v", name, err))
+	}
+	return f
+}
+
+// parseLocales parses a comma-separated list of language tags into canonical form
+func parseLocales(s string) []string {
+	var locales []string
+	for _, part := range splitList(s) {
+		tag, err := locale.Parse(part)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid SUPPORTED_LOCALES: %v", err))
+		}
+		locales = append(locales, tag)
+	}
+	return locales
+}
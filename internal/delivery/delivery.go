@@ -0,0 +1,81 @@
+// Package delivery parses delivery-event webhooks from email providers (deliveries, bounces and complaints).
+// Each provider has an Adapter that authenticates the request and maps its payload to Events.
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Providers
+const (
+	ProviderSES      = "ses"
+	ProviderPostmark = "postmark"
+	ProviderMailgun  = "mailgun"
+)
+
+// MetadataEmailID is the metadata key (SES tag, Postmark metadata, Mailgun user variable) or,
+// as X-Email-ID, the header that carries messaging.emails.id through the provider
+const (
+	MetadataEmailID = "email_id"
+	HeaderEmailID   = "X-Email-ID"
+)
+
+// EventType is the kind of delivery event
+type EventType string
+
+// Event types
+const (
+	EventDelivered EventType = "delivered"
+	EventBounce    EventType = "bounce"
+	EventComplaint EventType = "complaint"
+)
+
+// ErrUnauthorized is returned when a webhook request's signature or credentials do not check out
+var ErrUnauthorized = errors.New("webhook signature verification failed")
+
+// ErrInvalidPayload is returned when a webhook body cannot be parsed
+var ErrInvalidPayload = errors.New("invalid webhook payload")
+
+// Event is one delivery outcome for one recipient
+type Event struct {
+	// ID identifies the event at the provider, so a redelivered webhook can be recognized (empty if unknown)
+	ID   string
+	Type EventType
+	// EmailID is the email the event belongs to (0 if the provider payload does not carry one)
+	EmailID   int64
+	Recipient string
+	// MessageID is the provider's message ID, for logs
+	MessageID string
+	// Permanent marks hard bounces; transient bounces may still be delivered by the provider
+	Permanent   bool
+	Description string
+	OccurredAt  time.Time
+}
+
+// Adapter authenticates and parses one provider's webhook requests.
+// Errors wrap ErrUnauthorized or ErrInvalidPayload when the request itself is at fault.
+type Adapter interface {
+	Provider() string
+	Parse(r *http.Request, body []byte) ([]Event, error)
+}
+
+// parseEmailID reads an email ID from provider metadata (0 if absent or malformed)
+func parseEmailID(value string) int64 {
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// parseTime parses an RFC3339 timestamp, falling back to now for missing or malformed values
+func parseTime(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t
+	}
+	return time.Now()
+}
@@ -0,0 +1,287 @@
+package delivery
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostmark_Parse(t *testing.T) {
+	adapter := NewPostmark("hook", "s3cret")
+
+	tests := []struct {
+		name          string
+		body          string
+		wantType      EventType
+		wantPermanent bool
+	}{
+		{"hard bounce", `{"RecordType":"Bounce","Type":"HardBounce","Email":"gone@example.com","Description":"Unknown user","MessageID":"m-1","Metadata":{"email_id":"42"}}`, EventBounce, true},
+		{"soft bounce", `{"RecordType":"Bounce","Type":"SoftBounce","Email":"full@example.com","Metadata":{"email_id":"42"}}`, EventBounce, false},
+		{"delivery", `{"RecordType":"Delivery","Recipient":"jane@example.com","DeliveredAt":"2026-01-02T10:00:00Z","Metadata":{"email_id":"42"}}`, EventDelivered, false},
+		{"complaint", `{"RecordType":"SpamComplaint","Email":"jane@example.com","Metadata":{"email_id":"42"}}`, EventComplaint, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.SetBasicAuth("hook", "s3cret")
+
+			events, err := adapter.Parse(req, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("expected one event, got %d", len(events))
+			}
+			if events[0].Type != tt.wantType || events[0].Permanent != tt.wantPermanent || events[0].EmailID != 42 {
+				t.Errorf("unexpected event %+v", events[0])
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.SetBasicAuth("hook", "wrong")
+	if _, err := adapter.Parse(req, []byte(`{"RecordType":"Delivery"}`)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for wrong credentials, got %v", err)
+	}
+}
+
+func TestPostmark_EventID(t *testing.T) {
+	adapter := NewPostmark("hook", "s3cret")
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.SetBasicAuth("hook", "s3cret")
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"bounce record id", `{"RecordType":"Bounce","ID":991,"Type":"HardBounce","Email":"gone@example.com","MessageID":"m-1"}`, "991"},
+		{"delivery message and recipient", `{"RecordType":"Delivery","Recipient":"jane@example.com","MessageID":"m-2"}`, "m-2:jane@example.com"},
+		{"no identifiers", `{"RecordType":"Delivery","Recipient":"jane@example.com"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := adapter.Parse(req, []byte(tt.body))
+			if err != nil || len(events) != 1 {
+				t.Fatalf("expected one event, got %+v, %v", events, err)
+			}
+			if events[0].ID != tt.want {
+				t.Errorf("ID = %q, want %q", events[0].ID, tt.want)
+			}
+		})
+	}
+}
+
+func mailgunBody(t *testing.T, key string, timestamp int64, event string) []byte {
+	t.Helper()
+	token := "tok-123"
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(fmt.Sprintf("%d%s", timestamp, token)))
+	body, err := json.Marshal(map[string]any{
+		"signature": map[string]string{
+			"timestamp": fmt.Sprint(timestamp),
+			"token":     token,
+			"signature": hex.EncodeToString(mac.Sum(nil)),
+		},
+		"event-data": map[string]any{
+			"id":             "evt-" + event,
+			"event":          event,
+			"severity":       "permanent",
+			"recipient":      "gone@example.com",
+			"timestamp":      float64(timestamp) + 0.5,
+			"user-variables": map[string]any{"email_id": 7},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestMailgun_Parse(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	adapter := NewMailgun("signing-key", 15*time.Minute)
+	adapter.now = func() time.Time { return now }
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	events, err := adapter.Parse(req, mailgunBody(t, "signing-key", now.Unix(), "failed"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventBounce || !events[0].Permanent || events[0].EmailID != 7 || events[0].ID != "evt-failed" {
+		t.Errorf("unexpected events %+v", events)
+	}
+
+	if events, err := adapter.Parse(req, mailgunBody(t, "signing-key", now.Unix(), "opened")); err != nil || len(events) != 0 {
+		t.Errorf("expected untracked events to be ignored, got %+v, %v", events, err)
+	}
+	if _, err := adapter.Parse(req, mailgunBody(t, "other-key", now.Unix(), "failed")); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a wrong key, got %v", err)
+	}
+	stale := now.Add(-time.Hour).Unix()
+	if _, err := adapter.Parse(req, mailgunBody(t, "signing-key", stale, "failed")); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a replayed signature, got %v", err)
+	}
+}
+
+const (
+	testTopicARN = "arn:aws:sns:eu-west-1:123456789012:ses-events"
+	testCertURL  = "https://sns.eu-west-1.amazonaws.com/SimpleNotificationService-test.pem"
+)
+
+// newTestSES returns an SES adapter whose fetches are served from memory, and a signer for SNS messages
+func newTestSES(t *testing.T) (*SES, func(msg *snsMessage), *[]string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	var fetched []string
+	adapter := NewSES([]string{testTopicARN}, time.Second)
+	adapter.fetch = func(_ context.Context, rawURL string) ([]byte, error) {
+		fetched = append(fetched, rawURL)
+		if rawURL == testCertURL {
+			return certPEM, nil
+		}
+		return []byte("<ConfirmSubscriptionResponse/>"), nil
+	}
+
+	sign := func(msg *snsMessage) {
+		msg.TopicArn = testTopicARN
+		msg.SignatureVersion = "2"
+		msg.SigningCertURL = testCertURL
+		digest := sha256.Sum256([]byte(snsStringToSign(msg)))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.Signature = base64.StdEncoding.EncodeToString(sig)
+	}
+	return adapter, sign, &fetched
+}
+
+func parseSNS(t *testing.T, adapter *SES, msg *snsMessage) ([]Event, error) {
+	t.Helper()
+	body, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return adapter.Parse(httptest.NewRequest(http.MethodPost, "/", nil), body)
+}
+
+func TestSES_Notification(t *testing.T) {
+	adapter, sign, _ := newTestSES(t)
+
+	msg := &snsMessage{
+		Type:      "Notification",
+		MessageID: "sns-1",
+		Timestamp: "2026-03-01T12:00:00.000Z",
+		Message: `{"notificationType":"Bounce","mail":{"messageId":"ses-1","tags":{"email_id":["15"]}},
+			"bounce":{"bounceType":"Permanent","bounceSubType":"General","timestamp":"2026-03-01T11:59:00.000Z",
+			"bouncedRecipients":[{"emailAddress":"a@example.com","diagnosticCode":"550 5.1.1"},{"emailAddress":"b@example.com"}]}}`,
+	}
+	sign(msg)
+
+	events, err := parseSNS(t, adapter, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected an event per recipient, got %d", len(events))
+	}
+	if events[0].Type != EventBounce || !events[0].Permanent || events[0].EmailID != 15 || events[0].Recipient != "a@example.com" {
+		t.Errorf("unexpected event %+v", events[0])
+	}
+	if events[0].ID != "sns-1:a@example.com" || events[1].ID != "sns-1:b@example.com" {
+		t.Errorf("expected per-recipient event IDs, got %q and %q", events[0].ID, events[1].ID)
+	}
+
+	// Any change after signing breaks the signature
+	msg.Message = strings.Replace(msg.Message, "Permanent", "Transient", 1)
+	if _, err := parseSNS(t, adapter, msg); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a tampered message, got %v", err)
+	}
+}
+
+func TestSES_EmailIDHeader(t *testing.T) {
+	events, err := parseSESNotification("sns-3", `{"eventType":"Delivery","mail":{"headers":[{"name":"x-email-id","value":"9"}]},
+		"delivery":{"recipients":["jane@example.com"]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventDelivered || events[0].EmailID != 9 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestSES_SubscriptionConfirmation(t *testing.T) {
+	adapter, sign, fetched := newTestSES(t)
+
+	msg := &snsMessage{
+		Type:         "SubscriptionConfirmation",
+		MessageID:    "sns-2",
+		Token:        "confirm-token",
+		Timestamp:    "2026-03-01T12:00:00.000Z",
+		Message:      "You have chosen to subscribe",
+		SubscribeURL: "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=confirm-token",
+	}
+	sign(msg)
+
+	events, err := parseSNS(t, adapter, msg)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected no events, got %+v, %v", events, err)
+	}
+	if len(*fetched) != 2 || (*fetched)[1] != msg.SubscribeURL {
+		t.Errorf("expected the subscription to be confirmed, fetched %v", *fetched)
+	}
+}
+
+func TestSES_Rejects(t *testing.T) {
+	adapter, sign, fetched := newTestSES(t)
+
+	unknownTopic := &snsMessage{Type: "Notification", Message: "{}"}
+	sign(unknownTopic)
+	unknownTopic.TopicArn = "arn:aws:sns:eu-west-1:999999999999:other"
+	if _, err := parseSNS(t, adapter, unknownTopic); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for an unknown topic, got %v", err)
+	}
+
+	foreignCert := &snsMessage{Type: "Notification", Message: "{}"}
+	sign(foreignCert)
+	foreignCert.SigningCertURL = "https://attacker.example.com/cert.pem"
+	if _, err := parseSNS(t, adapter, foreignCert); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a non-SNS certificate url, got %v", err)
+	}
+	if len(*fetched) != 0 {
+		t.Errorf("expected nothing to be fetched, got %v", *fetched)
+	}
+}
@@ -0,0 +1,131 @@
+package delivery
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailgun parses Mailgun event webhooks, which are signed with HMAC-SHA256 of timestamp and token
+// using the domain's webhook signing key
+type Mailgun struct {
+	signingKey []byte
+	// maxAge rejects signatures older than this, so captured requests cannot be replayed later
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// NewMailgun creates a Mailgun adapter
+func NewMailgun(signingKey string, maxAge time.Duration) *Mailgun {
+	return &Mailgun{signingKey: []byte(signingKey), maxAge: maxAge, now: time.Now}
+}
+
+type mailgunPayload struct {
+	Signature struct {
+		Timestamp string `json:"timestamp"`
+		Token     string `json:"token"`
+		Signature string `json:"signature"`
+	} `json:"signature"`
+	EventData struct {
+		ID            string         `json:"id"`
+		Event         string         `json:"event"`
+		Severity      string         `json:"severity"`
+		Reason        string         `json:"reason"`
+		Recipient     string         `json:"recipient"`
+		Timestamp     float64        `json:"timestamp"`
+		UserVariables map[string]any `json:"user-variables"`
+		Message       struct {
+			Headers struct {
+				MessageID string `json:"message-id"`
+			} `json:"headers"`
+		} `json:"message"`
+		DeliveryStatus struct {
+			Description string `json:"description"`
+			Message     string `json:"message"`
+		} `json:"delivery-status"`
+	} `json:"event-data"`
+}
+
+// Provider implements Adapter
+func (m *Mailgun) Provider() string {
+	return ProviderMailgun
+}
+
+// Parse implements Adapter. Events other than delivered, failed and complained yield no events.
+func (m *Mailgun) Parse(_ *http.Request, body []byte) ([]Event, error) {
+	var payload mailgunPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if err := m.verify(payload.Signature.Timestamp, payload.Signature.Token, payload.Signature.Signature); err != nil {
+		return nil, err
+	}
+
+	data := payload.EventData
+	event := Event{
+		ID:        data.ID,
+		Recipient: data.Recipient,
+		MessageID: data.Message.Headers.MessageID,
+	}
+	if value, ok := data.UserVariables[MetadataEmailID]; ok {
+		event.EmailID = parseEmailID(fmt.Sprint(value))
+	}
+	if data.Timestamp > 0 {
+		sec, frac := math.Modf(data.Timestamp)
+		event.OccurredAt = time.Unix(int64(sec), int64(frac*1e9))
+	} else {
+		event.OccurredAt = time.Now()
+	}
+
+	switch data.Event {
+	case "delivered":
+		event.Type = EventDelivered
+		event.Description = data.DeliveryStatus.Description
+	case "failed":
+		event.Type = EventBounce
+		event.Permanent = data.Severity == "permanent"
+		event.Description = firstNonEmpty(data.DeliveryStatus.Description, data.DeliveryStatus.Message, data.Reason)
+	case "complained":
+		event.Type = EventComplaint
+	default:
+		return nil, nil
+	}
+	return []Event{event}, nil
+}
+
+// verify checks the signature and that its timestamp is within maxAge
+func (m *Mailgun) verify(timestamp, token, signature string) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || token == "" {
+		return fmt.Errorf("%w: missing signature", ErrUnauthorized)
+	}
+	if age := m.now().Sub(time.Unix(sec, 0)); age > m.maxAge || age < -m.maxAge {
+		return fmt.Errorf("%w: signature timestamp outside %s", ErrUnauthorized, m.maxAge)
+	}
+
+	mac := hmac.New(sha256.New, m.signingKey)
+	mac.Write([]byte(timestamp + token))
+	expected := mac.Sum(nil)
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, expected) {
+		return fmt.Errorf("%w: signature mismatch", ErrUnauthorized)
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first value that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
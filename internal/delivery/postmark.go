@@ -0,0 +1,95 @@
+package delivery
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// postmarkHardBounceTypes are the Postmark bounce types that mean the address will never accept mail
+var postmarkHardBounceTypes = map[string]bool{
+	"HardBounce":      true,
+	"BadEmailAddress": true,
+}
+
+// Postmark parses Postmark bounce, delivery and spam complaint webhooks.
+// Postmark does not sign webhooks; the webhook URL carries HTTP basic auth credentials instead.
+type Postmark struct {
+	username string
+	password string
+}
+
+// NewPostmark creates a Postmark adapter that accepts requests with the given basic auth credentials
+func NewPostmark(username, password string) *Postmark {
+	return &Postmark{username: username, password: password}
+}
+
+// postmarkPayload covers the Bounce, Delivery and SpamComplaint record types
+type postmarkPayload struct {
+	ID          int64             `json:"ID"`
+	RecordType  string            `json:"RecordType"`
+	Type        string            `json:"Type"`
+	MessageID   string            `json:"MessageID"`
+	Email       string            `json:"Email"`
+	Recipient   string            `json:"Recipient"`
+	Description string            `json:"Description"`
+	Details     string            `json:"Details"`
+	BouncedAt   string            `json:"BouncedAt"`
+	DeliveredAt string            `json:"DeliveredAt"`
+	Metadata    map[string]string `json:"Metadata"`
+}
+
+// Provider implements Adapter
+func (p *Postmark) Provider() string {
+	return ProviderPostmark
+}
+
+// Parse implements Adapter. Record types other than bounces, deliveries and complaints yield no events.
+func (p *Postmark) Parse(r *http.Request, body []byte) ([]Event, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok ||
+		subtle.ConstantTimeCompare([]byte(username), []byte(p.username)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(password), []byte(p.password)) != 1 {
+		return nil, fmt.Errorf("%w: invalid basic auth credentials", ErrUnauthorized)
+	}
+
+	var payload postmarkPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	event := Event{
+		EmailID:   parseEmailID(payload.Metadata[MetadataEmailID]),
+		MessageID: payload.MessageID,
+	}
+	switch payload.RecordType {
+	case "Delivery":
+		event.Type = EventDelivered
+		event.Recipient = payload.Recipient
+		event.Description = payload.Details
+		event.OccurredAt = parseTime(payload.DeliveredAt)
+	case "Bounce":
+		event.Type = EventBounce
+		event.Recipient = payload.Email
+		event.Permanent = postmarkHardBounceTypes[payload.Type]
+		event.Description = strings.TrimSpace(payload.Type + ": " + payload.Description)
+		event.OccurredAt = parseTime(payload.BouncedAt)
+	case "SpamComplaint":
+		event.Type = EventComplaint
+		event.Recipient = payload.Email
+		event.Description = payload.Description
+		event.OccurredAt = parseTime(payload.BouncedAt)
+	default:
+		return nil, nil
+	}
+	// Bounces and complaints carry a record ID; deliveries happen once per message and recipient
+	if payload.ID != 0 {
+		event.ID = strconv.FormatInt(payload.ID, 10)
+	} else if payload.MessageID != "" {
+		event.ID = payload.MessageID + ":" + event.Recipient
+	}
+	return []Event{event}, nil
+}
@@ -0,0 +1,324 @@
+package delivery
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // SNS SignatureVersion 1 signs with SHA1
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snsHostPattern matches the SNS endpoints that serve signing certificates and subscription confirmations
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// maxSNSFetchSize bounds signing certificates and confirmation responses
+const maxSNSFetchSize = 64 << 10
+
+// SES parses Amazon SES notifications delivered through an SNS HTTPS subscription.
+// Messages are verified against the SNS signing certificate and must come from an allowed topic;
+// subscription confirmations are confirmed automatically.
+type SES struct {
+	topics map[string]bool
+	fetch  func(ctx context.Context, rawURL string) ([]byte, error)
+
+	mu    sync.Mutex
+	certs map[string]*rsa.PublicKey
+}
+
+// NewSES creates an SES adapter accepting messages from the given SNS topic ARNs
+func NewSES(topicARNs []string, timeout time.Duration) *SES {
+	topics := make(map[string]bool, len(topicARNs))
+	for _, arn := range topicARNs {
+		topics[arn] = true
+	}
+	client := &http.Client{Timeout: timeout}
+	return &SES{
+		topics: topics,
+		fetch: func(ctx context.Context, rawURL string) ([]byte, error) {
+			return httpGet(ctx, client, rawURL)
+		},
+		certs: make(map[string]*rsa.PublicKey),
+	}
+}
+
+// snsMessage is the SNS HTTP(S) envelope
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+// sesNotification covers SES notifications (notificationType) and event publishing (eventType)
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Mail             struct {
+		MessageID string `json:"messageId"`
+		Headers   []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"headers"`
+		Tags map[string][]string `json:"tags"`
+	} `json:"mail"`
+	Bounce *struct {
+		BounceType        string         `json:"bounceType"`
+		BounceSubType     string         `json:"bounceSubType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+		Timestamp         string         `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplainedRecipients  []sesRecipient `json:"complainedRecipients"`
+		ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+		Timestamp             string         `json:"timestamp"`
+	} `json:"complaint"`
+	Delivery *struct {
+		Recipients   []string `json:"recipients"`
+		SMTPResponse string   `json:"smtpResponse"`
+		Timestamp    string   `json:"timestamp"`
+	} `json:"delivery"`
+}
+
+// Provider implements Adapter
+func (s *SES) Provider() string {
+	return ProviderSES
+}
+
+// Parse implements Adapter
+func (s *SES) Parse(r *http.Request, body []byte) ([]Event, error) {
+	var msg snsMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if !s.topics[msg.TopicArn] {
+		return nil, fmt.Errorf("%w: topic %q is not allowed", ErrUnauthorized, msg.TopicArn)
+	}
+	if err := s.verify(r.Context(), &msg); err != nil {
+		return nil, err
+	}
+
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		if err := validateSNSURL(msg.SubscribeURL); err != nil {
+			return nil, fmt.Errorf("%w: subscribe url: %v", ErrInvalidPayload, err)
+		}
+		if _, err := s.fetch(r.Context(), msg.SubscribeURL); err != nil {
+			return nil, fmt.Errorf("failed to confirm SNS subscription: %w", err)
+		}
+		return nil, nil
+	case "Notification":
+		return parseSESNotification(msg.MessageID, msg.Message)
+	default:
+		return nil, nil
+	}
+}
+
+// parseSESNotification maps an SES notification to one event per recipient, identified by the SNS
+// message ID and the recipient
+func parseSESNotification(snsMessageID, message string) ([]Event, error) {
+	var n sesNotification
+	if err := json.Unmarshal([]byte(message), &n); err != nil {
+		return nil, fmt.Errorf("%w: ses notification: %v", ErrInvalidPayload, err)
+	}
+
+	base := Event{EmailID: sesEmailID(&n), MessageID: n.Mail.MessageID}
+	var events []Event
+	kind := n.NotificationType
+	if kind == "" {
+		kind = n.EventType
+	}
+	switch {
+	case kind == "Bounce" && n.Bounce != nil:
+		for _, rcpt := range n.Bounce.BouncedRecipients {
+			event := base
+			event.Type = EventBounce
+			event.Recipient = rcpt.EmailAddress
+			event.ID = snsMessageID + ":" + rcpt.EmailAddress
+			event.Permanent = n.Bounce.BounceType == "Permanent"
+			event.Description = strings.TrimSpace(n.Bounce.BounceType + "/" + n.Bounce.BounceSubType + ": " + rcpt.DiagnosticCode)
+			event.OccurredAt = parseTime(n.Bounce.Timestamp)
+			events = append(events, event)
+		}
+	case kind == "Complaint" && n.Complaint != nil:
+		for _, rcpt := range n.Complaint.ComplainedRecipients {
+			event := base
+			event.Type = EventComplaint
+			event.Recipient = rcpt.EmailAddress
+			event.ID = snsMessageID + ":" + rcpt.EmailAddress
+			event.Description = n.Complaint.ComplaintFeedbackType
+			event.OccurredAt = parseTime(n.Complaint.Timestamp)
+			events = append(events, event)
+		}
+	case kind == "Delivery" && n.Delivery != nil:
+		for _, rcpt := range n.Delivery.Recipients {
+			event := base
+			event.Type = EventDelivered
+			event.Recipient = rcpt
+			event.ID = snsMessageID + ":" + rcpt
+			event.Description = n.Delivery.SMTPResponse
+			event.OccurredAt = parseTime(n.Delivery.Timestamp)
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// sesEmailID reads the email ID from the email_id message tag or the X-Email-ID header
+func sesEmailID(n *sesNotification) int64 {
+	if values := n.Mail.Tags[MetadataEmailID]; len(values) > 0 {
+		return parseEmailID(values[0])
+	}
+	for _, header := range n.Mail.Headers {
+		if strings.EqualFold(header.Name, HeaderEmailID) {
+			return parseEmailID(header.Value)
+		}
+	}
+	return 0
+}
+
+// verify checks the SNS signature against the certificate at SigningCertURL
+func (s *SES) verify(ctx context.Context, msg *snsMessage) error {
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("%w: unsupported signature version %q", ErrUnauthorized, msg.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrUnauthorized)
+	}
+	if err := validateSNSURL(msg.SigningCertURL); err != nil {
+		return fmt.Errorf("%w: signing certificate url: %v", ErrUnauthorized, err)
+	}
+	key, err := s.signingKey(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(snsStringToSign(msg))) //nolint:gosec // required by SignatureVersion 1
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(snsStringToSign(msg)))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return fmt.Errorf("%w: signature mismatch", ErrUnauthorized)
+	}
+	return nil
+}
+
+// snsStringToSign builds the canonical string SNS signs for the message type
+func snsStringToSign(msg *snsMessage) string {
+	var b strings.Builder
+	add := func(name, value string) {
+		b.WriteString(name + "\n" + value + "\n")
+	}
+	add("Message", msg.Message)
+	add("MessageId", msg.MessageID)
+	if msg.Type == "Notification" {
+		if msg.Subject != "" {
+			add("Subject", msg.Subject)
+		}
+	} else {
+		add("SubscribeURL", msg.SubscribeURL)
+	}
+	add("Timestamp", msg.Timestamp)
+	if msg.Type != "Notification" {
+		add("Token", msg.Token)
+	}
+	add("TopicArn", msg.TopicArn)
+	add("Type", msg.Type)
+	return b.String()
+}
+
+// signingKey returns the RSA key of the certificate at certURL, fetching it on first use
+func (s *SES) signingKey(ctx context.Context, certURL string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	key, ok := s.certs[certURL]
+	s.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	data, err := s.fetch(ctx, certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SNS signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("SNS signing certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SNS signing certificate: %w", err)
+	}
+	key, ok = cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("SNS signing certificate does not hold an RSA key")
+	}
+
+	s.mu.Lock()
+	s.certs[certURL] = key
+	s.mu.Unlock()
+	return key, nil
+}
+
+// validateSNSURL only allows HTTPS URLs on SNS hosts, so a forged message cannot point us elsewhere
+func validateSNSURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || !snsHostPattern.MatchString(u.Hostname()) || u.Port() != "" {
+		return fmt.Errorf("%q is not an SNS endpoint", rawURL)
+	}
+	return nil
+}
+
+// httpGet fetches a URL, requiring 200 and bounding the response size
+func httpGet(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", rawURL, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSNSFetchSize))
+}
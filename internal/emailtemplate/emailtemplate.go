@@ -0,0 +1,96 @@
+// Package emailtemplate parses and renders database-managed email templates.
+// Subjects and text bodies use text/template; HTML bodies use html/template so data is escaped.
+package emailtemplate
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// Rendered is the output of rendering a template with request data
+type Rendered struct {
+	Subject string
+	HTML    string
+	// Text is empty when the template has no text body
+	Text string
+}
+
+// Validate parses every part of a template and reports the first syntax error
+func Validate(subject, html string, text *string) error {
+	if _, err := parseText("subject", subject); err != nil {
+		return err
+	}
+	if _, err := parseHTML(html); err != nil {
+		return err
+	}
+	if text != nil {
+		if _, err := parseText("text", *text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render executes a template with data. Keys absent from data render as empty strings.
+func Render(subject, html string, text *string, data map[string]string) (Rendered, error) {
+	var out Rendered
+
+	subjectTmpl, err := parseText("subject", subject)
+	if err != nil {
+		return out, err
+	}
+	if out.Subject, err = execute(subjectTmpl, data); err != nil {
+		return out, fmt.Errorf("render subject: %w", err)
+	}
+
+	htmlTmpl, err := parseHTML(html)
+	if err != nil {
+		return out, err
+	}
+	var buf bytes.Buffer
+	if err := htmlTmpl.Execute(&buf, data); err != nil {
+		return out, fmt.Errorf("render html: %w", err)
+	}
+	out.HTML = buf.String()
+
+	if text != nil {
+		textTmpl, err := parseText("text", *text)
+		if err != nil {
+			return out, err
+		}
+		if out.Text, err = execute(textTmpl, data); err != nil {
+			return out, fmt.Errorf("render text: %w", err)
+		}
+	}
+
+	return out, nil
+}
+
+// parseText parses a plain-text template part
+func parseText(name, body string) (*texttemplate.Template, error) {
+	tmpl, err := texttemplate.New(name).Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// parseHTML parses the HTML template part
+func parseHTML(body string) (*htmltemplate.Template, error) {
+	tmpl, err := htmltemplate.New("html").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid html template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// execute runs a text template into a string
+func execute(tmpl *texttemplate.Template, data map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
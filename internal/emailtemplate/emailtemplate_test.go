@@ -0,0 +1,76 @@
+package emailtemplate
+
+import (
+	"strings"
+	"testing"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRender(t *testing.T) {
+	out, err := Render(
+		"Welcome, {{.username}}",
+		`<p>Hi {{.username}}, <a href="{{.url}}">open</a></p>`,
+		strPtr("Hi {{.username}}, open {{.url}}"),
+		map[string]string{"username": "<b>Ann</b>", "url": "https://example.com/?a=1&b=2"},
+	)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if out.Subject != "Welcome, <b>Ann</b>" {
+		t.Errorf("subject = %q, text templates must not escape", out.Subject)
+	}
+	if !strings.Contains(out.HTML, "Hi &lt;b&gt;Ann&lt;/b&gt;") {
+		t.Errorf("html = %q, expected data to be escaped", out.HTML)
+	}
+	if out.Text != "Hi <b>Ann</b>, open https://example.com/?a=1&b=2" {
+		t.Errorf("text = %q", out.Text)
+	}
+}
+
+func TestRender_MissingKeyIsEmpty(t *testing.T) {
+	out, err := Render("Hello {{.name}}!", "<p>{{.name}}</p>", nil, map[string]string{})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if out.Subject != "Hello !" {
+		t.Errorf("subject = %q, want %q", out.Subject, "Hello !")
+	}
+	if out.Text != "" {
+		t.Errorf("text = %q, want empty without a text body", out.Text)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		html    string
+		text    *string
+		wantErr string
+	}{
+		{"valid", "Hi {{.a}}", "<p>{{.a}}</p>", strPtr("{{.a}}"), ""},
+		{"bad subject", "Hi {{.a", "<p></p>", nil, "subject"},
+		{"bad html", "Hi", "<p>{{if .a}}</p>", nil, "html"},
+		{"bad text", "Hi", "<p></p>", strPtr("{{end}}"), "text"},
+		{"unknown function", "{{upper .a}}", "<p></p>", nil, "subject"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.subject, tt.html, tt.text)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
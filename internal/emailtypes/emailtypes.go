@@ -0,0 +1,209 @@
+// Package emailtypes describes the email types the shared renderer supports and the data each one needs.
+// Shared types are discovered from the renderer's templates; types portfolio-common does not render yet
+// are registered here with their own embedded templates.
+package emailtypes
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	"github.com/GunarsK-portfolio/portfolio-common/renderer"
+	"github.com/GunarsK-portfolio/portfolio-common/renderer/templates"
+)
+
+// Type describes one email type accepted by POST /emails
+type Type struct {
+	Name string `json:"type"`
+	// Subject is empty for types whose subject is supplied by the caller
+	Subject      string   `json:"subject,omitempty"`
+	RequiredKeys []string `json:"required_keys"`
+	// Placeholders lists every data key the template references, required or not
+	Placeholders []string `json:"placeholders"`
+}
+
+// requiredKeys mirrors the required keys of the renderer's registry, which portfolio-common does not
+// export. A shared type missing here is still supported and lists no required keys (the renderer
+// enforces its own); emailtypes_test.go checks the two against each other.
+var requiredKeys = map[string][]string{
+	models.EmailTypeEmailVerification: {"username", "verify_url"},
+	models.EmailTypePasswordReset:     {"username", "reset_url"},
+	models.EmailTypeContactForm:       {"name", "email", "subject", "message", "submitted_at", "id"},
+}
+
+// EmailTypeContactAck acknowledges a contact form submission to its sender
+const EmailTypeContactAck = "contact_ack"
+
+// localType is a type rendered from this package's templates instead of the shared renderer
+type localType struct {
+	subject      string
+	requiredKeys []string
+}
+
+// localTypes move to portfolio-common once other services need them
+var localTypes = map[string]localType{
+	EmailTypeContactAck: {subject: "We received your message", requiredKeys: []string{"name"}},
+}
+
+//go:embed templates/*.html
+var localFS embed.FS
+
+// catalog is built once at init from the renderer and its embedded templates
+var catalog = map[string]Type{}
+
+// localTemplates holds the parsed templates of localTypes
+var localTemplates = map[string]*template.Template{}
+
+func init() {
+	// Every shared template the renderer has a subject for is a supported type
+	shared, _ := fs.Glob(templates.FS, "*.html")
+	for _, file := range shared {
+		name := strings.TrimSuffix(file, ".html")
+		subject, ok := renderer.SubjectForType(name)
+		if !ok {
+			continue
+		}
+		keys := requiredKeys[name]
+		if keys == nil {
+			keys = []string{}
+		}
+		if _, err := register(templates.FS, name, subject, keys); err != nil {
+			slog.Warn("Skipping shared email type with unparsable template", "type", name, "error", err)
+		}
+	}
+
+	local, err := fs.Sub(localFS, "templates")
+	if err != nil {
+		panic(fmt.Sprintf("emailtypes: %v", err))
+	}
+	for name, lt := range localTypes {
+		tmpl, err := register(local, name, lt.subject, lt.requiredKeys)
+		if err != nil {
+			panic(fmt.Sprintf("emailtypes: %v", err))
+		}
+		localTemplates[name] = tmpl.Option("missingkey=zero")
+	}
+}
+
+// register parses a type's template and adds it to the catalog
+func register(fsys fs.FS, name, subject string, keys []string) (*template.Template, error) {
+	tmpl, err := template.ParseFS(fsys, name+".html")
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+	catalog[name] = Type{
+		Name:         name,
+		Subject:      subject,
+		RequiredKeys: keys,
+		Placeholders: templatePlaceholders(tmpl),
+	}
+	return tmpl, nil
+}
+
+// Render renders the built-in HTML template of a type, from the shared renderer or this package
+func Render(name string, data map[string]string) (string, error) {
+	tmpl, ok := localTemplates[name]
+	if !ok {
+		return renderer.Render(name, data)
+	}
+	if missing := catalog[name].MissingKeys(data); len(missing) > 0 {
+		return "", fmt.Errorf("missing required template key %q for type %s", missing[0], name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("execute template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// All returns every supported email type, sorted by name
+func All() []Type {
+	types := make([]Type, 0, len(catalog))
+	for _, t := range catalog {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Lookup returns the description of an email type. The bool reports whether the type is supported.
+func Lookup(name string) (Type, bool) {
+	t, ok := catalog[name]
+	return t, ok
+}
+
+// MissingKeys returns the required keys absent or empty in data, in declaration order
+func (t Type) MissingKeys(data map[string]string) []string {
+	var missing []string
+	for _, key := range t.RequiredKeys {
+		if data[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// templatePlaceholders lists the top-level {{.key}} fields referenced by a type's template
+func templatePlaceholders(tmpl *template.Template) []string {
+	seen := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectFields(t.Tree.Root, seen)
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// collectFields walks a template parse tree and records the first identifier of every field
+func collectFields(node parse.Node, seen map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, seen)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, seen)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				collectFields(arg, seen)
+			}
+		}
+	case *parse.FieldNode:
+		if len(n.Ident) > 0 {
+			seen[n.Ident[0]] = true
+		}
+	case *parse.IfNode:
+		collectFields(n.Pipe, seen)
+		collectFields(n.List, seen)
+		collectFields(n.ElseList, seen)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, seen)
+		collectFields(n.List, seen)
+		collectFields(n.ElseList, seen)
+	case *parse.WithNode:
+		collectFields(n.Pipe, seen)
+		collectFields(n.List, seen)
+		collectFields(n.ElseList, seen)
+	}
+}
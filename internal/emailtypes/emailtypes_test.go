@@ -0,0 +1,130 @@
+package emailtypes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	"github.com/GunarsK-portfolio/portfolio-common/renderer"
+)
+
+// fullData returns a payload with every required key of t set
+func fullData(t Type) map[string]string {
+	data := map[string]string{}
+	for _, key := range t.RequiredKeys {
+		data[key] = "value"
+	}
+	return data
+}
+
+func TestRequiredKeys_MatchRenderer(t *testing.T) {
+	for _, typ := range All() {
+		t.Run(typ.Name, func(t *testing.T) {
+			if _, err := Render(typ.Name, fullData(typ)); err != nil {
+				t.Fatalf("render with all required keys failed: %v", err)
+			}
+			for _, key := range typ.RequiredKeys {
+				data := fullData(typ)
+				delete(data, key)
+				if _, err := Render(typ.Name, data); err == nil {
+					t.Errorf("renderer accepted data without %q, catalog says it is required", key)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiredKeys_TypesSupportedByRenderer(t *testing.T) {
+	for name := range requiredKeys {
+		if _, ok := renderer.SubjectForType(name); !ok {
+			t.Errorf("requiredKeys lists %s, which the renderer does not support", name)
+		}
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("requiredKeys lists %s, which is missing from the catalog", name)
+		}
+	}
+}
+
+func TestRequiredKeys_CoverSharedTypes(t *testing.T) {
+	for _, typ := range All() {
+		if _, local := localTypes[typ.Name]; local {
+			continue
+		}
+		if _, ok := requiredKeys[typ.Name]; !ok {
+			t.Errorf("shared type %s has no requiredKeys entry; add it so POST /emails can report missing keys", typ.Name)
+		}
+	}
+}
+
+func TestAll_SortedWithPlaceholders(t *testing.T) {
+	types := All()
+	if want := len(requiredKeys) + len(localTypes); len(types) != want {
+		t.Fatalf("expected %d types, got %d", want, len(types))
+	}
+	for i := 1; i < len(types); i++ {
+		if types[i-1].Name >= types[i].Name {
+			t.Errorf("types not sorted: %q before %q", types[i-1].Name, types[i].Name)
+		}
+	}
+
+	verification, ok := Lookup(models.EmailTypeEmailVerification)
+	if !ok {
+		t.Fatal("expected email_verification to be supported")
+	}
+	if verification.Subject != "Verify your email address" {
+		t.Errorf("subject = %q", verification.Subject)
+	}
+	if want := []string{"username", "verify_url"}; !reflect.DeepEqual(verification.Placeholders, want) {
+		t.Errorf("placeholders = %v, want %v", verification.Placeholders, want)
+	}
+}
+
+func TestRender_SharedTypesUseRenderer(t *testing.T) {
+	data := map[string]string{"username": "testuser", "reset_url": "https://example.com/reset"}
+	want, err := renderer.Render(models.EmailTypePasswordReset, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Render(models.EmailTypePasswordReset, data); got != want {
+		t.Error("expected shared types to render through portfolio-common")
+	}
+}
+
+func TestRender_ContactAck(t *testing.T) {
+	typ, ok := Lookup(EmailTypeContactAck)
+	if !ok {
+		t.Fatal("expected contact_ack to be supported")
+	}
+	if typ.Subject != "We received your message" || !reflect.DeepEqual(typ.Placeholders, []string{"name"}) {
+		t.Errorf("unexpected type %+v", typ)
+	}
+
+	html, err := Render(EmailTypeContactAck, map[string]string{"name": "<Jane>"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(html, "Hi &lt;Jane&gt;,") {
+		t.Error("expected escaped name in greeting")
+	}
+}
+
+func TestLookup_Unsupported(t *testing.T) {
+	if _, ok := Lookup(models.EmailType2FACode); ok {
+		t.Error("expected 2fa_code to be unsupported until the renderer has a template")
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("expected unknown type to be unsupported")
+	}
+}
+
+func TestMissingKeys(t *testing.T) {
+	typ, _ := Lookup(models.EmailTypePasswordReset)
+
+	if missing := typ.MissingKeys(map[string]string{"username": "u", "reset_url": "x"}); missing != nil {
+		t.Errorf("expected no missing keys, got %v", missing)
+	}
+	if missing := typ.MissingKeys(map[string]string{"username": ""}); !reflect.DeepEqual(missing, []string{"username", "reset_url"}) {
+		t.Errorf("missing = %v, want [username reset_url]", missing)
+	}
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/messaging-api/internal/storage"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+)
+
+// contactAttachmentField is the multipart field POST /contact reads files from
+const contactAttachmentField = "attachments"
+
+// maxAttachmentFilenameLength matches email_attachments.filename
+const maxAttachmentFilenameLength = 255
+
+// AttachmentLimits bounds uploads on POST /contact and POST /emails
+type AttachmentLimits struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+	MaxCount     int
+	AllowedTypes []string
+	// URLTTL is how long signed download links stay valid
+	URLTTL time.Duration
+}
+
+// sniffedTypes maps a declared content type to the prefix http.DetectContentType reports for a genuine file.
+// Allowed types missing here are accepted on their declared type alone.
+var sniffedTypes = map[string]string{
+	"application/pdf": "application/pdf",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "application/zip",
+	"text/plain": "text/plain",
+	"image/png":  "image/png",
+	"image/jpeg": "image/jpeg",
+	"image/gif":  "image/gif",
+}
+
+// AttachmentInput is a file attached to a SendEmailRequest
+type AttachmentInput struct {
+	Filename    string `json:"filename" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,max=100"`
+	// Content is the file encoded as standard base64
+	Content string `json:"content" binding:"required"`
+}
+
+// AttachmentURLResponse is a signed, time-limited download link
+type AttachmentURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// attachmentUpload is a decoded file awaiting validation and storage
+type attachmentUpload struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// attachmentError is a rejected upload and the status to answer with
+type attachmentError struct {
+	status  int
+	message string
+}
+
+func (e *attachmentError) Error() string {
+	return e.message
+}
+
+// GetAttachmentURL godoc
+// @Summary Get a signed attachment download URL
+// @Description Returns a time-limited link that downloads the attachment without further authentication (admin only)
+// @Tags Emails
+// @Produce json
+// @Param id path int true "Email ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 200 {object} AttachmentURLResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/{id}/attachments/{attachmentId}/url [get]
+func (h *Handler) GetAttachmentURL(c *gin.Context) {
+	emailID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	attachmentID, err := strconv.ParseInt(c.Param("attachmentId"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid attachment ID format")
+		return
+	}
+	if h.attachmentStore == nil {
+		commonhandlers.RespondError(c, http.StatusNotFound, "Attachment not found")
+		return
+	}
+
+	ctx := c.Request.Context()
+	attachment, err := h.repo.GetAttachment(ctx, emailID, attachmentID)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Attachment not found", "Failed to retrieve attachment")
+		return
+	}
+
+	expiresAt := time.Now().Add(h.attachmentLimits.URLTTL).UTC()
+	url, err := h.attachmentStore.SignedURL(ctx, attachment.StorageKey, attachment.Filename, h.attachmentLimits.URLTTL)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to sign attachment URL")
+		return
+	}
+
+	c.JSON(http.StatusOK, AttachmentURLResponse{URL: url, ExpiresAt: expiresAt})
+}
+
+// ServeAttachmentFile godoc
+// @Summary Download an attachment (local storage)
+// @Description Serves a signed link issued by GET /emails/{id}/attachments/{attachmentId}/url when the local storage backend is used
+// @Tags Emails
+// @Produce octet-stream
+// @Param key query string true "Storage key"
+// @Param filename query string true "Download filename"
+// @Param expires query int true "Expiry (Unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /attachments/files [get]
+func (h *Handler) ServeAttachmentFile(c *gin.Context) {
+	server, ok := h.attachmentStore.(storage.SignedFileServer)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	server.ServeSigned(c.Writer, c.Request)
+}
+
+// maxBodyWithoutAttachments caps request bodies when attachments are disabled,
+// and is the headroom for the other fields when they are enabled
+const maxBodyWithoutAttachments = 1 << 20
+
+// limitAttachmentBody caps a request body at the attachment budget plus headroom for the other fields,
+// or at maxBodyWithoutAttachments when attachments are disabled.
+// base64 grows content by a third, so JSON bodies get the larger allowance.
+func (h *Handler) limitAttachmentBody(c *gin.Context, base64Encoded bool) {
+	limit := int64(maxBodyWithoutAttachments)
+	if h.attachmentStore != nil {
+		budget := h.attachmentLimits.MaxTotalSize
+		if base64Encoded {
+			budget = budget/3*4 + 4
+		}
+		limit += budget
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+}
+
+// respondBindError answers a failed bind, with 413 when the body exceeded limitAttachmentBody
+func respondBindError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		commonhandlers.RespondError(c, http.StatusRequestEntityTooLarge, "Request body too large")
+		return
+	}
+	commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+}
+
+// decodeAttachmentInputs decodes base64 attachments from a SendEmailRequest
+func decodeAttachmentInputs(inputs []AttachmentInput) ([]attachmentUpload, error) {
+	uploads := make([]attachmentUpload, 0, len(inputs))
+	for i, in := range inputs {
+		data, err := base64.StdEncoding.DecodeString(in.Content)
+		if err != nil {
+			return nil, &attachmentError{http.StatusBadRequest, fmt.Sprintf("attachments[%d].content is not valid base64", i)}
+		}
+		uploads = append(uploads, attachmentUpload{Filename: in.Filename, ContentType: in.ContentType, Data: data})
+	}
+	return uploads, nil
+}
+
+// readMultipartAttachments reads uploaded files, rejecting oversized ones before reading them
+func (h *Handler) readMultipartAttachments(files []*multipart.FileHeader) ([]attachmentUpload, error) {
+	uploads := make([]attachmentUpload, 0, len(files))
+	for _, fh := range files {
+		if fh.Size > h.attachmentLimits.MaxFileSize {
+			return nil, h.fileTooLarge(fh.Filename)
+		}
+
+		contentType := fh.Header.Get("Content-Type")
+		if contentType == "" || contentType == "application/octet-stream" {
+			contentType = mime.TypeByExtension(filepath.Ext(fh.Filename))
+		}
+
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(f, h.attachmentLimits.MaxFileSize+1))
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, attachmentUpload{Filename: fh.Filename, ContentType: contentType, Data: data})
+	}
+	return uploads, nil
+}
+
+// validateAttachments enforces count, size and content-type limits and normalizes names and types
+func (h *Handler) validateAttachments(uploads []attachmentUpload) error {
+	if len(uploads) == 0 {
+		return nil
+	}
+	if h.attachmentStore == nil {
+		return &attachmentError{http.StatusBadRequest, "Attachments are not enabled"}
+	}
+
+	limits := h.attachmentLimits
+	if len(uploads) > limits.MaxCount {
+		return &attachmentError{http.StatusBadRequest, fmt.Sprintf("At most %d attachments are allowed", limits.MaxCount)}
+	}
+
+	var total int64
+	for i := range uploads {
+		u := &uploads[i]
+		u.Filename = sanitizeFilename(u.Filename)
+		if u.Filename == "" {
+			return &attachmentError{http.StatusBadRequest, "Attachment filename is required"}
+		}
+
+		size := int64(len(u.Data))
+		if size == 0 {
+			return &attachmentError{http.StatusBadRequest, "Attachment " + u.Filename + " is empty"}
+		}
+		if size > limits.MaxFileSize {
+			return h.fileTooLarge(u.Filename)
+		}
+		if total += size; total > limits.MaxTotalSize {
+			return &attachmentError{http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("Attachments exceed the %d byte total limit", limits.MaxTotalSize)}
+		}
+
+		contentType, err := h.checkContentType(u.ContentType, u.Data)
+		if err != nil {
+			return &attachmentError{http.StatusUnsupportedMediaType, "Attachment " + u.Filename + ": " + err.Error()}
+		}
+		u.ContentType = contentType
+	}
+	return nil
+}
+
+// checkContentType normalizes a declared type, checks it is allowed and that the content matches it
+func (h *Handler) checkContentType(declared string, data []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q", declared)
+	}
+
+	allowed := false
+	for _, t := range h.attachmentLimits.AllowedTypes {
+		if strings.EqualFold(t, mediaType) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("content type %s is not allowed", mediaType)
+	}
+
+	if want, ok := sniffedTypes[mediaType]; ok && !strings.HasPrefix(http.DetectContentType(data), want) {
+		return "", fmt.Errorf("content does not match %s", mediaType)
+	}
+	return mediaType, nil
+}
+
+func (h *Handler) fileTooLarge(filename string) error {
+	return &attachmentError{http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("Attachment %s exceeds the %d byte limit", sanitizeFilename(filename), h.attachmentLimits.MaxFileSize)}
+}
+
+// respondAttachmentError answers a rejected upload, logging unexpected failures
+func respondAttachmentError(c *gin.Context, err error) {
+	var attErr *attachmentError
+	if errors.As(err, &attErr) {
+		commonhandlers.RespondError(c, attErr.status, attErr.message)
+		return
+	}
+	commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to read attachments")
+}
+
+// storeAttachments uploads validated files and returns their records for CreateEmail.
+// Blobs already uploaded are removed if a later upload fails.
+func (h *Handler) storeAttachments(c *gin.Context, uploads []attachmentUpload) ([]repository.Attachment, error) {
+	ctx := c.Request.Context()
+	attachments := make([]repository.Attachment, 0, len(uploads))
+	for _, u := range uploads {
+		key, err := storage.NewKey()
+		if err == nil {
+			err = h.attachmentStore.Put(ctx, key, bytes.NewReader(u.Data), int64(len(u.Data)), u.ContentType)
+		}
+		if err != nil {
+			h.discardAttachments(c, attachments)
+			return nil, err
+		}
+		attachments = append(attachments, repository.Attachment{
+			Filename:    u.Filename,
+			ContentType: u.ContentType,
+			SizeBytes:   int64(len(u.Data)),
+			StorageKey:  key,
+		})
+	}
+	return attachments, nil
+}
+
+// discardAttachments removes blobs whose email was never saved. Failures only leave orphaned blobs, so they are logged.
+func (h *Handler) discardAttachments(c *gin.Context, attachments []repository.Attachment) {
+	ctx := context.WithoutCancel(c.Request.Context())
+	for _, a := range attachments {
+		if err := h.attachmentStore.Delete(ctx, a.StorageKey); err != nil {
+			logger.GetLogger(c).Warn("Failed to delete orphaned attachment", "error", err, "key", a.StorageKey)
+		}
+	}
+}
+
+// sanitizeFilename keeps the base name of a client-supplied filename without control characters
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == ".." || name == "/" {
+		return ""
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxAttachmentFilenameLength {
+		ext := []rune(filepath.Ext(name))
+		if len(ext) > 16 {
+			ext = nil
+		}
+		name = string(runes[:maxAttachmentFilenameLength-len(ext)]) + string(ext)
+	}
+	return name
+}
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/messaging-api/internal/storage"
+)
+
+// pdfContent is enough for http.DetectContentType to report a PDF
+var pdfContent = []byte("%PDF-1.4\n%test document\n")
+
+// mockStore keeps blobs in memory
+type mockStore struct {
+	blobs   map[string][]byte
+	deleted []string
+	putErr  error
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{blobs: map[string][]byte{}}
+}
+
+func (s *mockStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *mockStore) Delete(_ context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	delete(s.blobs, key)
+	return nil
+}
+
+func (s *mockStore) SignedURL(_ context.Context, key, _ string, _ time.Duration) (string, error) {
+	return "https://storage.example.com/" + key + "?sig=abc", nil
+}
+
+func testAttachmentLimits() AttachmentLimits {
+	return AttachmentLimits{
+		MaxFileSize:  1024,
+		MaxTotalSize: 1536,
+		MaxCount:     2,
+		AllowedTypes: []string{"application/pdf", "text/plain"},
+		URLTTL:       15 * time.Minute,
+	}
+}
+
+type testFile struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+// multipartContactRequest builds a multipart contact form with the given files
+func multipartContactRequest(t *testing.T, files ...testFile) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for field, value := range map[string]string{
+		"name": "Test User", "email": "test@example.com", "subject": "My CV", "message": "Please see attached",
+	} {
+		if err := mw.WriteField(field, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="attachments"; filename="`+f.name+`"`)
+		header.Set("Content-Type", f.contentType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write(f.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/contact", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func serveRequest(router *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// =============================================================================
+// Contact form attachment Tests
+// =============================================================================
+
+func TestCreateContactMessage_MultipartWithAttachment(t *testing.T) {
+	store := newMockStore()
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithAttachments(store, testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	w := serveRequest(router, multipartContactRequest(t, testFile{"../../cv.pdf", "application/pdf", pdfContent}))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if created == nil || created.Subject != "My CV" {
+		t.Fatalf("expected email from form fields, got %+v", created)
+	}
+	if len(created.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(created.Attachments))
+	}
+	att := created.Attachments[0]
+	if att.Filename != "cv.pdf" || att.ContentType != "application/pdf" || att.SizeBytes != int64(len(pdfContent)) {
+		t.Errorf("unexpected attachment %+v", att)
+	}
+	if !bytes.Equal(store.blobs[att.StorageKey], pdfContent) {
+		t.Error("expected blob stored under the attachment key")
+	}
+}
+
+func TestCreateContactMessage_AttachmentRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  []testFile
+		status int
+		want   string
+	}{
+		{"too_large", []testFile{{"big.txt", "text/plain", bytes.Repeat([]byte("a"), 1025)}}, http.StatusRequestEntityTooLarge, "exceeds the 1024 byte limit"},
+		{"total_too_large", []testFile{
+			{"a.txt", "text/plain", bytes.Repeat([]byte("a"), 1000)},
+			{"b.txt", "text/plain", bytes.Repeat([]byte("b"), 1000)},
+		}, http.StatusRequestEntityTooLarge, "1536 byte total limit"},
+		{"too_many", []testFile{
+			{"a.txt", "text/plain", []byte("a")}, {"b.txt", "text/plain", []byte("b")}, {"c.txt", "text/plain", []byte("c")},
+		}, http.StatusBadRequest, "At most 2 attachments"},
+		{"type_not_allowed", []testFile{{"run.exe", "application/x-msdownload", []byte("MZ")}}, http.StatusUnsupportedMediaType, "not allowed"},
+		{"content_mismatch", []testFile{{"cv.pdf", "application/pdf", []byte("<html>not a pdf</html>")}}, http.StatusUnsupportedMediaType, "does not match"},
+		{"empty", []testFile{{"empty.txt", "text/plain", nil}}, http.StatusBadRequest, "is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			mockRepo := &mockRepository{
+				createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+					t.Error("email should not be created")
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{}, WithAttachments(store, testAttachmentLimits()))
+
+			router := setupTestRouter()
+			router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+			w := serveRequest(router, multipartContactRequest(t, tt.files...))
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected error to mention %q, got %s", tt.want, w.Body.String())
+			}
+			if len(store.blobs) != 0 {
+				t.Errorf("expected nothing stored, got %d blobs", len(store.blobs))
+			}
+		})
+	}
+}
+
+func TestCreateContactMessage_AttachmentsDisabled(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	w := serveRequest(router, multipartContactRequest(t, testFile{"cv.pdf", "application/pdf", pdfContent}))
+
+	if w.Code != http.StatusUnsupportedMediaType || !strings.Contains(w.Body.String(), "not enabled") {
+		t.Errorf("expected 415 attachments not enabled, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateContactMessage_MultipartRejectedWhenAttachmentsDisabled(t *testing.T) {
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			t.Error("expected no email to be created")
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	w := serveRequest(router, multipartContactRequest(t))
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status %d, got %d: %s", http.StatusUnsupportedMediaType, w.Code, w.Body.String())
+	}
+}
+
+func TestCreateContactMessage_BodyCappedWhenAttachmentsDisabled(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	big := strings.Repeat("a", 2<<20)
+	body := `{"name":"Test User","email":"test@example.com","subject":"Hi","message":"` + big + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/contact", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := serveRequest(router, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+}
+
+func TestCreateContactMessage_MultipartWithoutFiles(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithAttachments(newMockStore(), testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	w := serveRequest(router, multipartContactRequest(t))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if created == nil || len(created.Attachments) != 0 {
+		t.Errorf("expected email without attachments, got %+v", created)
+	}
+}
+
+func TestCreateContactMessage_RepositoryErrorDiscardsBlobs(t *testing.T) {
+	store := newMockStore()
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			return errors.New("database error")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithAttachments(store, testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	w := serveRequest(router, multipartContactRequest(t, testFile{"cv.pdf", "application/pdf", pdfContent}))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if len(store.deleted) != 1 || len(store.blobs) != 0 {
+		t.Errorf("expected the uploaded blob to be deleted, deleted=%v remaining=%d", store.deleted, len(store.blobs))
+	}
+}
+
+// =============================================================================
+// SendEmail attachment Tests
+// =============================================================================
+
+func TestSendEmail_Attachments(t *testing.T) {
+	store := newMockStore()
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithAttachments(store, testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"u","verify_url":"https://example.com/v"},` +
+		`"attachments":[{"filename":"invoice.pdf","content_type":"application/pdf","content":"` + base64.StdEncoding.EncodeToString(pdfContent) + `"}]}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if len(created.Attachments) != 1 || created.Attachments[0].Filename != "invoice.pdf" {
+		t.Fatalf("unexpected attachments %+v", created.Attachments)
+	}
+	if !bytes.Equal(store.blobs[created.Attachments[0].StorageKey], pdfContent) {
+		t.Error("expected decoded content stored")
+	}
+}
+
+func TestSendEmail_InvalidAttachment(t *testing.T) {
+	tests := []struct {
+		name       string
+		attachment string
+		status     int
+	}{
+		{"bad_base64", `{"filename":"a.pdf","content_type":"application/pdf","content":"%%%"}`, http.StatusBadRequest},
+		{"missing_filename", `{"content_type":"application/pdf","content":"JVBERi0="}`, http.StatusBadRequest},
+		{"type_not_allowed", `{"filename":"a.zip","content_type":"application/zip","content":"UEsDBA=="}`, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(&mockRepository{}, &mockPublisher{}, WithAttachments(newMockStore(), testAttachmentLimits()))
+
+			router := setupTestRouter()
+			router.POST("/api/v1/emails", handler.SendEmail)
+
+			body := `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"u","verify_url":"https://example.com/v"},"attachments":[` + tt.attachment + `]}`
+			w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSendEmail_BodyTooLarge(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{}, WithAttachments(newMockStore(), testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	huge := strings.Repeat("A", 2<<20)
+	body := `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"u","verify_url":"v"},"attachments":[{"filename":"a.txt","content_type":"text/plain","content":"` + huge + `"}]}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+// =============================================================================
+// Attachment download Tests
+// =============================================================================
+
+func TestGetAttachmentURL_Success(t *testing.T) {
+	mockRepo := &mockRepository{
+		getAttachmentFunc: func(_ context.Context, emailID, attachmentID int64) (*repository.Attachment, error) {
+			if emailID != 3 || attachmentID != 7 {
+				t.Errorf("unexpected lookup email=%d attachment=%d", emailID, attachmentID)
+			}
+			return &repository.Attachment{ID: 7, EmailID: 3, Filename: "cv.pdf", StorageKey: "abc"}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithAttachments(newMockStore(), testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/:id/attachments/:attachmentId/url", handler.GetAttachmentURL)
+
+	before := time.Now()
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/3/attachments/7/url", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp AttachmentURLResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.URL != "https://storage.example.com/abc?sig=abc" {
+		t.Errorf("url = %q", resp.URL)
+	}
+	if resp.ExpiresAt.Before(before.Add(14 * time.Minute)) {
+		t.Errorf("expected expiry ~15m ahead, got %v", resp.ExpiresAt)
+	}
+}
+
+func TestGetAttachmentURL_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		getAttachmentFunc: func(_ context.Context, _, _ int64) (*repository.Attachment, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithAttachments(newMockStore(), testAttachmentLimits()))
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/:id/attachments/:attachmentId/url", handler.GetAttachmentURL)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/3/attachments/99/url", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestServeAttachmentFile_LocalStore(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/api/v1/attachments/files", []byte("test-secret-key-at-least-32-chars!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := storage.NewKey()
+	if err := store.Put(context.Background(), key, bytes.NewReader(pdfContent), int64(len(pdfContent)), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	signed, _ := store.SignedURL(context.Background(), key, "cv.pdf", time.Minute)
+
+	handler := New(&mockRepository{}, &mockPublisher{}, WithAttachments(store, testAttachmentLimits()))
+	router := setupTestRouter()
+	router.GET("/api/v1/attachments/files", handler.ServeAttachmentFile)
+
+	w := performRequest(router, http.MethodGet, strings.TrimPrefix(signed, "http://localhost"), nil)
+
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), pdfContent) {
+		t.Errorf("expected file content, got %d: %q", w.Code, w.Body.String())
+	}
+}
+
+func TestServeAttachmentFile_ObjectStorage(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{}, WithAttachments(newMockStore(), testAttachmentLimits()))
+	router := setupTestRouter()
+	router.GET("/api/v1/attachments/files", handler.ServeAttachmentFile)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/attachments/files?key=abc", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := map[string]string{
+		"cv.pdf":                          "cv.pdf",
+		"../../etc/passwd":                "passwd",
+		`C:\Users\me\brief.docx`:          "brief.docx",
+		"bad\x00name\n.txt":               "badname.txt",
+		"  spaced.txt ":                   "spaced.txt",
+		"..":                              "",
+		"/":                               "",
+		strings.Repeat("a", 300) + ".pdf": strings.Repeat("a", 251) + ".pdf",
+	}
+	for in, want := range tests {
+		if got := sanitizeFilename(in); got != want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
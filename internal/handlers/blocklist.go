@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/blocklist"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+// BlocklistEntryCreate is the body of POST /blocklist.
+// Value is an address, a domain (blocking its subdomains too), a wildcard pattern over the whole
+// address using * and ? (e.g. "*@*.example.com"), or an IP or CIDR range of client IPs.
+type BlocklistEntryCreate struct {
+	Kind   string  `json:"kind" binding:"required,oneof=address domain pattern cidr"`
+	Value  string  `json:"value" binding:"required,max=255"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// BlocklistEntryUpdate is the body of PUT /blocklist/{id}; omitted fields are left unchanged
+type BlocklistEntryUpdate struct {
+	Kind   *string `json:"kind" binding:"omitempty,oneof=address domain pattern cidr"`
+	Value  *string `json:"value" binding:"omitempty,max=255"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// GetBlocklist godoc
+// @Summary List blocklist entries
+// @Description Returns all blocklist entries, newest first. Contact messages from a matching sender or client IP are dropped. Requires emails:read scope.
+// @Tags Blocklist
+// @Produce json
+// @Success 200 {array} repository.BlocklistEntry
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /blocklist [get]
+func (h *Handler) GetBlocklist(c *gin.Context) {
+	entries, err := h.repo.GetBlocklistEntries(c.Request.Context())
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve blocklist")
+		return
+	}
+	if entries == nil {
+		entries = []repository.BlocklistEntry{}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// GetBlocklistEntry godoc
+// @Summary Get blocklist entry by ID
+// @Description Returns a single blocklist entry. Requires emails:read scope.
+// @Tags Blocklist
+// @Produce json
+// @Param id path int true "Blocklist entry ID"
+// @Success 200 {object} repository.BlocklistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /blocklist/{id} [get]
+func (h *Handler) GetBlocklistEntry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	entry, err := h.repo.GetBlocklistEntryByID(c.Request.Context(), id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Blocklist entry not found", "Failed to retrieve blocklist entry")
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// CreateBlocklistEntry godoc
+// @Summary Create a blocklist entry
+// @Description Blocks contact messages from an address, a domain and its subdomains, addresses matching a wildcard pattern, or client IPs in a CIDR range. Takes effect immediately on this instance and within BLOCKLIST_CACHE_TTL on others. Requires emails:edit scope.
+// @Tags Blocklist
+// @Accept json
+// @Produce json
+// @Param entry body BlocklistEntryCreate true "Blocklist entry"
+// @Success 201 {object} repository.BlocklistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /blocklist [post]
+func (h *Handler) CreateBlocklistEntry(c *gin.Context) {
+	var req BlocklistEntryCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	value, err := blocklist.Normalize(req.Kind, req.Value)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry := &repository.BlocklistEntry{Kind: req.Kind, Value: value, Reason: req.Reason}
+	if username := c.GetString("username"); username != "" {
+		entry.CreatedBy = &username
+	}
+	if err := h.repo.CreateBlocklistEntry(c.Request.Context(), entry); err != nil {
+		if errors.Is(err, repository.ErrBlocklistEntryExists) {
+			commonhandlers.RespondError(c, http.StatusConflict, "This sender is already on the blocklist")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create blocklist entry")
+		return
+	}
+
+	h.blocklist.Invalidate()
+	h.recordAction(c, actionBlocklistCreate, auditResourceBlocklistEntry, entry.ID,
+		map[string]interface{}{"kind": entry.Kind, "value": entry.Value})
+
+	setLocationHeader(c, entry.ID)
+	c.JSON(http.StatusCreated, entry)
+}
+
+// UpdateBlocklistEntry godoc
+// @Summary Update a blocklist entry
+// @Description Changes the kind, value or reason of a blocklist entry. Requires emails:edit scope.
+// @Tags Blocklist
+// @Accept json
+// @Produce json
+// @Param id path int true "Blocklist entry ID"
+// @Param entry body BlocklistEntryUpdate true "Fields to change"
+// @Success 200 {object} repository.BlocklistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /blocklist/{id} [put]
+func (h *Handler) UpdateBlocklistEntry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	existing, err := h.repo.GetBlocklistEntryByID(c.Request.Context(), id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Blocklist entry not found", "Failed to retrieve blocklist entry")
+		return
+	}
+
+	var req BlocklistEntryUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Kind != nil {
+		existing.Kind = *req.Kind
+	}
+	if req.Value != nil {
+		existing.Value = *req.Value
+	}
+	if req.Reason != nil {
+		existing.Reason = req.Reason
+	}
+	// The value is re-validated whenever the kind or value changes
+	if existing.Value, err = blocklist.Normalize(existing.Kind, existing.Value); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.repo.UpdateBlocklistEntry(c.Request.Context(), existing); err != nil {
+		if errors.Is(err, repository.ErrBlocklistEntryExists) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Another entry already blocks this sender")
+			return
+		}
+		commonhandlers.HandleRepositoryError(c, err, "Blocklist entry not found", "Failed to update blocklist entry")
+		return
+	}
+
+	h.blocklist.Invalidate()
+	h.recordAction(c, actionBlocklistUpdate, auditResourceBlocklistEntry, id,
+		map[string]interface{}{"kind": existing.Kind, "value": existing.Value})
+
+	c.JSON(http.StatusOK, existing)
+}
+
+// DeleteBlocklistEntry godoc
+// @Summary Delete a blocklist entry
+// @Description Removes an entry, unblocking the senders it matched. Requires emails:delete scope.
+// @Tags Blocklist
+// @Param id path int true "Blocklist entry ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /blocklist/{id} [delete]
+func (h *Handler) DeleteBlocklistEntry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.repo.DeleteBlocklistEntry(c.Request.Context(), id); err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Blocklist entry not found", "Failed to delete blocklist entry")
+		return
+	}
+
+	h.blocklist.Invalidate()
+	h.recordAction(c, actionBlocklistDelete, auditResourceBlocklistEntry, id, nil)
+
+	c.Status(http.StatusNoContent)
+}
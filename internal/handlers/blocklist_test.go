@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+func setupBlocklistRouter(handler *Handler) *gin.Engine {
+	router := setupTestRouter()
+	router.GET("/api/v1/blocklist", handler.GetBlocklist)
+	router.GET("/api/v1/blocklist/:id", handler.GetBlocklistEntry)
+	router.POST("/api/v1/blocklist", handler.CreateBlocklistEntry)
+	router.PUT("/api/v1/blocklist/:id", handler.UpdateBlocklistEntry)
+	router.DELETE("/api/v1/blocklist/:id", handler.DeleteBlocklistEntry)
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+	return router
+}
+
+func TestGetBlocklist_Empty(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	w := performRequest(setupBlocklistRouter(handler), http.MethodGet, "/api/v1/blocklist", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("expected an empty array, got %s", w.Body.String())
+	}
+}
+
+func TestGetBlocklistEntry_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		getBlocklistEntryFunc: func(_ context.Context, _ int64) (*repository.BlocklistEntry, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupBlocklistRouter(handler), http.MethodGet, "/api/v1/blocklist/9", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestCreateBlocklistEntry(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantValue string
+	}{
+		{"address", `{"kind":"address","value":"Spammer@Example.com","reason":"abuse"}`, http.StatusCreated, "spammer@example.com"},
+		{"domain", `{"kind":"domain","value":"spam.test"}`, http.StatusCreated, "spam.test"},
+		{"pattern", `{"kind":"pattern","value":"seo*@*"}`, http.StatusCreated, "seo*@*"},
+		{"cidr", `{"kind":"cidr","value":"203.0.113.9/24"}`, http.StatusCreated, "203.0.113.0/24"},
+		{"invalid cidr", `{"kind":"cidr","value":"203.0.113.0/40"}`, http.StatusBadRequest, ""},
+		{"unknown kind", `{"kind":"regex","value":".*"}`, http.StatusBadRequest, ""},
+		{"missing value", `{"kind":"domain"}`, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *repository.BlocklistEntry
+			mockRepo := &mockRepository{
+				createBlocklistFunc: func(_ context.Context, entry *repository.BlocklistEntry) error {
+					entry.ID = 7
+					created = entry
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			w := performRequest(setupBlocklistRouter(handler), http.MethodPost, "/api/v1/blocklist", strings.NewReader(tt.body))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantValue == "" {
+				if created != nil {
+					t.Error("expected invalid entry not to be stored")
+				}
+				return
+			}
+			if created == nil || created.Value != tt.wantValue {
+				t.Errorf("expected stored value %q, got %+v", tt.wantValue, created)
+			}
+			if loc := w.Header().Get("Location"); !strings.HasSuffix(loc, "/7") {
+				t.Errorf("unexpected Location header %q", loc)
+			}
+		})
+	}
+}
+
+func TestCreateBlocklistEntry_Exists(t *testing.T) {
+	mockRepo := &mockRepository{
+		createBlocklistFunc: func(_ context.Context, _ *repository.BlocklistEntry) error {
+			return fmt.Errorf("failed to create blocklist entry: %w", repository.ErrBlocklistEntryExists)
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	body := strings.NewReader(`{"kind":"domain","value":"spam.test"}`)
+	w := performRequest(setupBlocklistRouter(handler), http.MethodPost, "/api/v1/blocklist", body)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestUpdateBlocklistEntry_RevalidatesValue(t *testing.T) {
+	var updated *repository.BlocklistEntry
+	mockRepo := &mockRepository{
+		getBlocklistEntryFunc: func(_ context.Context, id int64) (*repository.BlocklistEntry, error) {
+			return &repository.BlocklistEntry{ID: id, Kind: repository.BlocklistKindDomain, Value: "spam.test"}, nil
+		},
+		updateBlocklistFunc: func(_ context.Context, entry *repository.BlocklistEntry) error {
+			updated = entry
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+	router := setupBlocklistRouter(handler)
+
+	// Changing only the kind leaves a domain that is not a valid address
+	w := performRequest(router, http.MethodPut, "/api/v1/blocklist/3", strings.NewReader(`{"kind":"address"}`))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = performRequest(router, http.MethodPut, "/api/v1/blocklist/3", strings.NewReader(`{"kind":"pattern","value":"*@*.Spam.test"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if updated == nil || updated.Kind != repository.BlocklistKindPattern || updated.Value != "*@*.spam.test" {
+		t.Errorf("unexpected update %+v", updated)
+	}
+}
+
+func TestDeleteBlocklistEntry_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		deleteBlocklistFunc: func(_ context.Context, _ int64) error {
+			return gorm.ErrRecordNotFound
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupBlocklistRouter(handler), http.MethodDelete, "/api/v1/blocklist/3", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestBlocklist_ChangesInvalidateCache checks that a new entry applies to the next contact message
+// without waiting for the cache to expire
+func TestBlocklist_ChangesInvalidateCache(t *testing.T) {
+	var entries []repository.BlocklistEntry
+	stored := 0
+	mockRepo := &mockRepository{
+		getBlocklistFunc: func(_ context.Context) ([]repository.BlocklistEntry, error) {
+			return entries, nil
+		},
+		createBlocklistFunc: func(_ context.Context, entry *repository.BlocklistEntry) error {
+			entry.ID = int64(len(entries) + 1)
+			entries = append(entries, *entry)
+			return nil
+		},
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			stored++
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+	router := setupBlocklistRouter(handler)
+
+	contact := func() {
+		t.Helper()
+		w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(contactAckBody))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+	}
+
+	contact()
+	w := performRequest(router, http.MethodPost, "/api/v1/blocklist", strings.NewReader(`{"kind":"cidr","value":"192.0.2.0/24"}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var entry repository.BlocklistEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entry); err != nil || entry.Value != "192.0.2.0/24" {
+		t.Fatalf("unexpected response %s", w.Body.String())
+	}
+	contact()
+
+	// performRequest sends from 192.0.2.1, the httptest default client address
+	if stored != 1 {
+		t.Errorf("expected only the message before the block to be stored, got %d", stored)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/captcha"
+	"github.com/GunarsK-portfolio/messaging-api/internal/spam"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+)
+
+// captchaUnavailableReason is recorded on messages held because the CAPTCHA provider could not be asked
+const captchaUnavailableReason = "captcha: verification unavailable"
+
+// verifyCaptcha checks the request's CAPTCHA token when a verifier is configured. A rejected token
+// gets 400 and ok=false. If the provider cannot be reached the message is let through with
+// held=true, so it is quarantined rather than lost.
+func (h *Handler) verifyCaptcha(c *gin.Context, token string) (held, ok bool) {
+	if h.captcha == nil {
+		return false, true
+	}
+
+	err := h.captcha.Verify(c.Request.Context(), token, c.ClientIP())
+	switch {
+	case err == nil:
+		return false, true
+	case errors.Is(err, captcha.ErrRejected):
+		logger.GetLogger(c).Info("Contact message failed CAPTCHA", "error", err)
+		commonhandlers.RespondError(c, http.StatusBadRequest, "CAPTCHA verification failed")
+		return false, false
+	default:
+		logger.GetLogger(c).Warn("CAPTCHA provider unavailable, holding message for review", "error", err)
+		return true, true
+	}
+}
+
+// holdForReview quarantines a verdict that would otherwise be accepted, recording why
+func holdForReview(verdict *spam.Verdict, reason string) {
+	verdict.Reasons = append(verdict.Reasons, reason)
+	if verdict.Action == spam.ActionAccept {
+		verdict.Action = spam.ActionQuarantine
+	}
+}
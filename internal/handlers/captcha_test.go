@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/captcha"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+type mockVerifier struct {
+	token string
+	err   error
+}
+
+func (m *mockVerifier) Verify(_ context.Context, token, _ string) error {
+	m.token = token
+	return m.err
+}
+
+const captchaContactBody = `{"name":"Jane","email":"jane@example.com","subject":"Hello","message":"Hi there, nice portfolio","captchaToken":"tok-123"}`
+
+func TestCreateContactMessage_CaptchaPassed(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	verifier := &mockVerifier{}
+	handler := New(mockRepo, &mockPublisher{}, WithCaptcha(verifier))
+
+	if code := postContact(t, handler, captchaContactBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if verifier.token != "tok-123" {
+		t.Errorf("expected captchaToken to be verified, got %q", verifier.token)
+	}
+	if created == nil || created.Status != models.EmailStatusPending {
+		t.Errorf("expected pending email, got %+v", created)
+	}
+}
+
+func TestCreateContactMessage_CaptchaRejected(t *testing.T) {
+	createCalled := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			createCalled = true
+			return nil
+		},
+	}
+	verifier := &mockVerifier{err: fmt.Errorf("%w: invalid-input-response", captcha.ErrRejected)}
+	handler := New(mockRepo, &mockPublisher{}, WithCaptcha(verifier))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+	w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(captchaContactBody))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "CAPTCHA verification failed") {
+		t.Errorf("unexpected body %s", w.Body.String())
+	}
+	if createCalled {
+		t.Error("expected message not to be stored")
+	}
+}
+
+func TestCreateContactMessage_CaptchaUnavailableHoldsMessage(t *testing.T) {
+	var created *repository.Email
+	published := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	mockPub := &mockPublisher{publishFunc: func(_ context.Context, _ any) error {
+		published = true
+		return nil
+	}}
+	handler := New(mockRepo, mockPub, WithCaptcha(&mockVerifier{err: errors.New("connection refused")}))
+
+	if code := postContact(t, handler, captchaContactBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if created == nil || created.Status != repository.EmailStatusQuarantined {
+		t.Fatalf("expected message to be quarantined, got %+v", created)
+	}
+	if len(created.SpamReasons) != 1 || created.SpamReasons[0] != captchaUnavailableReason {
+		t.Errorf("unexpected spam reasons %v", created.SpamReasons)
+	}
+	if published {
+		t.Error("expected held message not to be published")
+	}
+}
+
+func TestCreateContactMessage_CaptchaDisabled(t *testing.T) {
+	created := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			created = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	body := `{"name":"Jane","email":"jane@example.com","subject":"Hello","message":"Hi there, nice portfolio"}`
+	if code := postContact(t, handler, body); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if !created {
+		t.Error("expected message to be stored without a CAPTCHA token")
+	}
+}
+
+// TestCreateContactMessage_CaptchaStubProvider runs the real verifier against a local siteverify stub
+func TestCreateContactMessage_CaptchaStubProvider(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("response") == "tok-123" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer stub.Close()
+
+	verifier, err := captcha.New(captcha.ProviderTurnstile, "secret", stub.URL, 0, time.Second)
+	if err != nil {
+		t.Fatalf("captcha.New() error = %v", err)
+	}
+	handler := New(&mockRepository{}, &mockPublisher{}, WithCaptcha(verifier))
+
+	if code := postContact(t, handler, captchaContactBody); code != http.StatusCreated {
+		t.Errorf("valid token: expected status %d, got %d", http.StatusCreated, code)
+	}
+	forged := strings.Replace(captchaContactBody, "tok-123", "forged", 1)
+	if code := postContact(t, handler, forged); code != http.StatusBadRequest {
+		t.Errorf("forged token: expected status %d, got %d", http.StatusBadRequest, code)
+	}
+}
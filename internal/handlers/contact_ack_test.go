@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtypes"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+const contactAckBody = `{"name":"Jane <b>","email":"jane@example.com","subject":"Hello","message":"Hi there"}`
+
+func postContact(t *testing.T, handler *Handler, body string) int {
+	t.Helper()
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+	w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(body))
+	return w.Code
+}
+
+func TestCreateContactMessage_SendsContactAck(t *testing.T) {
+	var ack *repository.Email
+	var since time.Time
+	var published []int64
+
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			email.ID = 10
+			return nil
+		},
+		createThrottledFunc: func(_ context.Context, email *repository.Email, s time.Time) (bool, error) {
+			ack, since = email, s
+			email.ID = 11
+			return true, nil
+		},
+	}
+	mockPub := &mockPublisher{
+		publishFunc: func(_ context.Context, msg interface{}) error {
+			published = append(published, msg.(models.EmailEvent).EmailID)
+			return nil
+		},
+	}
+	handler := New(mockRepo, mockPub, WithContactAck(true, 24*time.Hour))
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if ack == nil {
+		t.Fatal("expected contact acknowledgement to be created")
+	}
+	if ack.Type != emailtypes.EmailTypeContactAck {
+		t.Errorf("expected type %q, got %q", emailtypes.EmailTypeContactAck, ack.Type)
+	}
+	if ack.RecipientEmail == nil || *ack.RecipientEmail != "jane@example.com" {
+		t.Errorf("expected recipient jane@example.com, got %v", ack.RecipientEmail)
+	}
+	if ack.InReplyToID == nil || *ack.InReplyToID != 10 {
+		t.Errorf("expected ack to reply to email 10, got %v", ack.InReplyToID)
+	}
+	if ack.Subject != "We received your message" || ack.Status != models.EmailStatusPending {
+		t.Errorf("unexpected ack %q in status %s", ack.Subject, ack.Status)
+	}
+	if !strings.Contains(ack.Message, "Hi Jane &lt;b&gt;,") {
+		t.Error("expected escaped sender name in ack body")
+	}
+	if ack.TextBody == nil || !strings.Contains(*ack.TextBody, "Hi Jane <b>,") {
+		t.Errorf("expected text body with sender name, got %v", ack.TextBody)
+	}
+	if strings.Contains(ack.Message, "Hi there") {
+		t.Error("expected ack not to echo the visitor's message")
+	}
+	if d := time.Since(since); d < 24*time.Hour || d > 24*time.Hour+time.Minute {
+		t.Errorf("expected throttle window of 24h, got %v", d)
+	}
+	if len(published) != 2 || published[0] != 10 || published[1] != 11 {
+		t.Errorf("expected both emails published, got %v", published)
+	}
+}
+
+func TestCreateContactMessage_ContactAckThrottled(t *testing.T) {
+	var published []int64
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			email.ID = 10
+			return nil
+		},
+		createThrottledFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+			return false, nil
+		},
+	}
+	mockPub := &mockPublisher{
+		publishFunc: func(_ context.Context, msg interface{}) error {
+			published = append(published, msg.(models.EmailEvent).EmailID)
+			return nil
+		},
+	}
+	handler := New(mockRepo, mockPub, WithContactAck(true, time.Hour))
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if len(published) != 1 || published[0] != 10 {
+		t.Errorf("expected only the contact message published, got %v", published)
+	}
+}
+
+func TestCreateContactMessage_ContactAckDisabled(t *testing.T) {
+	called := false
+	mockRepo := &mockRepository{
+		createThrottledFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+			called = true
+			return true, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if called {
+		t.Error("expected no acknowledgement when disabled")
+	}
+}
+
+func TestCreateContactMessage_ContactAckSkippedForSpam(t *testing.T) {
+	called := false
+	mockRepo := &mockRepository{
+		createThrottledFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+			called = true
+			return true, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithContactAck(true, time.Hour))
+
+	body := `{"name":"Bot","email":"bot@example.com","subject":"Buy","message":"Spam","website":"http://spam.example"}`
+	if code := postContact(t, handler, body); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if called {
+		t.Error("expected no acknowledgement for spam")
+	}
+}
+
+func TestCreateContactMessage_ContactAckErrorDoesNotFailRequest(t *testing.T) {
+	mockRepo := &mockRepository{
+		createThrottledFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+			return false, errors.New("db down")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithContactAck(true, time.Hour))
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, code)
+	}
+}
+
+func TestCreateContactMessage_ContactAckUsesDatabaseTemplate(t *testing.T) {
+	var ack *repository.Email
+	var lookedUp string
+	mockRepo := &mockRepository{
+		getActiveTemplateFunc: func(_ context.Context, emailType string, _ []string) (*repository.EmailTemplateVersion, error) {
+			lookedUp = emailType
+			return &repository.EmailTemplateVersion{ID: 7, Version: 2, Subject: "Thanks {{.name}}", HTMLBody: "<p>Got it, {{.name}}</p>"}, nil
+		},
+		createThrottledFunc: func(_ context.Context, email *repository.Email, _ time.Time) (bool, error) {
+			ack = email
+			return true, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithContactAck(true, time.Hour))
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if lookedUp != emailtypes.EmailTypeContactAck {
+		t.Errorf("expected template lookup for contact_ack, got %q", lookedUp)
+	}
+	if ack == nil || ack.Subject != "Thanks Jane <b>" {
+		t.Fatalf("expected database template subject, got %+v", ack)
+	}
+	if ack.TemplateVersionID == nil || *ack.TemplateVersionID != 7 {
+		t.Errorf("expected template version 7, got %v", ack.TemplateVersionID)
+	}
+}
@@ -1,11 +1,22 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtypes"
+	"github.com/GunarsK-portfolio/messaging-api/internal/plaintext"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/messaging-api/internal/spam"
 	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
 	"github.com/GunarsK-portfolio/portfolio-common/logger"
 	"github.com/GunarsK-portfolio/portfolio-common/models"
@@ -13,67 +24,365 @@ import (
 
 // CreateContactMessage godoc
 // @Summary Submit a contact message
-// @Description Creates a new contact message (public endpoint)
+// @Description Creates a new contact message (public endpoint). When attachments are enabled, send multipart/form-data with files in "attachments" to attach a CV or brief; otherwise multipart gets 415. Submissions the spam filter finds suspicious are held for review, and a repeat of a recent submission is not stored again; the response is the same either way. When CAPTCHA is enabled, captchaToken is required and a rejected token gets 400. A sender address whose domain does not accept mail is held for review or gets 400, depending on configuration. Submissions are rate-limited per client IP and per sender address; over the limit the response is 429 with Retry-After.
 // @Tags Contact
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
-// @Param message body models.ContactMessageCreate true "Contact message"
+// @Param message body ContactMessageRequest true "Contact message"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Header 429 {string} Retry-After "Seconds until another submission is accepted"
 // @Failure 500 {object} map[string]string
 // @Router /contact [post]
 func (h *Handler) CreateContactMessage(c *gin.Context) {
-	var req models.ContactMessageCreate
-	if err := c.ShouldBindJSON(&req); err != nil {
-		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+	req, uploads, err := h.bindContactMessage(c)
+	if err != nil {
+		var attErr *attachmentError
+		if errors.As(err, &attErr) {
+			respondAttachmentError(c, err)
+			return
+		}
+		respondBindError(c, err)
+		return
+	}
+
+	if h.senderLimit != nil && !h.senderLimit.Check(c, strings.ToLower(req.Email)) {
+		return
+	}
+
+	captchaHeld, ok := h.verifyCaptcha(c, req.CaptchaToken)
+	if !ok {
+		return
+	}
+
+	// Spam is answered like a real submission so bots cannot tell they were caught
+	if h.senderBlocked(c, req.Email) {
+		logger.GetLogger(c).Info("Contact message from blocked sender dropped")
+		c.JSON(http.StatusCreated, gin.H{"message": "Thank you for your message"})
+		return
+	}
+
+	undeliverable, ok := h.checkSenderDomain(c, req.Email)
+	if !ok {
 		return
 	}
 
-	if req.IsSpam() {
+	verdict := h.spamFilter.Evaluate(c.Request.Context(), req.spamSubmission())
+	if captchaHeld {
+		holdForReview(&verdict, captchaUnavailableReason)
+	}
+	if undeliverable {
+		holdForReview(&verdict, undeliverableSenderReason)
+	}
+	if verdict.Action == spam.ActionReject {
+		logger.GetLogger(c).Info("Contact message rejected as spam", "score", verdict.Score, "reasons", verdict.Reasons)
 		c.JSON(http.StatusCreated, gin.H{"message": "Thank you for your message"})
 		return
 	}
 
-	email := &models.Email{
-		Type:        models.EmailTypeContactForm,
-		Name:        &req.Name,
-		SenderEmail: &req.Email,
-		Subject:     req.Subject,
-		Message:     req.Message,
-		Status:      models.EmailStatusPending,
+	if err := h.validateAttachments(uploads); err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	status := models.EmailStatusPending
+	if verdict.Action == spam.ActionQuarantine {
+		status = repository.EmailStatusQuarantined
 	}
 
-	if err := h.repo.CreateEmail(c.Request.Context(), email); err != nil {
+	// The visitor's text is the plain-text body; the HTML body is its escaped rendering
+	text := plaintext.NormalizeNewlines(req.Message)
+	email := &repository.Email{
+		Email: models.Email{
+			Type:        models.EmailTypeContactForm,
+			Name:        &req.Name,
+			SenderEmail: &req.Email,
+			Subject:     req.Subject,
+			Message:     plaintext.ToHTML(text),
+			Status:      status,
+		},
+		TextBody:    &text,
+		SpamScore:   &verdict.Score,
+		SpamReasons: verdict.Reasons,
+		ContentHash: contactContentHash(req.ContactMessageCreate),
+	}
+
+	if len(uploads) > 0 {
+		if email.Attachments, err = h.storeAttachments(c, uploads); err != nil {
+			commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to submit message")
+			return
+		}
+	}
+
+	created, err := h.createContactEmail(c, email)
+	if err != nil {
+		h.discardAttachments(c, email.Attachments)
 		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to submit message")
 		return
 	}
+	if !created {
+		h.discardAttachments(c, email.Attachments)
+		logger.GetLogger(c).Info("Duplicate contact message ignored")
+		if h.metrics != nil {
+			h.metrics.ContactDuplicates.Inc()
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Thank you for your message"})
+		return
+	}
+
+	if status == repository.EmailStatusQuarantined {
+		logger.GetLogger(c).Info("Contact message quarantined", "emailId", email.ID, "score", verdict.Score, "reasons", verdict.Reasons)
+		c.JSON(http.StatusCreated, gin.H{"message": "Thank you for your message"})
+		return
+	}
+
+	h.publishEmailEvent(c, email.ID)
 
-	event := models.EmailEvent{EmailID: email.ID}
-	if err := h.publisher.Publish(c.Request.Context(), event); err != nil {
-		logger.GetLogger(c).Error("Failed to publish message to queue", "error", err, "emailId", email.ID)
+	if h.contactAck {
+		h.sendContactAck(c, email.ID, req.ContactMessageCreate)
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"message": "Thank you for your message"})
 }
 
+// createContactEmail stores a contact message, skipping it if the same content was submitted
+// within the duplicate window. It reports whether the message was created.
+func (h *Handler) createContactEmail(c *gin.Context, email *repository.Email) (bool, error) {
+	if h.duplicateWindow <= 0 {
+		return true, h.repo.CreateEmail(c.Request.Context(), email)
+	}
+	return h.repo.CreateDedupedEmail(c.Request.Context(), email, time.Now().Add(-h.duplicateWindow))
+}
+
+// contactContentHash hashes the sender, subject and message so that resubmissions of the same
+// message match regardless of the sender's letter case or surrounding whitespace
+func contactContentHash(req models.ContactMessageCreate) *string {
+	sum := sha256.New()
+	for _, part := range []string{
+		strings.ToLower(strings.TrimSpace(req.Email)),
+		strings.TrimSpace(req.Subject),
+		plaintext.NormalizeNewlines(strings.TrimSpace(req.Message)),
+	} {
+		sum.Write([]byte(part))
+		sum.Write([]byte{0})
+	}
+	hash := hex.EncodeToString(sum.Sum(nil))
+	return &hash
+}
+
+// sendContactAck creates the contact_ack auto-reply to the sender of a contact message.
+// It is skipped if the address was acknowledged within the throttle window; failures are only
+// logged because the contact message itself has already been accepted.
+func (h *Handler) sendContactAck(c *gin.Context, messageID int64, req models.ContactMessageCreate) {
+	ctx := c.Request.Context()
+	log := logger.GetLogger(c)
+
+	// Suppressed senders (e.g. hard bounces) are not acknowledged; failures only skip the check
+	suppression, err := h.repo.GetActiveSuppression(ctx, req.Email)
+	if err != nil {
+		log.Warn("Failed to check suppression for contact acknowledgement", "error", err, "emailId", messageID)
+	} else if suppression != nil {
+		log.Info("Contact acknowledgement to suppressed sender skipped", "emailId", messageID, "reason", suppression.Reason)
+		return
+	}
+
+	ackType, _ := emailtypes.Lookup(emailtypes.EmailTypeContactAck)
+	rendered, err := h.renderEmail(ctx, ackType, "", map[string]string{"name": req.Name})
+	if err != nil {
+		log.Error("Failed to render contact acknowledgement", "error", err, "emailId", messageID)
+		return
+	}
+
+	ack := &repository.Email{
+		Email: models.Email{
+			Type:           ackType.Name,
+			RecipientEmail: &req.Email,
+			Subject:        rendered.Subject,
+			Message:        rendered.HTML,
+			Status:         models.EmailStatusPending,
+		},
+		TextBody:          &rendered.Text,
+		TemplateVersionID: rendered.TemplateVersionID,
+		InReplyToID:       &messageID,
+	}
+
+	created, err := h.repo.CreateThrottledEmail(ctx, ack, time.Now().Add(-h.contactAckThrottle))
+	if err != nil {
+		log.Error("Failed to create contact acknowledgement", "error", err, "emailId", messageID)
+		return
+	}
+	if !created {
+		log.Info("Contact acknowledgement throttled", "emailId", messageID)
+		return
+	}
+
+	h.publishEmailEvent(c, ack.ID)
+}
+
+// bindContactMessage reads a contact message from JSON, or from multipart/form-data with attached files
+func (h *Handler) bindContactMessage(c *gin.Context) (ContactMessageRequest, []attachmentUpload, error) {
+	var req ContactMessageRequest
+	h.limitAttachmentBody(c, false)
+	if c.ContentType() != gin.MIMEMultipartPOSTForm {
+		err := c.ShouldBindJSON(&req)
+		return req, nil, err
+	}
+	if h.attachmentStore == nil {
+		return req, nil, &attachmentError{http.StatusUnsupportedMediaType, "Attachments are not enabled; send the message as JSON"}
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return req, nil, err
+	}
+	req = ContactMessageRequest{
+		ContactMessageCreate: models.ContactMessageCreate{
+			Name:     c.PostForm("name"),
+			Email:    c.PostForm("email"),
+			Subject:  c.PostForm("subject"),
+			Message:  c.PostForm("message"),
+			Honeypot: c.PostForm("website"),
+		},
+		FormToken:    c.PostForm("formToken"),
+		CaptchaToken: c.PostForm("captchaToken"),
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return req, nil, err
+	}
+
+	uploads, err := h.readMultipartAttachments(form.File[contactAttachmentField])
+	return req, uploads, err
+}
+
+// defaultEmailPageSize is the page size when no limit is given
+const defaultEmailPageSize = 50
+
+// legacyEmailPageSize preserves the pre-pagination listing size for the bare-array response
+const legacyEmailPageSize = 100
+
+// EmailListQuery holds the query parameters for listing emails
+type EmailListQuery struct {
+	Limit          int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor         string    `form:"cursor" binding:"omitempty,max=512"`
+	Type           string    `form:"type" binding:"omitempty,max=50"`
+	Status         string    `form:"status" binding:"omitempty,max=50"`
+	SenderEmail    string    `form:"sender_email" binding:"omitempty,max=255"`
+	RecipientEmail string    `form:"recipient_email" binding:"omitempty,max=255"`
+	CreatedFrom    time.Time `form:"created_from"`
+	CreatedTo      time.Time `form:"created_to"`
+}
+
+// EmailListResponse is the paged envelope for email listings
+type EmailListResponse struct {
+	Data       []repository.Email `json:"data"`
+	NextCursor *string            `json:"next_cursor"`
+	HasMore    bool               `json:"has_more"`
+	Limit      int                `json:"limit"`
+}
+
 // GetEmails godoc
-// @Summary Get all emails
-// @Description Returns all emails (admin only)
+// @Summary List emails
+// @Description Returns a page of emails, newest first (admin only). Follow next_cursor to load older pages.
 // @Tags Emails
 // @Produce json
-// @Success 200 {array} models.Email
+// @Param limit query int false "Page size (1-100, default 50)"
+// @Param cursor query string false "Opaque cursor from a previous next_cursor"
+// @Param type query string false "Filter by email type"
+// @Param status query string false "Filter by status" Enums(pending, queued, sent, failed, cancelled, quarantined, spam, scheduled)
+// @Param sender_email query string false "Filter by sender email (case-insensitive)"
+// @Param recipient_email query string false "Filter by recipient email (case-insensitive)"
+// @Param created_from query string false "Created at or after (RFC3339)"
+// @Param created_to query string false "Created before (RFC3339)"
+// @Success 200 {object} EmailListResponse
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /emails [get]
 func (h *Handler) GetEmails(c *gin.Context) {
-	emails, err := h.repo.GetEmails(c.Request.Context())
+	var query EmailListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := h.buildEmailFilter(query)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.repo.GetEmails(c.Request.Context(), filter)
 	if err != nil {
 		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve emails")
 		return
 	}
 
-	c.JSON(http.StatusOK, emails)
+	emails := page.Emails
+	if emails == nil {
+		emails = []repository.Email{}
+	}
+
+	if h.legacyEmailList {
+		c.JSON(http.StatusOK, emails)
+		return
+	}
+
+	c.JSON(http.StatusOK, EmailListResponse{
+		Data:       emails,
+		NextCursor: encodeCursor(page.NextCursor),
+		HasMore:    page.NextCursor != nil,
+		Limit:      filter.Limit,
+	})
+}
+
+// buildEmailFilter validates list query parameters and converts them to a repository filter
+func (h *Handler) buildEmailFilter(query EmailListQuery) (repository.EmailFilter, error) {
+	filter := repository.EmailFilter{
+		Type:           query.Type,
+		Status:         query.Status,
+		SenderEmail:    query.SenderEmail,
+		RecipientEmail: query.RecipientEmail,
+		Limit:          query.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultEmailPageSize
+		if h.legacyEmailList {
+			filter.Limit = legacyEmailPageSize
+		}
+	}
+	if filter.Status != "" && !validEmailStatus(filter.Status) {
+		return filter, fmt.Errorf("invalid status: %s", filter.Status)
+	}
+	if !query.CreatedFrom.IsZero() {
+		filter.CreatedFrom = &query.CreatedFrom
+	}
+	if !query.CreatedTo.IsZero() {
+		filter.CreatedTo = &query.CreatedTo
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, errors.New("created_from must be before created_to")
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// validEmailStatus reports whether s is a status emails can be filtered by
+func validEmailStatus(s string) bool {
+	return models.ValidEmailStatus(s) || s == repository.EmailStatusCancelled ||
+		s == repository.EmailStatusQuarantined || s == repository.EmailStatusSpam ||
+		s == repository.EmailStatusScheduled
 }
 
 // GetEmail godoc
@@ -82,7 +391,7 @@ func (h *Handler) GetEmails(c *gin.Context) {
 // @Tags Emails
 // @Produce json
 // @Param id path int true "Email ID"
-// @Success 200 {object} models.Email
+// @Success 200 {object} repository.Email
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
 	"github.com/GunarsK-portfolio/portfolio-common/models"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -18,12 +20,12 @@ import (
 // =============================================================================
 
 func TestCreateContactMessage_Success(t *testing.T) {
-	var createdEmail *models.Email
+	var createdEmail *repository.Email
 	var published interface{}
 	publishCalled := false
 
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, email *models.Email) error {
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
 			createdEmail = email
 			email.ID = 1
 			return nil
@@ -81,11 +83,11 @@ func TestCreateContactMessage_Success(t *testing.T) {
 }
 
 func TestCreateContactMessage_SpamDetected(t *testing.T) {
-	createCalled := false
+	var created *repository.Email
 	publishCalled := false
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, _ *models.Email) error {
-			createCalled = true
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
 			return nil
 		},
 	}
@@ -106,8 +108,8 @@ func TestCreateContactMessage_SpamDetected(t *testing.T) {
 	if w.Code != http.StatusCreated {
 		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
 	}
-	if createCalled {
-		t.Error("expected message NOT to be saved for spam")
+	if created == nil || created.Status != repository.EmailStatusQuarantined {
+		t.Errorf("expected spam to be stored as quarantined, got %+v", created)
 	}
 	if publishCalled {
 		t.Error("expected publisher.Publish NOT to be called for spam")
@@ -119,7 +121,7 @@ func TestCreateContactMessage_SpamDetected(t *testing.T) {
 
 func TestCreateContactMessage_PublishError_DoesNotFailRequest(t *testing.T) {
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, m *models.Email) error {
+		createEmailFunc: func(_ context.Context, m *repository.Email) error {
 			m.ID = 1
 			return nil
 		},
@@ -145,6 +147,67 @@ func TestCreateContactMessage_PublishError_DoesNotFailRequest(t *testing.T) {
 	}
 }
 
+func TestCreateContactMessage_MarksOutboxDispatched(t *testing.T) {
+	var markedID int64
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, m *repository.Email) error {
+			m.ID = 7
+			return nil
+		},
+		markOutboxFunc: func(_ context.Context, emailID int64) error {
+			markedID = emailID
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	body := `{"name":"John Doe","email":"john@example.com","subject":"Test","message":"Hello world"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if markedID != 7 {
+		t.Errorf("expected outbox for email 7 to be marked dispatched, got %d", markedID)
+	}
+}
+
+func TestCreateContactMessage_PublishError_LeavesOutboxForRelay(t *testing.T) {
+	markCalled := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, m *repository.Email) error {
+			m.ID = 1
+			return nil
+		},
+		markOutboxFunc: func(_ context.Context, _ int64) error {
+			markCalled = true
+			return nil
+		},
+	}
+	mockPub := &mockPublisher{
+		publishFunc: func(_ context.Context, _ any) error {
+			return errors.New("mq down")
+		},
+	}
+	handler := New(mockRepo, mockPub)
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	body := `{"name":"John Doe","email":"john@example.com","subject":"Test","message":"Hello world"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if markCalled {
+		t.Error("expected outbox entry NOT to be marked dispatched when publish fails")
+	}
+}
+
 func TestCreateContactMessage_ValidationError(t *testing.T) {
 	handler := New(&mockRepository{}, &mockPublisher{})
 
@@ -175,7 +238,7 @@ func TestCreateContactMessage_InvalidEmail(t *testing.T) {
 
 func TestCreateContactMessage_RepositoryError(t *testing.T) {
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, _ *models.Email) error {
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
 			return errors.New("database error")
 		},
 	}
@@ -207,9 +270,9 @@ func TestCreateContactMessage_InvalidJSON(t *testing.T) {
 }
 
 func TestCreateContactMessage_UnicodeAndSpecialCharacters(t *testing.T) {
-	var createdEmail *models.Email
+	var createdEmail *repository.Email
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, email *models.Email) error {
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
 			createdEmail = email
 			email.ID = 1
 			return nil
@@ -262,7 +325,7 @@ func TestCreateContactMessage_MaxLengthInputs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			createCalled := false
 			testRepo := &mockRepository{
-				createEmailFunc: func(_ context.Context, m *models.Email) error {
+				createEmailFunc: func(_ context.Context, m *repository.Email) error {
 					createCalled = true
 					m.ID = 1
 					return nil
@@ -302,10 +365,37 @@ func TestCreateContactMessage_MaxLengthInputs(t *testing.T) {
 	}
 }
 
+func TestCreateContactMessage_TextAndHTMLBodies(t *testing.T) {
+	var createdEmail *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			createdEmail = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	body := `{"name":"Test","email":"test@example.com","subject":"Hi","message":"Line one & <two>\r\nLine three"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if want := "Line one &amp; &lt;two&gt;<br>\nLine three"; createdEmail.Message != want {
+		t.Errorf("message: expected %q, got %q", want, createdEmail.Message)
+	}
+	if want := "Line one & <two>\nLine three"; createdEmail.TextBody == nil || *createdEmail.TextBody != want {
+		t.Errorf("text body: expected %q, got %v", want, createdEmail.TextBody)
+	}
+}
+
 func TestCreateContactMessage_EmptyHoneypotIsNotSpam(t *testing.T) {
 	createCalled := false
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, _ *models.Email) error {
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
 			createCalled = true
 			return nil
 		},
@@ -355,15 +445,15 @@ func TestCreateContactMessage_MaliciousContent(t *testing.T) {
 			inputMessage:    "<iframe src='evil.com'></iframe>",
 			expectedName:    "<script>alert('xss')</script>",
 			expectedSubject: "<img src=x onerror=alert('xss')>",
-			expectedMessage: "<iframe src='evil.com'></iframe>",
+			expectedMessage: "&lt;iframe src=&#39;evil.com&#39;&gt;&lt;/iframe&gt;",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var createdEmail *models.Email
+			var createdEmail *repository.Email
 			mockRepo := &mockRepository{
-				createEmailFunc: func(_ context.Context, email *models.Email) error {
+				createEmailFunc: func(_ context.Context, email *repository.Email) error {
 					createdEmail = email
 					email.ID = 1
 					return nil
@@ -403,6 +493,9 @@ func TestCreateContactMessage_MaliciousContent(t *testing.T) {
 			if createdEmail.Message != tt.expectedMessage {
 				t.Errorf("message: expected %q, got %q", tt.expectedMessage, createdEmail.Message)
 			}
+			if createdEmail.TextBody == nil || *createdEmail.TextBody != tt.inputMessage {
+				t.Errorf("text body: expected %q, got %v", tt.inputMessage, createdEmail.TextBody)
+			}
 		})
 	}
 }
@@ -414,8 +507,11 @@ func TestCreateContactMessage_MaliciousContent(t *testing.T) {
 func TestGetEmails_Success(t *testing.T) {
 	expected := createTestEmails()
 	mockRepo := &mockRepository{
-		getEmailsFunc: func(_ context.Context) ([]models.Email, error) {
-			return expected, nil
+		getEmailsFunc: func(_ context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
+			if filter.Limit != defaultEmailPageSize {
+				t.Errorf("expected default limit %d, got %d", defaultEmailPageSize, filter.Limit)
+			}
+			return &repository.EmailPage{Emails: expected}, nil
 		},
 	}
 	handler := New(mockRepo, &mockPublisher{})
@@ -429,19 +525,22 @@ func TestGetEmails_Success(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	var result []models.Email
+	var result EmailListResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
-	if len(result) != len(expected) {
-		t.Errorf("expected %d emails, got %d", len(expected), len(result))
+	if len(result.Data) != len(expected) {
+		t.Errorf("expected %d emails, got %d", len(expected), len(result.Data))
+	}
+	if result.HasMore || result.NextCursor != nil {
+		t.Errorf("expected last page, got has_more=%v next_cursor=%v", result.HasMore, result.NextCursor)
 	}
 }
 
 func TestGetEmails_Empty(t *testing.T) {
 	mockRepo := &mockRepository{
-		getEmailsFunc: func(_ context.Context) ([]models.Email, error) {
-			return []models.Email{}, nil
+		getEmailsFunc: func(_ context.Context, _ repository.EmailFilter) (*repository.EmailPage, error) {
+			return &repository.EmailPage{}, nil
 		},
 	}
 	handler := New(mockRepo, &mockPublisher{})
@@ -454,19 +553,14 @@ func TestGetEmails_Empty(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
-
-	var result []models.Email
-	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
-		t.Fatalf("failed to unmarshal response: %v", err)
-	}
-	if len(result) != 0 {
-		t.Errorf("expected 0 emails, got %d", len(result))
+	if !strings.Contains(w.Body.String(), `"data":[]`) {
+		t.Errorf("expected empty data array, got %s", w.Body.String())
 	}
 }
 
 func TestGetEmails_RepositoryError(t *testing.T) {
 	mockRepo := &mockRepository{
-		getEmailsFunc: func(_ context.Context) ([]models.Email, error) {
+		getEmailsFunc: func(_ context.Context, _ repository.EmailFilter) (*repository.EmailPage, error) {
 			return nil, errors.New("database error")
 		},
 	}
@@ -482,6 +576,185 @@ func TestGetEmails_RepositoryError(t *testing.T) {
 	}
 }
 
+func TestGetEmails_Filters(t *testing.T) {
+	var captured repository.EmailFilter
+	mockRepo := &mockRepository{
+		getEmailsFunc: func(_ context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
+			captured = filter
+			return &repository.EmailPage{}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails", handler.GetEmails)
+
+	path := "/api/v1/emails?limit=10&type=contact_form&status=sent&sender_email=john@example.com" +
+		"&recipient_email=admin@example.com&created_from=2025-01-01T00:00:00Z&created_to=2025-02-01T00:00:00Z"
+	w := performRequest(router, http.MethodGet, path, nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if captured.Limit != 10 {
+		t.Errorf("limit = %d, want 10", captured.Limit)
+	}
+	if captured.Type != models.EmailTypeContactForm || captured.Status != models.EmailStatusSent {
+		t.Errorf("type/status = %q/%q", captured.Type, captured.Status)
+	}
+	if captured.SenderEmail != "john@example.com" || captured.RecipientEmail != "admin@example.com" {
+		t.Errorf("sender/recipient = %q/%q", captured.SenderEmail, captured.RecipientEmail)
+	}
+	wantFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if captured.CreatedFrom == nil || !captured.CreatedFrom.Equal(wantFrom) {
+		t.Errorf("created_from = %v, want %v", captured.CreatedFrom, wantFrom)
+	}
+	if captured.CreatedTo == nil {
+		t.Error("expected created_to to be set")
+	}
+}
+
+func TestGetEmails_CancelledStatusFilter(t *testing.T) {
+	var captured repository.EmailFilter
+	mockRepo := &mockRepository{
+		getEmailsFunc: func(_ context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
+			captured = filter
+			email := createTestEmail()
+			email.Status = repository.EmailStatusCancelled
+			return &repository.EmailPage{Emails: []repository.Email{*email}}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails", handler.GetEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails?status=cancelled", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if captured.Status != repository.EmailStatusCancelled {
+		t.Errorf("status = %q, want %q", captured.Status, repository.EmailStatusCancelled)
+	}
+
+	var result EmailListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Status != repository.EmailStatusCancelled {
+		t.Errorf("expected one cancelled email, got %+v", result.Data)
+	}
+}
+
+func TestGetEmails_CursorRoundTrip(t *testing.T) {
+	next := &repository.EmailCursor{CreatedAt: time.Date(2025, 3, 1, 10, 30, 0, 123456000, time.UTC), ID: 42}
+	var captured repository.EmailFilter
+	mockRepo := &mockRepository{
+		getEmailsFunc: func(_ context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
+			captured = filter
+			return &repository.EmailPage{Emails: createTestEmails(), NextCursor: next}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails", handler.GetEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails?limit=2", nil)
+
+	var result EmailListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !result.HasMore || result.NextCursor == nil {
+		t.Fatalf("expected next cursor, got %s", w.Body.String())
+	}
+
+	w = performRequest(router, http.MethodGet, "/api/v1/emails?limit=2&cursor="+*result.NextCursor, nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if captured.After == nil {
+		t.Fatal("expected cursor to be passed to repository")
+	}
+	if captured.After.ID != 42 || !captured.After.CreatedAt.Equal(next.CreatedAt) {
+		t.Errorf("cursor = %+v, want %+v", captured.After, next)
+	}
+}
+
+func TestGetEmails_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"invalid_cursor", "cursor=not-a-cursor"},
+		{"invalid_status", "status=bogus"},
+		{"limit_over_max", "limit=101"},
+		{"limit_negative", "limit=-1"},
+		{"invalid_date", "created_from=yesterday"},
+		{"inverted_range", "created_from=2025-02-01T00:00:00Z&created_to=2025-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoCalled := false
+			mockRepo := &mockRepository{
+				getEmailsFunc: func(_ context.Context, _ repository.EmailFilter) (*repository.EmailPage, error) {
+					repoCalled = true
+					return &repository.EmailPage{}, nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			router := setupTestRouter()
+			router.GET("/api/v1/emails", handler.GetEmails)
+
+			w := performRequest(router, http.MethodGet, "/api/v1/emails?"+tt.query, nil)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if repoCalled {
+				t.Error("expected repository NOT to be called for invalid query")
+			}
+		})
+	}
+}
+
+func TestGetEmails_LegacyArrayResponse(t *testing.T) {
+	expected := createTestEmails()
+	var captured repository.EmailFilter
+	mockRepo := &mockRepository{
+		getEmailsFunc: func(_ context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
+			captured = filter
+			return &repository.EmailPage{Emails: expected}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithLegacyEmailList(true))
+
+	router := setupTestRouter()
+	router.GET("/api/v1/messages", handler.GetEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/messages", nil)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var result []repository.Email
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("expected bare array response: %v", err)
+	}
+	if len(result) != len(expected) {
+		t.Errorf("expected %d emails, got %d", len(expected), len(result))
+	}
+	if captured.Limit != legacyEmailPageSize {
+		t.Errorf("limit = %d, want legacy default %d", captured.Limit, legacyEmailPageSize)
+	}
+}
+
 // =============================================================================
 // GetEmail Tests
 // =============================================================================
@@ -489,7 +762,7 @@ func TestGetEmails_RepositoryError(t *testing.T) {
 func TestGetEmail_Success(t *testing.T) {
 	expected := createTestEmail()
 	mockRepo := &mockRepository{
-		getEmailByIDFunc: func(_ context.Context, id int64) (*models.Email, error) {
+		getEmailByIDFunc: func(_ context.Context, id int64) (*repository.Email, error) {
 			if id != 1 {
 				t.Errorf("expected id 1, got %d", id)
 			}
@@ -534,7 +807,7 @@ func TestGetEmail_InvalidID(t *testing.T) {
 
 func TestGetEmail_NotFound(t *testing.T) {
 	mockRepo := &mockRepository{
-		getEmailByIDFunc: func(_ context.Context, _ int64) (*models.Email, error) {
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
 			return nil, gorm.ErrRecordNotFound
 		},
 	}
@@ -552,7 +825,7 @@ func TestGetEmail_NotFound(t *testing.T) {
 
 func TestGetEmail_RepositoryError(t *testing.T) {
 	mockRepo := &mockRepository{
-		getEmailByIDFunc: func(_ context.Context, _ int64) (*models.Email, error) {
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
 			return nil, errors.New("database error")
 		},
 	}
@@ -573,11 +846,11 @@ func TestGetEmail_RepositoryError(t *testing.T) {
 // =============================================================================
 
 func TestSendEmail_Success(t *testing.T) {
-	var createdEmail *models.Email
+	var createdEmail *repository.Email
 	publishCalled := false
 
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, email *models.Email) error {
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
 			createdEmail = email
 			email.ID = 10
 			return nil
@@ -619,6 +892,10 @@ func TestSendEmail_Success(t *testing.T) {
 	if !strings.Contains(createdEmail.Message, "https://example.com/verify?token=abc") {
 		t.Error("expected rendered template to contain verify_url")
 	}
+	if createdEmail.TextBody == nil || !strings.Contains(*createdEmail.TextBody, "(https://example.com/verify?token=abc)") ||
+		strings.Contains(*createdEmail.TextBody, "<") {
+		t.Errorf("expected plain-text body derived from the template, got %v", createdEmail.TextBody)
+	}
 	if !publishCalled {
 		t.Error("expected publisher.Publish to be called")
 	}
@@ -665,7 +942,7 @@ func TestSendEmail_MissingFields(t *testing.T) {
 
 func TestSendEmail_RepositoryError(t *testing.T) {
 	mockRepo := &mockRepository{
-		createEmailFunc: func(_ context.Context, _ *models.Email) error {
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
 			return errors.New("database error")
 		},
 	}
@@ -689,7 +966,7 @@ func TestSendEmail_RepositoryError(t *testing.T) {
 func TestCreateContactMessage_ContextPropagation(t *testing.T) {
 	var capturedCtx context.Context
 	mockRepo := &mockRepository{
-		createEmailFunc: func(ctx context.Context, _ *models.Email) error {
+		createEmailFunc: func(ctx context.Context, _ *repository.Email) error {
 			capturedCtx = ctx
 			return nil
 		},
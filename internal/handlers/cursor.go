@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursorPayload is the JSON form of an email cursor before base64 encoding
+type cursorPayload struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"id"`
+}
+
+// encodeCursor turns a repository cursor into an opaque URL-safe token
+func encodeCursor(cursor *repository.EmailCursor) *string {
+	if cursor == nil {
+		return nil
+	}
+	raw, err := json.Marshal(cursorPayload{CreatedAt: cursor.CreatedAt, ID: cursor.ID})
+	if err != nil {
+		return nil
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return &token
+}
+
+// decodeCursor parses a token produced by encodeCursor
+func decodeCursor(token string) (*repository.EmailCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.ID <= 0 || payload.CreatedAt.IsZero() {
+		return nil, errInvalidCursor
+	}
+	return &repository.EmailCursor{CreatedAt: payload.CreatedAt, ID: payload.ID}, nil
+}
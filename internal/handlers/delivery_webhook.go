@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/delivery"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// maxDeliveryWebhookSize bounds delivery webhook bodies; provider events are a few KB
+const maxDeliveryWebhookSize = 1 << 20
+
+// HandleDeliveryWebhook godoc
+// @Summary Receive provider delivery events
+// @Description Accepts delivery, bounce and complaint events from SES (via SNS), Postmark or Mailgun. Requests are authenticated per provider (SNS signature and topic, Postmark basic auth, Mailgun HMAC signature). Events are matched to emails by the email_id metadata; deliveries mark emails sent, hard bounces mark them failed, and hard bounces and complaints suppress the recipient.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider" Enums(ses, postmark, mailgun)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/delivery/{provider} [post]
+func (h *Handler) HandleDeliveryWebhook(c *gin.Context) {
+	provider := c.Param("provider")
+	adapter, ok := h.deliveryAdapters[provider]
+	if !ok {
+		commonhandlers.RespondError(c, http.StatusNotFound, "Unknown delivery provider")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxDeliveryWebhookSize))
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+
+	log := logger.GetLogger(c)
+	events, err := adapter.Parse(c.Request, body)
+	switch {
+	case errors.Is(err, delivery.ErrUnauthorized):
+		log.Warn("Delivery webhook rejected", "provider", provider, "error", err)
+		commonhandlers.RespondError(c, http.StatusUnauthorized, "Invalid webhook signature")
+		return
+	case errors.Is(err, delivery.ErrInvalidPayload):
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to process delivery webhook")
+		return
+	}
+
+	// Failures answer 500 so the provider retries; events already recorded are skipped on the retry
+	for _, event := range events {
+		duplicate, err := h.applyDeliveryEvent(c, provider, event)
+		if err != nil {
+			commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to process delivery webhook")
+			return
+		}
+		if duplicate {
+			continue
+		}
+		if h.metrics != nil {
+			h.metrics.DeliveryEvents.WithLabelValues(provider, string(event.Type)).Inc()
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"processed": len(events)})
+}
+
+// applyDeliveryEvent suppresses hard-bounced and complaining recipients, records the event in the email's
+// timeline and moves the email to the event's status in one transaction. It reports whether the event
+// was already recorded, in which case the email is left as is.
+func (h *Handler) applyDeliveryEvent(c *gin.Context, provider string, event delivery.Event) (bool, error) {
+	ctx := c.Request.Context()
+	log := logger.GetLogger(c).With("provider", provider, "emailId", event.EmailID, "messageId", event.MessageID)
+
+	if reason := suppressionReasonFor(event); reason != "" && event.Recipient != "" {
+		note := provider + ": " + event.Description
+		err := h.repo.CreateSuppression(ctx, &repository.Suppression{Email: event.Recipient, Reason: reason, Note: &note})
+		switch {
+		case err == nil:
+			log.Info("Recipient suppressed by delivery event", "reason", reason)
+		case !errors.Is(err, repository.ErrSuppressionExists):
+			return false, err
+		}
+	}
+
+	if event.EmailID == 0 {
+		log.Debug("Delivery event without email_id, status not updated", "type", event.Type)
+		return false, nil
+	}
+	email, err := h.repo.GetEmailByID(ctx, event.EmailID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Warn("Delivery event for unknown email", "type", event.Type)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	details := map[string]any{
+		"provider":   provider,
+		"recipient":  event.Recipient,
+		"messageId":  event.MessageID,
+		"occurredAt": event.OccurredAt,
+	}
+	if event.Description != "" {
+		details["description"] = event.Description
+	}
+	if event.Type == delivery.EventBounce {
+		details["permanent"] = event.Permanent
+	}
+	timelineEvent := &repository.EmailEvent{EmailID: email.ID, Type: deliveryEventTypes[event.Type], Details: details}
+	if event.ID != "" {
+		timelineEvent.ProviderEventID = &event.ID
+	}
+	status, lastError := deliveryTransition(email.Status, event)
+	recorded, err := h.repo.CreateDeliveryEvent(ctx, timelineEvent, status, lastError)
+	if err != nil {
+		return false, err
+	}
+	if !recorded {
+		log.Info("Duplicate delivery event ignored", "type", event.Type, "eventId", event.ID)
+		return true, nil
+	}
+	if status != "" {
+		log.Info("Email status updated by delivery event", "type", event.Type, "from", email.Status, "to", status)
+	}
+	return false, nil
+}
+
+// deliveryEventTypes names the timeline event recorded for each provider event
+var deliveryEventTypes = map[delivery.EventType]string{
+	delivery.EventDelivered: repository.EmailEventDelivered,
+	delivery.EventBounce:    repository.EmailEventBounced,
+	delivery.EventComplaint: repository.EmailEventComplained,
+}
+
+// suppressionReasonFor returns the suppression reason an event calls for ("" if none)
+func suppressionReasonFor(event delivery.Event) string {
+	switch {
+	case event.Type == delivery.EventBounce && event.Permanent:
+		return repository.SuppressionReasonHardBounce
+	case event.Type == delivery.EventComplaint:
+		return repository.SuppressionReasonComplaint
+	default:
+		return ""
+	}
+}
+
+// deliveryTransition maps an event to the email's new status ("" to leave it unchanged).
+// A delivery confirms a queued email as sent; a hard bounce fails a queued or sent one.
+// Transient bounces and complaints leave the status alone.
+func deliveryTransition(current string, event delivery.Event) (string, *string) {
+	switch {
+	case event.Type == delivery.EventDelivered && current == models.EmailStatusQueued:
+		return models.EmailStatusSent, nil
+	case event.Type == delivery.EventBounce && event.Permanent &&
+		(current == models.EmailStatusQueued || current == models.EmailStatusSent):
+		lastError := "bounced: " + event.Description
+		return models.EmailStatusFailed, &lastError
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/delivery"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+const (
+	postmarkHardBounce = `{"RecordType":"Bounce","Type":"HardBounce","Email":"Gone@Example.com","Description":"Unknown user","Metadata":{"email_id":"12"}}`
+	postmarkDelivery   = `{"RecordType":"Delivery","Recipient":"jane@example.com","Metadata":{"email_id":"12"}}`
+)
+
+func postDeliveryWebhook(handler *Handler, provider, body, password string) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	router.POST("/api/v1/webhooks/delivery/:provider", handler.HandleDeliveryWebhook)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/delivery/"+provider, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth("hook", password)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func emailInStatus(status string) func(context.Context, int64) (*repository.Email, error) {
+	return func(_ context.Context, id int64) (*repository.Email, error) {
+		return &repository.Email{Email: models.Email{ID: id, Status: status}}, nil
+	}
+}
+
+func TestDeliveryWebhook_HardBounce(t *testing.T) {
+	var suppression *repository.Suppression
+	var status string
+	var lastError *string
+	mockRepo := &mockRepository{
+		createSuppressionFunc: func(_ context.Context, s *repository.Suppression) error {
+			suppression = s
+			return nil
+		},
+		getEmailByIDFunc: emailInStatus(models.EmailStatusSent),
+		createDeliveryEventFunc: func(_ context.Context, _ *repository.EmailEvent, s string, e *string) (bool, error) {
+			status, lastError = s, e
+			return true, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")))
+
+	w := postDeliveryWebhook(handler, delivery.ProviderPostmark, postmarkHardBounce, "s3cret")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if suppression == nil || suppression.Reason != repository.SuppressionReasonHardBounce || suppression.Email != "Gone@Example.com" {
+		t.Errorf("expected the recipient to be suppressed for a hard bounce, got %+v", suppression)
+	}
+	if status != models.EmailStatusFailed || lastError == nil || !strings.Contains(*lastError, "Unknown user") {
+		t.Errorf("expected email to fail with the bounce reason, got %q %v", status, lastError)
+	}
+}
+
+func TestDeliveryWebhook_AlreadySuppressed(t *testing.T) {
+	mockRepo := &mockRepository{
+		createSuppressionFunc: func(_ context.Context, _ *repository.Suppression) error {
+			return fmt.Errorf("failed to create suppression: %w", repository.ErrSuppressionExists)
+		},
+		getEmailByIDFunc: emailInStatus(models.EmailStatusFailed),
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")))
+
+	// A redelivered event finds the address suppressed and the email already failed
+	w := postDeliveryWebhook(handler, delivery.ProviderPostmark, postmarkHardBounce, "s3cret")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestDeliveryWebhook_Delivery(t *testing.T) {
+	tests := []struct {
+		current    string
+		wantStatus string
+	}{
+		{models.EmailStatusQueued, models.EmailStatusSent},
+		{models.EmailStatusSent, ""},
+		{repository.EmailStatusCancelled, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			var status string
+			suppressed := false
+			mockRepo := &mockRepository{
+				getEmailByIDFunc: emailInStatus(tt.current),
+				createDeliveryEventFunc: func(_ context.Context, _ *repository.EmailEvent, s string, _ *string) (bool, error) {
+					status = s
+					return true, nil
+				},
+				createSuppressionFunc: func(_ context.Context, _ *repository.Suppression) error {
+					suppressed = true
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{}, WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")))
+
+			w := postDeliveryWebhook(handler, delivery.ProviderPostmark, postmarkDelivery, "s3cret")
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("expected status update %q, got %q", tt.wantStatus, status)
+			}
+			if suppressed {
+				t.Error("expected a delivery not to suppress the recipient")
+			}
+		})
+	}
+}
+
+func TestDeliveryWebhook_DuplicateEventSkipped(t *testing.T) {
+	var providerEventID *string
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: emailInStatus(models.EmailStatusQueued),
+		createDeliveryEventFunc: func(_ context.Context, event *repository.EmailEvent, _ string, _ *string) (bool, error) {
+			providerEventID = event.ProviderEventID
+			return false, nil
+		},
+	}
+	deliveryEvents := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "delivery_events"}, []string{"provider", "type"})
+	handler := New(mockRepo, &mockPublisher{},
+		WithMetrics(&metrics.Metrics{DeliveryEvents: deliveryEvents}),
+		WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")),
+	)
+
+	body := `{"RecordType":"Delivery","Recipient":"jane@example.com","MessageID":"m-7","Metadata":{"email_id":"12"}}`
+	w := postDeliveryWebhook(handler, delivery.ProviderPostmark, body, "s3cret")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if providerEventID == nil || *providerEventID != "m-7:jane@example.com" {
+		t.Errorf("expected the provider event ID to be recorded, got %v", providerEventID)
+	}
+	if got := counterValue(t, deliveryEvents.WithLabelValues(delivery.ProviderPostmark, string(delivery.EventDelivered))); got != 0 {
+		t.Errorf("expected a duplicate not to be counted, got %v", got)
+	}
+}
+
+func TestDeliveryWebhook_UnknownEmail(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")))
+
+	w := postDeliveryWebhook(handler, delivery.ProviderPostmark, postmarkDelivery, "s3cret")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestDeliveryWebhook_Errors(t *testing.T) {
+	failingRepo := &mockRepository{
+		createSuppressionFunc: func(_ context.Context, _ *repository.Suppression) error {
+			return errors.New("database error")
+		},
+	}
+	tests := []struct {
+		name     string
+		repo     *mockRepository
+		provider string
+		body     string
+		password string
+		wantCode int
+	}{
+		{"unconfigured provider", &mockRepository{}, delivery.ProviderMailgun, "{}", "s3cret", http.StatusNotFound},
+		{"bad credentials", &mockRepository{}, delivery.ProviderPostmark, postmarkDelivery, "wrong", http.StatusUnauthorized},
+		{"malformed body", &mockRepository{}, delivery.ProviderPostmark, "{", "s3cret", http.StatusBadRequest},
+		{"repository error", failingRepo, delivery.ProviderPostmark, postmarkHardBounce, "s3cret", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(tt.repo, &mockPublisher{}, WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")))
+
+			w := postDeliveryWebhook(handler, tt.provider, tt.body, tt.password)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+func TestCreateContactMessage_DuplicateIgnored(t *testing.T) {
+	hashes := map[string]bool{}
+	var since time.Time
+	published := 0
+	mockRepo := &mockRepository{
+		createDedupedFunc: func(_ context.Context, email *repository.Email, s time.Time) (bool, error) {
+			since = s
+			if hashes[*email.ContentHash] {
+				return false, nil
+			}
+			hashes[*email.ContentHash] = true
+			return true, nil
+		},
+	}
+	mockPub := &mockPublisher{publishFunc: func(_ context.Context, _ any) error {
+		published++
+		return nil
+	}}
+	duplicates := prometheus.NewCounter(prometheus.CounterOpts{Name: "duplicates"})
+	handler := New(mockRepo, mockPub,
+		WithDuplicateWindow(10*time.Minute),
+		WithMetrics(&metrics.Metrics{ContactDuplicates: duplicates}),
+	)
+
+	body := `{"name":"Jane","email":"jane@example.com","subject":"Hello","message":"Hi there, nice portfolio"}`
+	resubmitted := `{"name":"Jane D","email":"JANE@example.com","subject":"Hello ","message":"Hi there, nice portfolio\r\n"}`
+	for _, b := range []string{body, resubmitted} {
+		if code := postContact(t, handler, b); code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+	}
+
+	if len(hashes) != 1 {
+		t.Errorf("expected the resubmission to hash the same, got %d hashes", len(hashes))
+	}
+	if published != 1 {
+		t.Errorf("expected one message to be published, got %d", published)
+	}
+	if got := counterValue(t, duplicates); got != 1 {
+		t.Errorf("duplicates counter = %v, want 1", got)
+	}
+	if d := time.Since(since); d < 10*time.Minute || d > 11*time.Minute {
+		t.Errorf("expected duplicates to be looked up over the window, got since %v ago", d)
+	}
+}
+
+func TestCreateContactMessage_DifferentMessageNotDuplicate(t *testing.T) {
+	a := contactContentHash(models.ContactMessageCreate{Email: "jane@example.com", Subject: "Hello", Message: "First"})
+	b := contactContentHash(models.ContactMessageCreate{Email: "jane@example.com", Subject: "Hello", Message: "Second"})
+	c := contactContentHash(models.ContactMessageCreate{Email: "jane@example.com", Subject: "HelloFirst", Message: ""})
+	if *a == *b || *a == *c {
+		t.Error("expected different content to hash differently")
+	}
+}
+
+func TestCreateContactMessage_DuplicateCheckDisabled(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+		createDedupedFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+			t.Error("expected no duplicate lookup without a window")
+			return false, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	body := `{"name":"Jane","email":"jane@example.com","subject":"Hello","message":"Hi there, nice portfolio"}`
+	if code := postContact(t, handler, body); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if created == nil || created.ContentHash == nil {
+		t.Errorf("expected message to be stored with its content hash, got %+v", created)
+	}
+}
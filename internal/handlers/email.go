@@ -1,15 +1,21 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtemplate"
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtypes"
+	"github.com/GunarsK-portfolio/messaging-api/internal/plaintext"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
 	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
-	"github.com/GunarsK-portfolio/portfolio-common/logger"
 	"github.com/GunarsK-portfolio/portfolio-common/models"
-
-	"github.com/GunarsK-portfolio/portfolio-common/renderer"
 )
 
 // SendEmailRequest is the DTO for the S2S email endpoint
@@ -17,58 +23,225 @@ type SendEmailRequest struct {
 	Type           string            `json:"type" binding:"required"`
 	RecipientEmail string            `json:"recipient_email" binding:"required,email"`
 	Data           map[string]string `json:"data" binding:"required"`
+	// Locale selects localized templates (e.g. lv-LV, falling back to lv, then the default)
+	Locale string `json:"locale,omitempty" binding:"omitempty,max=35"`
+	// Attachments are stored with the email and delivered alongside the rendered body
+	Attachments []AttachmentInput `json:"attachments,omitempty" binding:"omitempty,dive"`
+	// SendAt schedules delivery for a later time (RFC 3339); omitted or past times send immediately
+	SendAt *time.Time `json:"send_at,omitempty"`
 }
 
 // SendEmail godoc
 // @Summary Send a templated email (S2S)
-// @Description Renders a template and queues an email for delivery. Requires emails:edit scope.
+// @Description Renders the active database template for the locale (or the built-in one) and queues an email for delivery. Requires emails:edit scope. Missing data keys are listed in missing_keys; unsupported locales are rejected; suppressed recipients get 422 with suppression_reason.
 // @Tags Emails
 // @Accept json
 // @Produce json
 // @Param email body SendEmailRequest true "Email request"
+// @Param Idempotency-Key header string false "Unique key per logical request; retries with the same key and body replay the original response"
 // @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /emails [post]
 func (h *Handler) SendEmail(c *gin.Context) {
 	var req SendEmailRequest
+	h.limitAttachmentBody(c, true)
 	if err := c.ShouldBindJSON(&req); err != nil {
-		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		respondBindError(c, err)
+		return
+	}
+
+	sendAt, ok := h.resolveSendAt(c, req.SendAt)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	idempotencyKey := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
 		return
 	}
+	// Keys are scoped per caller, so services cannot replay each other's emails
+	caller := c.GetString("username")
+
+	var requestHash string
+	if idempotencyKey != "" {
+		var err error
+		if requestHash, err = hashSendEmailRequest(req); err != nil {
+			commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create email")
+			return
+		}
+
+		stored, err := h.repo.GetIdempotencyKey(ctx, caller, idempotencyKey)
+		if err != nil {
+			commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create email")
+			return
+		}
+		if stored != nil {
+			respondIdempotentReplay(c, stored, requestHash)
+			return
+		}
+	}
 
-	subject, ok := renderer.SubjectForType(req.Type)
+	emailType, ok := lookupEmailType(c, req.Type, req.Data)
+	if !ok {
+		return
+	}
+	if !h.checkRecipientSuppressed(c, req.RecipientEmail) {
+		return
+	}
+	tag, ok := h.resolveLocale(c, req.Locale)
 	if !ok {
-		commonhandlers.RespondError(c, http.StatusBadRequest, "unsupported email type: "+req.Type)
 		return
 	}
 
-	html, err := renderer.Render(req.Type, req.Data)
+	uploads, err := decodeAttachmentInputs(req.Attachments)
+	if err == nil {
+		err = h.validateAttachments(uploads)
+	}
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	rendered, err := h.renderEmail(ctx, emailType, tag, req.Data)
 	if err != nil {
 		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to render template")
 		return
 	}
 
-	email := &models.Email{
-		Type:           req.Type,
-		RecipientEmail: &req.RecipientEmail,
-		Subject:        subject,
-		Message:        html,
-		Status:         models.EmailStatusPending,
+	email := &repository.Email{
+		Email: models.Email{
+			Type:           req.Type,
+			RecipientEmail: &req.RecipientEmail,
+			Subject:        rendered.Subject,
+			Message:        rendered.HTML,
+			Status:         models.EmailStatusPending,
+		},
+		TextBody:          &rendered.Text,
+		TemplateVersionID: rendered.TemplateVersionID,
+	}
+	if sendAt != nil {
+		email.Status = repository.EmailStatusScheduled
+		email.SendAt = sendAt
+	}
+	if tag != "" {
+		email.Locale = &tag
+	}
+	if len(uploads) > 0 {
+		if email.Attachments, err = h.storeAttachments(c, uploads); err != nil {
+			commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create email")
+			return
+		}
 	}
 
-	if err := h.repo.CreateEmail(c.Request.Context(), email); err != nil {
+	if idempotencyKey == "" {
+		err = h.repo.CreateEmail(ctx, email)
+	} else {
+		err = h.repo.CreateEmailWithIdempotencyKey(ctx, email, &repository.IdempotencyKey{
+			Caller:      caller,
+			Key:         idempotencyKey,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().Add(h.idempotencyTTL),
+		})
+	}
+	if err != nil {
+		h.discardAttachments(c, email.Attachments)
+	}
+	if errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the insert; answer as its replay
+		stored, lookupErr := h.repo.GetIdempotencyKey(ctx, caller, idempotencyKey)
+		if lookupErr != nil || stored == nil {
+			commonhandlers.RespondError(c, http.StatusConflict, "A request with this Idempotency-Key is already in progress")
+			return
+		}
+		respondIdempotentReplay(c, stored, requestHash)
+		return
+	}
+	if err != nil {
 		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create email")
 		return
 	}
 
-	event := models.EmailEvent{EmailID: email.ID}
-	if err := h.publisher.Publish(c.Request.Context(), event); err != nil {
-		logger.GetLogger(c).Error("Failed to publish email to queue", "error", err, "emailId", email.ID)
+	// Scheduled emails are published by the scheduler once due
+	if sendAt == nil {
+		h.publishEmailEvent(c, email.ID)
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"id": email.ID, "message": "Email queued"})
+	c.JSON(http.StatusCreated, sendEmailResponse(email.ID))
+}
+
+// resolveSendAt returns the time to schedule an email for, or nil to send it now.
+// A send_at further ahead than maxScheduleAhead gets 400 and ok=false.
+func (h *Handler) resolveSendAt(c *gin.Context, sendAt *time.Time) (*time.Time, bool) {
+	now := time.Now()
+	if sendAt == nil || !sendAt.After(now) {
+		return nil, true
+	}
+	if sendAt.Sub(now) > h.maxScheduleAhead {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "send_at must be at most "+h.maxScheduleAhead.String()+" ahead")
+		return nil, false
+	}
+	at := sendAt.UTC()
+	return &at, true
+}
+
+// sendEmailResponse is the 201 body for POST /emails, shared with idempotent replays
+func sendEmailResponse(id int64) gin.H {
+	return gin.H{"id": id, "message": "Email queued"}
+}
+
+// renderedEmail is an email type rendered with request data
+type renderedEmail struct {
+	Subject string
+	HTML    string
+	Text    string
+	// TemplateVersionID and TemplateVersion are set when a database template was used
+	TemplateVersionID *int64
+	TemplateVersion   *int
+}
+
+// renderEmail renders the type's active database template for the locale's fallback chain,
+// falling back to the built-in renderer
+func (h *Handler) renderEmail(ctx context.Context, emailType emailtypes.Type, tag string, data map[string]string) (*renderedEmail, error) {
+	version, err := h.repo.GetActiveTemplateVersion(ctx, emailType.Name, templateLocales(tag))
+	if err != nil {
+		return nil, err
+	}
+
+	if version != nil {
+		out, err := emailtemplate.Render(version.Subject, version.HTMLBody, version.TextBody, data)
+		if err != nil {
+			return nil, fmt.Errorf("render template version %d: %w", version.ID, err)
+		}
+		text := out.Text
+		if text == "" {
+			text = plaintext.FromHTML(out.HTML)
+		}
+		return &renderedEmail{
+			Subject:           out.Subject,
+			HTML:              out.HTML,
+			Text:              text,
+			TemplateVersionID: &version.ID,
+			TemplateVersion:   &version.Version,
+		}, nil
+	}
+
+	html, err := emailtypes.Render(emailType.Name, data)
+	if err != nil {
+		return nil, err
+	}
+	return &renderedEmail{
+		Subject: emailType.Subject,
+		HTML:    html,
+		Text:    plaintext.FromHTML(html),
+	}, nil
 }
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+)
+
+// GetEmailEvents godoc
+// @Summary Get an email's event timeline
+// @Description Returns everything that happened to an email, oldest first: created, published, publish_failed, sending, sent, failed, retried, cancelled, approved, rejected, released, and provider delivered, bounced and complained events. Requires emails:read scope.
+// @Tags Emails
+// @Produce json
+// @Param id path int true "Email ID"
+// @Success 200 {array} repository.EmailEvent
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/{id}/events [get]
+func (h *Handler) GetEmailEvents(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	events, err := h.repo.GetEmailEvents(c.Request.Context(), id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Email not found", "Failed to retrieve email events")
+		return
+	}
+	if events == nil {
+		events = []repository.EmailEvent{}
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// recordEmailEvent appends an event the repository cannot observe itself (e.g. a failed publish).
+// The timeline is informational, so failures are logged rather than failing the request.
+func (h *Handler) recordEmailEvent(c *gin.Context, emailID int64, eventType string, details map[string]any) {
+	if err := h.repo.CreateEmailEvent(c.Request.Context(), emailID, eventType, details); err != nil {
+		logger.GetLogger(c).Warn("Failed to record email event", "error", err, "emailId", emailID, "type", eventType)
+	}
+}
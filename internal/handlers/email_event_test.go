@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/delivery"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+func TestGetEmailEvents(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailEventsFunc: func(_ context.Context, emailID int64) ([]repository.EmailEvent, error) {
+			return []repository.EmailEvent{
+				{ID: 1, EmailID: emailID, Type: repository.EmailEventCreated},
+				{ID: 2, EmailID: emailID, Type: repository.EmailEventPublished},
+			}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/:id/events", handler.GetEmailEvents)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/5/events", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var events []repository.EmailEvent
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != repository.EmailEventCreated || events[1].EmailID != 5 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestGetEmailEvents_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		err      error
+		wantCode int
+	}{
+		{"invalid id", "/api/v1/emails/abc/events", nil, http.StatusBadRequest},
+		{"unknown email", "/api/v1/emails/9/events", fmt.Errorf("failed to get events for email 9: %w", gorm.ErrRecordNotFound), http.StatusNotFound},
+		{"repository error", "/api/v1/emails/9/events", errors.New("database error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				getEmailEventsFunc: func(_ context.Context, _ int64) ([]repository.EmailEvent, error) {
+					return nil, tt.err
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+			router := setupTestRouter()
+			router.GET("/api/v1/emails/:id/events", handler.GetEmailEvents)
+
+			w := performRequest(router, http.MethodGet, tt.path, nil)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestSendEmail_PublishFailureRecorded(t *testing.T) {
+	var recorded []string
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			email.ID = 3
+			return nil
+		},
+		createEmailEventFunc: func(_ context.Context, emailID int64, eventType string, details map[string]any) error {
+			recorded = append(recorded, fmt.Sprintf("%d:%s:%v", emailID, eventType, details["error"]))
+			return nil
+		},
+	}
+	mockPub := &mockPublisher{
+		publishFunc: func(_ context.Context, _ interface{}) error {
+			return errors.New("broker down")
+		},
+	}
+	handler := New(mockRepo, mockPub)
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(suppressedSendBody))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if len(recorded) != 1 || recorded[0] != "3:publish_failed:broker down" {
+		t.Errorf("expected a publish_failed event, got %v", recorded)
+	}
+}
+
+func TestDeliveryWebhook_RecordsProviderEvent(t *testing.T) {
+	var eventType string
+	var details map[string]any
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: emailInStatus(models.EmailStatusSent),
+		createDeliveryEventFunc: func(_ context.Context, event *repository.EmailEvent, _ string, _ *string) (bool, error) {
+			eventType, details = event.Type, event.Details
+			return true, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithDeliveryAdapters(delivery.NewPostmark("hook", "s3cret")))
+
+	w := postDeliveryWebhook(handler, delivery.ProviderPostmark, postmarkHardBounce, "s3cret")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if eventType != repository.EmailEventBounced || details["provider"] != delivery.ProviderPostmark || details["permanent"] != true {
+		t.Errorf("unexpected event %q %v", eventType, details)
+	}
+}
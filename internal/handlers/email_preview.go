@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+// EmailPreviewRequest is the DTO for rendering an email without sending it
+type EmailPreviewRequest struct {
+	Type string            `json:"type" binding:"required"`
+	Data map[string]string `json:"data" binding:"required"`
+	// Locale selects localized templates the same way as POST /emails
+	Locale string `json:"locale,omitempty" binding:"omitempty,max=35"`
+}
+
+// EmailPreviewResponse holds the rendered output of a template
+type EmailPreviewResponse struct {
+	Type    string `json:"type"`
+	Locale  string `json:"locale,omitempty"`
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+	// TemplateVersion is the database template version used (omitted for built-in templates)
+	TemplateVersion *int `json:"template_version,omitempty"`
+}
+
+// PreviewEmail godoc
+// @Summary Preview a templated email
+// @Description Renders the subject, HTML and plain-text body for a type and data payload using the same template POST /emails would. Nothing is persisted or published.
+// @Tags Emails
+// @Accept json
+// @Produce json
+// @Param email body EmailPreviewRequest true "Preview request"
+// @Success 200 {object} EmailPreviewResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/preview [post]
+func (h *Handler) PreviewEmail(c *gin.Context) {
+	var req EmailPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	emailType, ok := lookupEmailType(c, req.Type, req.Data)
+	if !ok {
+		return
+	}
+	tag, ok := h.resolveLocale(c, req.Locale)
+	if !ok {
+		return
+	}
+
+	rendered, err := h.renderEmail(c.Request.Context(), emailType, tag, req.Data)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to render template")
+		return
+	}
+
+	c.JSON(http.StatusOK, EmailPreviewResponse{
+		Type:            req.Type,
+		Locale:          tag,
+		Subject:         rendered.Subject,
+		HTML:            rendered.HTML,
+		Text:            rendered.Text,
+		TemplateVersion: rendered.TemplateVersion,
+	})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+// =============================================================================
+// PreviewEmail Tests
+// =============================================================================
+
+func TestPreviewEmail_Success(t *testing.T) {
+	repoCalled := false
+	published := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			repoCalled = true
+			return nil
+		},
+	}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, _ interface{}) error {
+		published = true
+		return nil
+	}}
+	handler := New(mockRepo, pub)
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails/preview", handler.PreviewEmail)
+
+	body := `{"type":"password_reset","data":{"username":"testuser","reset_url":"https://example.com/reset?token=abc"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails/preview", strings.NewReader(body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if repoCalled || published {
+		t.Error("expected preview not to persist or publish anything")
+	}
+
+	var resp EmailPreviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Subject != "Reset your password" {
+		t.Errorf("subject = %q, want %q", resp.Subject, "Reset your password")
+	}
+	if !strings.Contains(resp.HTML, "https://example.com/reset?token=abc") {
+		t.Error("expected HTML to contain reset_url")
+	}
+	if !strings.Contains(resp.Text, "testuser") || strings.Contains(resp.Text, "<") {
+		t.Errorf("expected plain-text rendering, got %q", resp.Text)
+	}
+}
+
+func TestPreviewEmail_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"missing_fields", `{}`, ""},
+		{"unsupported_type", `{"type":"unknown_type","data":{}}`, "unsupported email type"},
+		{"missing_key", `{"type":"email_verification","data":{"username":"testuser"}}`, "verify_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(&mockRepository{}, &mockPublisher{})
+
+			router := setupTestRouter()
+			router.POST("/api/v1/emails/preview", handler.PreviewEmail)
+
+			w := performRequest(router, http.MethodPost, "/api/v1/emails/preview", strings.NewReader(tt.body))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected error to mention %q, got %s", tt.want, w.Body.String())
+			}
+		})
+	}
+}
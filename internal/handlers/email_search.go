@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+// defaultSearchPageSize is the number of search results when no limit is given
+const defaultSearchPageSize = 20
+
+// EmailSearchQuery holds the query parameters for full-text email search
+type EmailSearchQuery struct {
+	Q           string    `form:"q" binding:"required,max=200"`
+	Limit       int       `form:"limit" binding:"omitempty,min=1,max=50"`
+	Offset      int       `form:"offset" binding:"omitempty,min=0,max=1000"`
+	Type        string    `form:"type" binding:"omitempty,max=50"`
+	Status      string    `form:"status" binding:"omitempty,max=50"`
+	CreatedFrom time.Time `form:"created_from"`
+	CreatedTo   time.Time `form:"created_to"`
+}
+
+// EmailSearchResponse is the response envelope for full-text email search
+type EmailSearchResponse struct {
+	Data   []repository.EmailSearchResult `json:"data"`
+	Query  string                         `json:"query"`
+	Limit  int                            `json:"limit"`
+	Offset int                            `json:"offset"`
+}
+
+// SearchEmails godoc
+// @Summary Search emails
+// @Description Full-text search over subject, message, name and sender email, ranked by relevance (admin only).
+// @Description Snippets are HTML-escaped with matches wrapped in <mark> tags.
+// @Tags Emails
+// @Produce json
+// @Param q query string true "Search query (supports quoted phrases, OR and -exclusions)"
+// @Param limit query int false "Max results (1-50, default 20)"
+// @Param offset query int false "Results to skip (max 1000)"
+// @Param type query string false "Filter by email type"
+// @Param status query string false "Filter by status" Enums(pending, queued, sent, failed, cancelled, quarantined, spam, scheduled)
+// @Param created_from query string false "Created at or after (RFC3339)"
+// @Param created_to query string false "Created before (RFC3339)"
+// @Success 200 {object} EmailSearchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/search [get]
+func (h *Handler) SearchEmails(c *gin.Context) {
+	var query EmailSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := buildSearchFilter(query)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.repo.SearchEmails(c.Request.Context(), filter)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to search emails")
+		return
+	}
+	if results == nil {
+		results = []repository.EmailSearchResult{}
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
+	}
+
+	c.JSON(http.StatusOK, EmailSearchResponse{
+		Data:   results,
+		Query:  filter.Query,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// buildSearchFilter validates search query parameters and converts them to a repository filter
+func buildSearchFilter(query EmailSearchQuery) (repository.EmailSearchFilter, error) {
+	filter := repository.EmailSearchFilter{
+		Query:  strings.TrimSpace(query.Q),
+		Type:   query.Type,
+		Status: query.Status,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	if filter.Query == "" {
+		return filter, errors.New("q must not be blank")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultSearchPageSize
+	}
+	if filter.Status != "" && !validEmailStatus(filter.Status) {
+		return filter, fmt.Errorf("invalid status: %s", filter.Status)
+	}
+	if !query.CreatedFrom.IsZero() {
+		filter.CreatedFrom = &query.CreatedFrom
+	}
+	if !query.CreatedTo.IsZero() {
+		filter.CreatedTo = &query.CreatedTo
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, errors.New("created_from must be before created_to")
+	}
+
+	return filter, nil
+}
+
+// snippetReplacer turns repository match markers into <mark> tags after escaping
+var snippetReplacer = strings.NewReplacer(
+	repository.SnippetMatchStart, "<mark>",
+	repository.SnippetMatchStop, "</mark>",
+)
+
+// highlightSnippet HTML-escapes a raw snippet and wraps matched terms in <mark> tags
+func highlightSnippet(raw string) string {
+	return snippetReplacer.Replace(html.EscapeString(raw))
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// =============================================================================
+// SearchEmails Tests
+// =============================================================================
+
+func TestSearchEmails_Success(t *testing.T) {
+	var captured repository.EmailSearchFilter
+	mockRepo := &mockRepository{
+		searchEmailsFunc: func(_ context.Context, filter repository.EmailSearchFilter) ([]repository.EmailSearchResult, error) {
+			captured = filter
+			return []repository.EmailSearchResult{
+				{
+					Email:   *createTestEmail(),
+					Rank:    0.5,
+					Snippet: "about the " + repository.SnippetMatchStart + "freelance" + repository.SnippetMatchStop + " gig",
+				},
+			}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/search", handler.SearchEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/search?q=freelance+gig&status=sent&created_from=2025-03-01T00:00:00Z", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if captured.Query != "freelance gig" {
+		t.Errorf("query = %q, want %q", captured.Query, "freelance gig")
+	}
+	if captured.Limit != defaultSearchPageSize {
+		t.Errorf("limit = %d, want %d", captured.Limit, defaultSearchPageSize)
+	}
+	if captured.Status != models.EmailStatusSent || captured.CreatedFrom == nil {
+		t.Errorf("expected status and created_from filters, got %+v", captured)
+	}
+
+	var result EmailSearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Data) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(result.Data))
+	}
+	if result.Data[0].ID != 1 || result.Data[0].Rank != 0.5 {
+		t.Errorf("unexpected result: %+v", result.Data[0])
+	}
+	if result.Data[0].Snippet != "about the <mark>freelance</mark> gig" {
+		t.Errorf("snippet = %q", result.Data[0].Snippet)
+	}
+}
+
+func TestSearchEmails_SnippetIsEscaped(t *testing.T) {
+	mockRepo := &mockRepository{
+		searchEmailsFunc: func(_ context.Context, _ repository.EmailSearchFilter) ([]repository.EmailSearchResult, error) {
+			return []repository.EmailSearchResult{
+				{Snippet: "<script>alert(1)</script> " + repository.SnippetMatchStart + "gig" + repository.SnippetMatchStop},
+			}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/search", handler.SearchEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/search?q=gig", nil)
+
+	var result EmailSearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := "&lt;script&gt;alert(1)&lt;/script&gt; <mark>gig</mark>"
+	if result.Data[0].Snippet != want {
+		t.Errorf("snippet = %q, want %q", result.Data[0].Snippet, want)
+	}
+}
+
+func TestSearchEmails_Empty(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/search", handler.SearchEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/search?q=nothing", nil)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var result EmailSearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if result.Data == nil || len(result.Data) != 0 {
+		t.Errorf("expected empty data array, got %v", result.Data)
+	}
+}
+
+func TestSearchEmails_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"missing_q", ""},
+		{"blank_q", "q=+++"},
+		{"limit_over_max", "q=test&limit=51"},
+		{"offset_over_max", "q=test&offset=1001"},
+		{"invalid_status", "q=test&status=bogus"},
+		{"inverted_range", "q=test&created_from=2025-02-01T00:00:00Z&created_to=2025-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoCalled := false
+			mockRepo := &mockRepository{
+				searchEmailsFunc: func(_ context.Context, _ repository.EmailSearchFilter) ([]repository.EmailSearchResult, error) {
+					repoCalled = true
+					return nil, nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			router := setupTestRouter()
+			router.GET("/api/v1/emails/search", handler.SearchEmails)
+
+			w := performRequest(router, http.MethodGet, "/api/v1/emails/search?"+tt.query, nil)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if repoCalled {
+				t.Error("expected repository NOT to be called for invalid query")
+			}
+		})
+	}
+}
+
+func TestSearchEmails_RepositoryError(t *testing.T) {
+	mockRepo := &mockRepository{
+		searchEmailsFunc: func(_ context.Context, _ repository.EmailSearchFilter) ([]repository.EmailSearchResult, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/emails/search", handler.SearchEmails)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/emails/search?q=test", nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// RetryEmail godoc
+// @Summary Retry a failed email
+// @Description Resets a failed email's attempts and last error and re-queues it for delivery. Requires emails:edit scope.
+// @Tags Emails
+// @Produce json
+// @Param id path int true "Email ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/{id}/retry [post]
+func (h *Handler) RetryEmail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	email, err := h.repo.GetEmailByID(ctx, id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Email not found", "Failed to retrieve email")
+		return
+	}
+
+	if msg := retryConflict(email.Status); msg != "" {
+		commonhandlers.RespondError(c, http.StatusConflict, msg)
+		return
+	}
+
+	if err := h.repo.RetryEmail(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrEmailStatusConflict) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Email status changed, reload and try again")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retry email")
+		return
+	}
+
+	h.publishEmailEvent(c, id)
+
+	h.recordEmailAction(c, actionEmailRetry, id, map[string]interface{}{
+		"previousAttempts":  email.Attempts,
+		"previousLastError": email.LastError,
+	})
+	logger.GetLogger(c).Info("Email retry triggered", "emailId", id, "username", c.GetString("username"))
+
+	c.JSON(http.StatusAccepted, gin.H{"id": id, "message": "Email queued for retry"})
+}
+
+// retryConflict explains why an email in the given status cannot be retried ("" if it can)
+func retryConflict(status string) string {
+	switch status {
+	case models.EmailStatusFailed:
+		return ""
+	case models.EmailStatusSent:
+		return "Email has already been sent"
+	case models.EmailStatusPending, models.EmailStatusQueued:
+		return "Email is already queued for delivery"
+	case repository.EmailStatusScheduled:
+		return "Email is scheduled for later delivery"
+	default:
+		return "Email cannot be retried in status " + status
+	}
+}
+
+// CancelEmail godoc
+// @Summary Cancel a pending or scheduled email
+// @Description Withdraws an email that no worker has claimed yet, or a scheduled one before its send_at, by moving it to cancelled. Requires emails:edit scope.
+// @Tags Emails
+// @Produce json
+// @Param id path int true "Email ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/{id}/cancel [post]
+func (h *Handler) CancelEmail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	email, err := h.repo.GetEmailByID(ctx, id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Email not found", "Failed to retrieve email")
+		return
+	}
+
+	if msg := cancelConflict(email.Status); msg != "" {
+		commonhandlers.RespondError(c, http.StatusConflict, msg)
+		return
+	}
+
+	if err := h.repo.CancelEmail(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrEmailStatusConflict) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Email was claimed for delivery before it could be cancelled")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to cancel email")
+		return
+	}
+
+	h.recordEmailAction(c, actionEmailCancel, id, nil)
+	logger.GetLogger(c).Info("Email cancelled", "emailId", id, "username", c.GetString("username"))
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "status": repository.EmailStatusCancelled, "message": "Email cancelled"})
+}
+
+// cancelConflict explains why an email in the given status cannot be cancelled ("" if it can)
+func cancelConflict(status string) string {
+	switch status {
+	case models.EmailStatusPending, repository.EmailStatusScheduled:
+		return ""
+	case repository.EmailStatusCancelled:
+		return "Email is already cancelled"
+	case models.EmailStatusSent:
+		return "Email has already been sent"
+	case models.EmailStatusQueued:
+		return "Email is already being delivered"
+	default:
+		return "Email cannot be cancelled in status " + status
+	}
+}
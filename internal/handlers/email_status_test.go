@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// =============================================================================
+// RetryEmail Tests
+// =============================================================================
+
+func failedTestEmail() *repository.Email {
+	email := createTestEmail()
+	email.Status = models.EmailStatusFailed
+	email.Attempts = 3
+	email.LastError = strPtr("smtp timeout")
+	return email
+}
+
+func setupRetryRouter(handler *Handler) *gin.Engine {
+	router := setupTestRouter()
+	router.POST("/api/v1/emails/:id/retry", func(c *gin.Context) {
+		c.Set("user_id", int64(7))
+		c.Set("username", "admin")
+		c.Next()
+	}, handler.RetryEmail)
+	return router
+}
+
+func TestRetryEmail_Success(t *testing.T) {
+	var retriedID int64
+	var published []int64
+	var marked []int64
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return failedTestEmail(), nil
+		},
+		retryEmailFunc: func(_ context.Context, id int64) error {
+			retriedID = id
+			return nil
+		},
+		markOutboxFunc: func(_ context.Context, emailID int64) error {
+			marked = append(marked, emailID)
+			return nil
+		},
+	}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		published = append(published, msg.(models.EmailEvent).EmailID)
+		return nil
+	}}
+	actionLog := &mockActionLog{}
+	handler := New(mockRepo, pub, WithActionLog(actionLog))
+
+	w := performRequest(setupRetryRouter(handler), http.MethodPost, "/api/v1/emails/1/retry", nil)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if retriedID != 1 {
+		t.Errorf("RetryEmail id = %d, want 1", retriedID)
+	}
+	if len(published) != 1 || published[0] != 1 {
+		t.Errorf("published = %v, want [1]", published)
+	}
+	if len(marked) != 1 {
+		t.Errorf("expected outbox entry to be marked dispatched, got %v", marked)
+	}
+
+	if len(actionLog.logs) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(actionLog.logs))
+	}
+	entry := actionLog.logs[0]
+	if entry.ActionType != actionEmailRetry {
+		t.Errorf("action type = %q, want %q", entry.ActionType, actionEmailRetry)
+	}
+	if entry.UserID == nil || *entry.UserID != 7 {
+		t.Errorf("user id = %v, want 7", entry.UserID)
+	}
+	if entry.ResourceID == nil || *entry.ResourceID != 1 {
+		t.Errorf("resource id = %v, want 1", entry.ResourceID)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(entry.Metadata, &metadata); err != nil {
+		t.Fatalf("failed to unmarshal metadata: %v", err)
+	}
+	if metadata["username"] != "admin" || metadata["previousAttempts"] != float64(3) {
+		t.Errorf("unexpected metadata: %v", metadata)
+	}
+}
+
+func TestRetryEmail_NonRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		message string
+	}{
+		{models.EmailStatusSent, "Email has already been sent"},
+		{models.EmailStatusPending, "Email is already queued for delivery"},
+		{models.EmailStatusQueued, "Email is already queued for delivery"},
+		{repository.EmailStatusScheduled, "Email is scheduled for later delivery"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			retryCalled := false
+			mockRepo := &mockRepository{
+				getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+					email := createTestEmail()
+					email.Status = tt.status
+					return email, nil
+				},
+				retryEmailFunc: func(_ context.Context, _ int64) error {
+					retryCalled = true
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			w := performRequest(setupRetryRouter(handler), http.MethodPost, "/api/v1/emails/1/retry", nil)
+
+			if w.Code != http.StatusConflict {
+				t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+			}
+			var response map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if response["error"] != tt.message {
+				t.Errorf("error = %q, want %q", response["error"], tt.message)
+			}
+			if retryCalled {
+				t.Error("expected RetryEmail NOT to be called")
+			}
+		})
+	}
+}
+
+func TestRetryEmail_ConcurrentStatusChange(t *testing.T) {
+	published := false
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return failedTestEmail(), nil
+		},
+		retryEmailFunc: func(_ context.Context, id int64) error {
+			return fmt.Errorf("failed to retry email %d: %w", id, repository.ErrEmailStatusConflict)
+		},
+	}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, _ interface{}) error {
+		published = true
+		return nil
+	}}
+	handler := New(mockRepo, pub)
+
+	w := performRequest(setupRetryRouter(handler), http.MethodPost, "/api/v1/emails/1/retry", nil)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if published {
+		t.Error("expected no event to be published")
+	}
+}
+
+func TestRetryEmail_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupRetryRouter(handler), http.MethodPost, "/api/v1/emails/999/retry", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRetryEmail_InvalidID(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	w := performRequest(setupRetryRouter(handler), http.MethodPost, "/api/v1/emails/abc/retry", nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRetryEmail_RepositoryError(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return failedTestEmail(), nil
+		},
+		retryEmailFunc: func(_ context.Context, _ int64) error {
+			return errors.New("database error")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupRetryRouter(handler), http.MethodPost, "/api/v1/emails/1/retry", nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+// =============================================================================
+// CancelEmail Tests
+// =============================================================================
+
+func setupCancelRouter(handler *Handler) *gin.Engine {
+	router := setupTestRouter()
+	router.POST("/api/v1/emails/:id/cancel", func(c *gin.Context) {
+		c.Set("user_id", int64(7))
+		c.Set("username", "admin")
+		c.Next()
+	}, handler.CancelEmail)
+	return router
+}
+
+func TestCancelEmail_Success(t *testing.T) {
+	var cancelledID int64
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return createTestEmail(), nil
+		},
+		cancelEmailFunc: func(_ context.Context, id int64) error {
+			cancelledID = id
+			return nil
+		},
+	}
+	actionLog := &mockActionLog{}
+	handler := New(mockRepo, &mockPublisher{}, WithActionLog(actionLog))
+
+	w := performRequest(setupCancelRouter(handler), http.MethodPost, "/api/v1/emails/1/cancel", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if cancelledID != 1 {
+		t.Errorf("CancelEmail id = %d, want 1", cancelledID)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response["status"] != repository.EmailStatusCancelled {
+		t.Errorf("status = %v, want %q", response["status"], repository.EmailStatusCancelled)
+	}
+
+	if len(actionLog.logs) != 1 || actionLog.logs[0].ActionType != actionEmailCancel {
+		t.Errorf("expected one %q audit entry, got %+v", actionEmailCancel, actionLog.logs)
+	}
+}
+
+func TestCancelEmail_Scheduled(t *testing.T) {
+	cancelled := false
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			email := createTestEmail()
+			email.Status = repository.EmailStatusScheduled
+			return email, nil
+		},
+		cancelEmailFunc: func(_ context.Context, _ int64) error {
+			cancelled = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupCancelRouter(handler), http.MethodPost, "/api/v1/emails/1/cancel", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !cancelled {
+		t.Error("expected the scheduled email to be cancelled")
+	}
+}
+
+func TestCancelEmail_NonCancellableStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		message string
+	}{
+		{models.EmailStatusSent, "Email has already been sent"},
+		{models.EmailStatusQueued, "Email is already being delivered"},
+		{repository.EmailStatusCancelled, "Email is already cancelled"},
+		{models.EmailStatusFailed, "Email cannot be cancelled in status failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			cancelCalled := false
+			mockRepo := &mockRepository{
+				getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+					email := createTestEmail()
+					email.Status = tt.status
+					return email, nil
+				},
+				cancelEmailFunc: func(_ context.Context, _ int64) error {
+					cancelCalled = true
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			w := performRequest(setupCancelRouter(handler), http.MethodPost, "/api/v1/emails/1/cancel", nil)
+
+			if w.Code != http.StatusConflict {
+				t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+			}
+			var response map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if response["error"] != tt.message {
+				t.Errorf("error = %q, want %q", response["error"], tt.message)
+			}
+			if cancelCalled {
+				t.Error("expected CancelEmail NOT to be called")
+			}
+		})
+	}
+}
+
+func TestCancelEmail_ClaimedByWorker(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return createTestEmail(), nil
+		},
+		cancelEmailFunc: func(_ context.Context, id int64) error {
+			return fmt.Errorf("failed to cancel email %d: %w", id, repository.ErrEmailStatusConflict)
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupCancelRouter(handler), http.MethodPost, "/api/v1/emails/1/cancel", nil)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestCancelEmail_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupCancelRouter(handler), http.MethodPost, "/api/v1/emails/999/cancel", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestCancelEmail_RepositoryError(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return createTestEmail(), nil
+		},
+		cancelEmailFunc: func(_ context.Context, _ int64) error {
+			return errors.New("database error")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupCancelRouter(handler), http.MethodPost, "/api/v1/emails/1/cancel", nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
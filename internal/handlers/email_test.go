@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// sendBodyAt is a valid POST /emails body scheduled for sendAt
+func sendBodyAt(sendAt time.Time) string {
+	return fmt.Sprintf(`{"type":"email_verification","recipient_email":"user@example.com",`+
+		`"data":{"username":"test","verify_url":"https://example.com/verify"},"send_at":%q}`, sendAt.Format(time.RFC3339))
+}
+
+func TestSendEmail_SendAt(t *testing.T) {
+	tests := []struct {
+		name          string
+		sendAt        time.Time
+		wantStatus    string
+		wantScheduled bool
+	}{
+		{"future", time.Now().Add(72 * time.Hour), repository.EmailStatusScheduled, true},
+		{"past sends now", time.Now().Add(-time.Minute), models.EmailStatusPending, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *repository.Email
+			published := false
+			mockRepo := &mockRepository{
+				createEmailFunc: func(_ context.Context, email *repository.Email) error {
+					email.ID = 7
+					created = email
+					return nil
+				},
+			}
+			mockPub := &mockPublisher{
+				publishFunc: func(_ context.Context, _ interface{}) error {
+					published = true
+					return nil
+				},
+			}
+			handler := New(mockRepo, mockPub)
+			router := setupTestRouter()
+			router.POST("/api/v1/emails", handler.SendEmail)
+
+			w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(sendBodyAt(tt.sendAt)))
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			if created.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", created.Status, tt.wantStatus)
+			}
+			if (created.SendAt != nil) != tt.wantScheduled {
+				t.Errorf("SendAt = %v, want set %v", created.SendAt, tt.wantScheduled)
+			}
+			if published == tt.wantScheduled {
+				t.Errorf("published = %v, want %v", published, !tt.wantScheduled)
+			}
+		})
+	}
+}
+
+func TestSendEmail_SendAtTooFarAhead(t *testing.T) {
+	created := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			created = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithMaxScheduleAhead(24*time.Hour))
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(sendBodyAt(time.Now().Add(48*time.Hour))))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "send_at must be at most 24h0m0s ahead") {
+		t.Errorf("unexpected body %s", w.Body.String())
+	}
+	if created {
+		t.Error("expected no email to be created")
+	}
+}
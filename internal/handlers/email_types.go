@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtypes"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+// EmailTypesResponse lists the email types accepted by POST /emails
+type EmailTypesResponse struct {
+	Data []emailtypes.Type `json:"data"`
+}
+
+// GetEmailTypes godoc
+// @Summary List supported email types
+// @Description Returns each email type POST /emails accepts, its subject and the data keys its template requires
+// @Tags Emails
+// @Produce json
+// @Success 200 {object} EmailTypesResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security BearerAuth
+// @Router /email-types [get]
+func (h *Handler) GetEmailTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, EmailTypesResponse{Data: emailtypes.All()})
+}
+
+// lookupEmailType resolves a requested type and checks its data, writing a 400 if either is invalid
+func lookupEmailType(c *gin.Context, name string, data map[string]string) (emailtypes.Type, bool) {
+	emailType, ok := emailtypes.Lookup(name)
+	if !ok {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "unsupported email type: "+name)
+		return emailType, false
+	}
+
+	if missing := emailType.MissingKeys(data); len(missing) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":        "missing required data keys: " + strings.Join(missing, ", "),
+			"missing_keys": missing,
+		})
+		return emailType, false
+	}
+	return emailType, true
+}
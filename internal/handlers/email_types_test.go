@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// =============================================================================
+// GetEmailTypes Tests
+// =============================================================================
+
+func TestGetEmailTypes_Success(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/email-types", handler.GetEmailTypes)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/email-types", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Data []struct {
+			Type         string   `json:"type"`
+			Subject      string   `json:"subject"`
+			RequiredKeys []string `json:"required_keys"`
+			Placeholders []string `json:"placeholders"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	found := false
+	for _, typ := range resp.Data {
+		if typ.Type != models.EmailTypePasswordReset {
+			continue
+		}
+		found = true
+		if typ.Subject != "Reset your password" {
+			t.Errorf("subject = %q", typ.Subject)
+		}
+		if strings.Join(typ.RequiredKeys, ",") != "username,reset_url" {
+			t.Errorf("required_keys = %v", typ.RequiredKeys)
+		}
+		if len(typ.Placeholders) == 0 {
+			t.Error("expected placeholders to be listed")
+		}
+	}
+	if !found {
+		t.Errorf("expected password_reset in catalog, got %+v", resp.Data)
+	}
+}
+
+// =============================================================================
+// SendEmail Required Keys Tests
+// =============================================================================
+
+func TestSendEmail_MissingRequiredKeys(t *testing.T) {
+	createCalled := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			createCalled = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"password_reset","recipient_email":"user@example.com","data":{"username":"","other":"x"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	if createCalled {
+		t.Error("expected no email to be created")
+	}
+
+	var resp struct {
+		Error       string   `json:"error"`
+		MissingKeys []string `json:"missing_keys"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if strings.Join(resp.MissingKeys, ",") != "username,reset_url" {
+		t.Errorf("missing_keys = %v, want [username reset_url]", resp.MissingKeys)
+	}
+	if !strings.Contains(resp.Error, "username, reset_url") {
+		t.Errorf("error = %q, expected it to list the missing keys", resp.Error)
+	}
+}
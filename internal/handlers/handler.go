@@ -1,24 +1,283 @@
 package handlers
 
 import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/blocklist"
+	"github.com/GunarsK-portfolio/messaging-api/internal/captcha"
+	"github.com/GunarsK-portfolio/messaging-api/internal/delivery"
+	"github.com/GunarsK-portfolio/messaging-api/internal/maildomain"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/ratelimit"
 	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/messaging-api/internal/spam"
+	"github.com/GunarsK-portfolio/messaging-api/internal/storage"
+	"github.com/GunarsK-portfolio/portfolio-common/audit"
 	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
 	"github.com/GunarsK-portfolio/portfolio-common/queue"
+	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
+)
+
+// Audit log values for admin actions on emails, the blocklist and suppressions
+const (
+	auditSource        = "messaging-api"
+	auditResourceEmail = "email"
+	actionEmailRetry   = "email_retry"
+	actionEmailCancel  = "email_cancel"
+	actionEmailApprove = "email_approve"
+	actionEmailReject  = "email_reject"
+
+	auditResourceBlocklistEntry = "blocklist_entry"
+	actionBlocklistCreate       = "blocklist_create"
+	actionBlocklistUpdate       = "blocklist_update"
+	actionBlocklistDelete       = "blocklist_delete"
+
+	auditResourceSuppression = "suppression"
+	actionSuppressionCreate  = "suppression_create"
+	actionSuppressionLift    = "suppression_lift"
 )
 
+// defaultMaxScheduleAhead bounds send_at on POST /emails when no limit is configured
+const defaultMaxScheduleAhead = 90 * 24 * time.Hour
+
+// defaultBlocklistCacheTTL bounds how long another replica's blocklist changes take to apply here
+const defaultBlocklistCacheTTL = time.Minute
+
 // Handler holds dependencies for HTTP handlers
 type Handler struct {
 	repo      repository.Repository
 	publisher queue.Publisher
+	actionLog commonrepo.ActionLogRepository
+	metrics   *metrics.Metrics
+
+	// idempotencyTTL is how long Idempotency-Key values on POST /emails are honoured
+	idempotencyTTL time.Duration
+	// maxScheduleAhead is how far in the future send_at on POST /emails may be
+	maxScheduleAhead time.Duration
+
+	// attachmentStore holds uploaded blobs; nil disables attachments
+	attachmentStore  storage.Store
+	attachmentLimits AttachmentLimits
+
+	// supportedLocales are the canonical language tags POST /emails and templates accept
+	supportedLocales []string
+
+	// spamFilter scores contact messages; formTokens issues the timing tokens it checks (nil disables GET /contact/token)
+	spamFilter *spam.Pipeline
+	formTokens *spam.TimingToken
+	// captcha verifies captchaToken on POST /contact; nil disables CAPTCHA
+	captcha captcha.Verifier
+	// senderDomains checks that contact senders' domains accept mail; nil disables the check.
+	// Undeliverable senders get 400 when rejectUndeliverable is set and are quarantined otherwise.
+	senderDomains       *maildomain.Checker
+	rejectUndeliverable bool
+	// blocklist caches the blocklist checked on POST /contact; it is invalidated by blocklist changes
+	blocklist         *blocklist.Cache
+	blocklistCacheTTL time.Duration
+	// duplicateWindow is how long an identical contact submission is ignored as a duplicate (0 disables the check)
+	duplicateWindow time.Duration
+	// senderLimit rate-limits POST /contact per sender address; nil disables it
+	senderLimit *ratelimit.Limiter
+
+	// contactAck enables the contact_ack auto-reply, sent at most once per contactAckThrottle to an address
+	contactAck         bool
+	contactAckThrottle time.Duration
+
+	// deliveryAdapters parse POST /webhooks/delivery/{provider}, keyed by provider; providers without one get 404
+	deliveryAdapters map[string]delivery.Adapter
+
+	// legacyEmailList returns email listings as a bare array instead of a paged envelope
+	legacyEmailList bool
+}
+
+// Option configures optional Handler behavior
+type Option func(*Handler)
+
+// WithLegacyEmailList makes GET /emails and /messages return a bare array (pre-pagination shape)
+func WithLegacyEmailList(enabled bool) Option {
+	return func(h *Handler) {
+		h.legacyEmailList = enabled
+	}
+}
+
+// WithIdempotencyTTL sets how long Idempotency-Key values on POST /emails are honoured
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(h *Handler) {
+		if ttl > 0 {
+			h.idempotencyTTL = ttl
+		}
+	}
+}
+
+// WithMaxScheduleAhead sets how far in the future POST /emails may schedule delivery with send_at
+func WithMaxScheduleAhead(d time.Duration) Option {
+	return func(h *Handler) {
+		if d > 0 {
+			h.maxScheduleAhead = d
+		}
+	}
+}
+
+// WithSupportedLocales sets the language tags accepted in request locales
+func WithSupportedLocales(locales []string) Option {
+	return func(h *Handler) {
+		if len(locales) > 0 {
+			h.supportedLocales = locales
+		}
+	}
+}
+
+// WithAttachments enables attachments on POST /contact and POST /emails, stored in store and bounded by limits
+func WithAttachments(store storage.Store, limits AttachmentLimits) Option {
+	return func(h *Handler) {
+		h.attachmentStore = store
+		h.attachmentLimits = limits
+	}
+}
+
+// WithSpamFilter replaces the default honeypot-only spam check on POST /contact
+func WithSpamFilter(filter *spam.Pipeline) Option {
+	return func(h *Handler) {
+		if filter != nil {
+			h.spamFilter = filter
+		}
+	}
+}
+
+// WithFormTokens enables GET /contact/token, issuing the timing tokens checked by the spam filter
+func WithFormTokens(tokens *spam.TimingToken) Option {
+	return func(h *Handler) {
+		h.formTokens = tokens
+	}
+}
+
+// WithCaptcha requires a CAPTCHA token on POST /contact, checked by verifier
+func WithCaptcha(verifier captcha.Verifier) Option {
+	return func(h *Handler) {
+		h.captcha = verifier
+	}
+}
+
+// WithSenderDomainCheck checks that contact senders' domains accept mail, rejecting or quarantining those that do not
+func WithSenderDomainCheck(checker *maildomain.Checker, reject bool) Option {
+	return func(h *Handler) {
+		h.senderDomains = checker
+		h.rejectUndeliverable = reject
+	}
+}
+
+// WithBlocklistCacheTTL sets how long the cached blocklist is used before it is reloaded
+func WithBlocklistCacheTTL(ttl time.Duration) Option {
+	return func(h *Handler) {
+		if ttl > 0 {
+			h.blocklistCacheTTL = ttl
+		}
+	}
+}
+
+// WithDuplicateWindow ignores contact submissions identical to one received within window
+func WithDuplicateWindow(window time.Duration) Option {
+	return func(h *Handler) {
+		h.duplicateWindow = window
+	}
+}
+
+// WithSenderRateLimit limits POST /contact submissions per sender address
+func WithSenderRateLimit(limiter *ratelimit.Limiter) Option {
+	return func(h *Handler) {
+		h.senderLimit = limiter
+	}
+}
+
+// WithContactAck enables the contact_ack auto-reply to contact form senders, throttled per address
+func WithContactAck(enabled bool, throttle time.Duration) Option {
+	return func(h *Handler) {
+		h.contactAck = enabled
+		h.contactAckThrottle = throttle
+	}
+}
+
+// WithDeliveryAdapters accepts provider delivery webhooks for the given adapters
+func WithDeliveryAdapters(adapters ...delivery.Adapter) Option {
+	return func(h *Handler) {
+		h.deliveryAdapters = make(map[string]delivery.Adapter, len(adapters))
+		for _, adapter := range adapters {
+			h.deliveryAdapters[adapter.Provider()] = adapter
+		}
+	}
+}
+
+// WithMetrics records messaging-specific metrics such as quarantine review outcomes
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(h *Handler) {
+		h.metrics = m
+	}
+}
+
+// WithActionLog records admin actions (e.g. manual retries) in the shared audit log
+func WithActionLog(actionLog commonrepo.ActionLogRepository) Option {
+	return func(h *Handler) {
+		h.actionLog = actionLog
+	}
 }
 
 // New creates a new Handler instance
-func New(repo repository.Repository, publisher queue.Publisher) *Handler {
-	return &Handler{
-		repo:      repo,
-		publisher: publisher,
+func New(repo repository.Repository, publisher queue.Publisher, opts ...Option) *Handler {
+	h := &Handler{
+		repo:              repo,
+		publisher:         publisher,
+		idempotencyTTL:    defaultIdempotencyTTL,
+		maxScheduleAhead:  defaultMaxScheduleAhead,
+		supportedLocales:  defaultSupportedLocales,
+		spamFilter:        defaultSpamFilter(),
+		blocklistCacheTTL: defaultBlocklistCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	h.blocklist = blocklist.NewCache(repo.GetBlocklistEntries, h.blocklistCacheTTL)
+	return h
 }
 
 // setLocationHeader wraps the common helper
 var setLocationHeader = commonhandlers.SetLocationHeader
+
+// publishEmailEvent eagerly publishes an EmailEvent and marks its outbox entry dispatched.
+// Failures are only logged: the outbox relay retries anything left undispatched.
+func (h *Handler) publishEmailEvent(c *gin.Context, emailID int64) {
+	ctx := c.Request.Context()
+	event := models.EmailEvent{EmailID: emailID}
+	if err := h.publisher.Publish(ctx, event); err != nil {
+		logger.GetLogger(c).Error("Failed to publish email to queue, outbox relay will retry", "error", err, "emailId", emailID)
+		h.recordEmailEvent(c, emailID, repository.EmailEventPublishFailed, map[string]any{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.MarkOutboxDispatched(ctx, emailID); err != nil {
+		logger.GetLogger(c).Warn("Failed to mark outbox entry dispatched", "error", err, "emailId", emailID)
+	}
+}
+
+// recordEmailAction writes an audit entry for an admin action on an email.
+// The acting user is taken from the auth context; failures are logged by the audit helper.
+func (h *Handler) recordEmailAction(c *gin.Context, actionType string, emailID int64, metadata map[string]interface{}) {
+	h.recordAction(c, actionType, auditResourceEmail, emailID, metadata)
+}
+
+// recordAction writes an admin action on a resource to the audit log, if one is configured
+func (h *Handler) recordAction(c *gin.Context, actionType, resourceType string, resourceID int64, metadata map[string]interface{}) {
+	if h.actionLog == nil {
+		return
+	}
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["username"] = c.GetString("username")
+
+	source := auditSource
+	_ = audit.LogFromContext(c, h.actionLog, actionType, &resourceType, &resourceID, &source, metadata)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+const (
+	// idempotencyKeyHeader lets S2S callers retry POST /emails without queuing duplicates
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader marks a response replayed from a stored idempotency key
+	idempotentReplayHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength bounds the stored key size
+	maxIdempotencyKeyLength = 255
+	// defaultIdempotencyTTL is how long keys are honoured when no TTL is configured
+	defaultIdempotencyTTL = 24 * time.Hour
+)
+
+// hashSendEmailRequest fingerprints a request so a reused key can be matched against its original body.
+// Hashes the decoded request (map keys sorted by encoding/json) so formatting differences do not matter.
+func hashSendEmailRequest(req SendEmailRequest) (string, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// respondIdempotentReplay answers a request whose key is already stored:
+// the original 201 for the same body, 422 for a different one
+func respondIdempotentReplay(c *gin.Context, stored *repository.IdempotencyKey, requestHash string) {
+	if stored.RequestHash != requestHash {
+		commonhandlers.RespondError(c, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request body")
+		return
+	}
+
+	c.Header(idempotentReplayHeader, "true")
+	c.JSON(http.StatusCreated, sendEmailResponse(stored.EmailID))
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+// =============================================================================
+// SendEmail Idempotency Tests
+// =============================================================================
+
+const idempotentBody = `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+
+func performIdempotentRequest(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKeyHeader, key)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func storedKeyFor(t *testing.T, body string, emailID int64) *repository.IdempotencyKey {
+	t.Helper()
+	var req SendEmailRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("failed to unmarshal body: %v", err)
+	}
+	hash, err := hashSendEmailRequest(req)
+	if err != nil {
+		t.Fatalf("failed to hash request: %v", err)
+	}
+	return &repository.IdempotencyKey{Key: "key-1", RequestHash: hash, EmailID: emailID}
+}
+
+func TestSendEmail_IdempotencyKey_StoredWithEmail(t *testing.T) {
+	var storedKey *repository.IdempotencyKey
+	plainCreateCalled := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			plainCreateCalled = true
+			return nil
+		},
+		createWithKeyFunc: func(_ context.Context, email *repository.Email, key *repository.IdempotencyKey) error {
+			email.ID = 10
+			storedKey = key
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithIdempotencyTTL(time.Hour))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	before := time.Now()
+	w := performIdempotentRequest(router, "key-1", idempotentBody)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if plainCreateCalled {
+		t.Error("expected CreateEmail NOT to be called when a key is supplied")
+	}
+	if storedKey == nil || storedKey.Key != "key-1" || len(storedKey.RequestHash) != 64 {
+		t.Fatalf("unexpected stored key: %+v", storedKey)
+	}
+	if storedKey.ExpiresAt.Before(before.Add(time.Hour)) || storedKey.ExpiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("expires_at = %v, want about one hour from now", storedKey.ExpiresAt)
+	}
+	if w.Header().Get(idempotentReplayHeader) != "" {
+		t.Error("expected first response not to be marked as a replay")
+	}
+}
+
+func TestSendEmail_IdempotencyKey_ReplaysOriginalResponse(t *testing.T) {
+	createCalled := false
+	published := false
+	mockRepo := &mockRepository{
+		getIdempotencyKeyFunc: func(_ context.Context, _, _ string) (*repository.IdempotencyKey, error) {
+			return storedKeyFor(t, idempotentBody, 10), nil
+		},
+		createWithKeyFunc: func(_ context.Context, _ *repository.Email, _ *repository.IdempotencyKey) error {
+			createCalled = true
+			return nil
+		},
+	}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, _ interface{}) error {
+		published = true
+		return nil
+	}}
+	handler := New(mockRepo, pub)
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	// Same request with different key order and whitespace
+	body := `{"recipient_email":"user@example.com", "type":"email_verification","data":{"verify_url":"https://example.com/verify","username":"testuser"}}`
+	w := performIdempotentRequest(router, "key-1", body)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if createCalled || published {
+		t.Error("expected replay not to create or publish an email")
+	}
+	if w.Header().Get(idempotentReplayHeader) != "true" {
+		t.Error("expected replayed response header")
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["id"] != float64(10) || resp["message"] != "Email queued" {
+		t.Errorf("unexpected replay body: %v", resp)
+	}
+}
+
+func TestSendEmail_IdempotencyKey_DifferentBody(t *testing.T) {
+	createCalled := false
+	mockRepo := &mockRepository{
+		getIdempotencyKeyFunc: func(_ context.Context, _, _ string) (*repository.IdempotencyKey, error) {
+			return storedKeyFor(t, idempotentBody, 10), nil
+		},
+		createWithKeyFunc: func(_ context.Context, _ *repository.Email, _ *repository.IdempotencyKey) error {
+			createCalled = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := strings.Replace(idempotentBody, "user@example.com", "other@example.com", 1)
+	w := performIdempotentRequest(router, "key-1", body)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+	if createCalled {
+		t.Error("expected no email to be created")
+	}
+}
+
+func TestSendEmail_IdempotencyKey_ConcurrentInsert(t *testing.T) {
+	lookups := 0
+	mockRepo := &mockRepository{
+		getIdempotencyKeyFunc: func(_ context.Context, _, _ string) (*repository.IdempotencyKey, error) {
+			lookups++
+			if lookups == 1 {
+				return nil, nil
+			}
+			return storedKeyFor(t, idempotentBody, 11), nil
+		},
+		createWithKeyFunc: func(_ context.Context, _ *repository.Email, _ *repository.IdempotencyKey) error {
+			return fmt.Errorf("failed to create email: %w", repository.ErrIdempotencyKeyExists)
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	w := performIdempotentRequest(router, "key-1", idempotentBody)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"id":11`) {
+		t.Errorf("expected the winning request's id, got %s", w.Body.String())
+	}
+}
+
+func TestSendEmail_IdempotencyKey_TooLong(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	w := performIdempotentRequest(router, strings.Repeat("k", maxIdempotencyKeyLength+1), idempotentBody)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestSendEmail_IdempotencyKey_ScopedToCaller(t *testing.T) {
+	var lookedUp, stored string
+	mockRepo := &mockRepository{
+		getIdempotencyKeyFunc: func(_ context.Context, caller, _ string) (*repository.IdempotencyKey, error) {
+			lookedUp = caller
+			return nil, nil
+		},
+		createWithKeyFunc: func(_ context.Context, _ *repository.Email, key *repository.IdempotencyKey) error {
+			stored = key.Caller
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", func(c *gin.Context) {
+		c.Set("username", "auth-service")
+		handler.SendEmail(c)
+	})
+
+	w := performIdempotentRequest(router, "key-1", idempotentBody)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if lookedUp != "auth-service" || stored != "auth-service" {
+		t.Errorf("expected the key to be scoped to the caller, looked up %q, stored %q", lookedUp, stored)
+	}
+}
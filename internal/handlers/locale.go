@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/locale"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+// defaultSupportedLocales are accepted when no locale list is configured
+var defaultSupportedLocales = []string{"en", "lv"}
+
+// resolveLocale canonicalizes an optional request locale ("lv_lv" becomes "lv-LV") and checks it against
+// the supported list. An empty locale selects the default templates. Writes a 400 and returns false otherwise.
+func (h *Handler) resolveLocale(c *gin.Context, raw string) (string, bool) {
+	if raw == "" {
+		return "", true
+	}
+
+	tag, err := locale.Parse(raw)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	if !locale.Supported(tag, h.supportedLocales) {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "unsupported locale: "+tag)
+		return "", false
+	}
+	return tag, true
+}
+
+// templateLocales is the template lookup order for a canonical locale: its fallback chain, then the default
+func templateLocales(tag string) []string {
+	return append(locale.Fallbacks(tag), "")
+}
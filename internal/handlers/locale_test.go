@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+// =============================================================================
+// Locale Tests
+// =============================================================================
+
+func TestSendEmail_LocaleFallbackChain(t *testing.T) {
+	var gotLocales []string
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		getActiveTemplateFunc: func(_ context.Context, _ string, locales []string) (*repository.EmailTemplateVersion, error) {
+			gotLocales = locales
+			return nil, nil
+		},
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"email_verification","recipient_email":"user@example.com","locale":"lv_lv","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if want := []string{"lv-LV", "lv", ""}; !reflect.DeepEqual(gotLocales, want) {
+		t.Errorf("expected lookup chain %v, got %v", want, gotLocales)
+	}
+	if created.Locale == nil || *created.Locale != "lv-LV" {
+		t.Errorf("expected canonical locale lv-LV persisted, got %v", created.Locale)
+	}
+}
+
+func TestSendEmail_DefaultLocale(t *testing.T) {
+	var gotLocales []string
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		getActiveTemplateFunc: func(_ context.Context, _ string, locales []string) (*repository.EmailTemplateVersion, error) {
+			gotLocales = locales
+			return nil, nil
+		},
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if want := []string{""}; !reflect.DeepEqual(gotLocales, want) {
+		t.Errorf("expected default-only lookup, got %v", gotLocales)
+	}
+	if created.Locale != nil {
+		t.Errorf("expected no locale persisted, got %q", *created.Locale)
+	}
+}
+
+func TestSendEmail_InvalidLocale(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+	}{
+		{"malformed", "latvian", "invalid locale"},
+		{"unsupported", "de-DE", "unsupported locale: de-DE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+					t.Error("email should not be created")
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			router := setupTestRouter()
+			router.POST("/api/v1/emails", handler.SendEmail)
+
+			body := `{"type":"email_verification","recipient_email":"user@example.com","locale":"` + tt.locale + `","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+			w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected error to mention %q, got %s", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSendEmail_ConfiguredLocales(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{}, WithSupportedLocales([]string{"de"}))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"email_verification","recipient_email":"user@example.com","locale":"de-AT","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+func TestPreviewEmail_Locale(t *testing.T) {
+	mockRepo := &mockRepository{
+		getActiveTemplateFunc: func(_ context.Context, _ string, locales []string) (*repository.EmailTemplateVersion, error) {
+			if locales[0] != "lv" {
+				return nil, nil
+			}
+			version := activeVerificationTemplate()
+			version.Subject = "Apstipriniet e-pastu, {{.username}}"
+			return version, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails/preview", handler.PreviewEmail)
+
+	body := `{"type":"email_verification","locale":"LV","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails/preview", strings.NewReader(body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp EmailPreviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Locale != "lv" || resp.Subject != "Apstipriniet e-pastu, testuser" {
+		t.Errorf("unexpected preview %+v", resp)
+	}
+}
+
+func TestCreateTemplate_Locale(t *testing.T) {
+	var got *repository.EmailTemplate
+	mockRepo := &mockRepository{
+		createTemplateFunc: func(_ context.Context, template *repository.EmailTemplate, _ *repository.EmailTemplateVersion, _ bool) error {
+			got = template
+			return nil
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	body := `{"type":"email_verification","locale":"lv-lv","subject":"s","htmlBody":"<p></p>"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/templates", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if got.Locale != "lv-LV" {
+		t.Errorf("expected canonical locale lv-LV, got %q", got.Locale)
+	}
+
+	body = `{"type":"email_verification","locale":"fr","subject":"s","htmlBody":"<p></p>"}`
+	w = performRequest(router, http.MethodPost, "/api/v1/templates", strings.NewReader(body))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for unsupported locale, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
 	"github.com/GunarsK-portfolio/portfolio-common/models"
 	"github.com/GunarsK-portfolio/portfolio-common/queue"
+	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
 	"github.com/gin-gonic/gin"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -18,10 +19,47 @@ import (
 // =============================================================================
 
 type mockRepository struct {
-	createEmailFunc         func(ctx context.Context, email *models.Email) error
-	getEmailsFunc           func(ctx context.Context) ([]models.Email, error)
-	getEmailByIDFunc        func(ctx context.Context, id int64) (*models.Email, error)
+	createEmailFunc         func(ctx context.Context, email *repository.Email) error
+	createThrottledFunc     func(ctx context.Context, email *repository.Email, since time.Time) (bool, error)
+	createDedupedFunc       func(ctx context.Context, email *repository.Email, since time.Time) (bool, error)
+	createWithKeyFunc       func(ctx context.Context, email *repository.Email, key *repository.IdempotencyKey) error
+	getIdempotencyKeyFunc   func(ctx context.Context, caller, key string) (*repository.IdempotencyKey, error)
+	getEmailsFunc           func(ctx context.Context, filter repository.EmailFilter) (*repository.EmailPage, error)
+	getEmailByIDFunc        func(ctx context.Context, id int64) (*repository.Email, error)
+	searchEmailsFunc        func(ctx context.Context, filter repository.EmailSearchFilter) ([]repository.EmailSearchResult, error)
 	updateEmailStatusFunc   func(ctx context.Context, id int64, status string, lastError *string) error
+	retryEmailFunc          func(ctx context.Context, id int64) error
+	cancelEmailFunc         func(ctx context.Context, id int64) error
+	approveEmailFunc        func(ctx context.Context, id int64) error
+	rejectEmailFunc         func(ctx context.Context, id int64, block *repository.BlocklistEntry) error
+	getBlocklistFunc        func(ctx context.Context) ([]repository.BlocklistEntry, error)
+	getBlocklistEntryFunc   func(ctx context.Context, id int64) (*repository.BlocklistEntry, error)
+	createBlocklistFunc     func(ctx context.Context, entry *repository.BlocklistEntry) error
+	updateBlocklistFunc     func(ctx context.Context, entry *repository.BlocklistEntry) error
+	deleteBlocklistFunc     func(ctx context.Context, id int64) error
+	getSuppressionsFunc     func(ctx context.Context, filter repository.SuppressionFilter) ([]repository.Suppression, error)
+	getSuppressionFunc      func(ctx context.Context, id int64) (*repository.Suppression, error)
+	getActiveSuppressFunc   func(ctx context.Context, address string) (*repository.Suppression, error)
+	createSuppressionFunc   func(ctx context.Context, suppression *repository.Suppression) error
+	liftSuppressionFunc     func(ctx context.Context, id int64, liftedBy *string) error
+	claimStalePendingFunc   func(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error)
+	getAttachmentFunc       func(ctx context.Context, emailID, attachmentID int64) (*repository.Attachment, error)
+	getEmailEventsFunc      func(ctx context.Context, emailID int64) ([]repository.EmailEvent, error)
+	createEmailEventFunc    func(ctx context.Context, emailID int64, eventType string, details map[string]any) error
+	createDeliveryEventFunc func(ctx context.Context, event *repository.EmailEvent, status string, lastError *string) (bool, error)
+	claimDueOutboxFunc      func(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]repository.OutboxEntry, error)
+	markOutboxFunc          func(ctx context.Context, emailID int64) error
+	recordOutboxFailureFunc func(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	releaseScheduledFunc    func(ctx context.Context, now time.Time, limit int) ([]repository.Email, error)
+	countScheduledFunc      func(ctx context.Context) (int64, error)
+	getTemplatesFunc        func(ctx context.Context) ([]repository.EmailTemplate, error)
+	getTemplateByIDFunc     func(ctx context.Context, id int64) (*repository.EmailTemplate, error)
+	createTemplateFunc      func(ctx context.Context, template *repository.EmailTemplate, version *repository.EmailTemplateVersion, activate bool) error
+	createVersionFunc       func(ctx context.Context, templateID int64, version *repository.EmailTemplateVersion, activate bool) error
+	getVersionsFunc         func(ctx context.Context, templateID int64) ([]repository.EmailTemplateVersion, error)
+	setActiveVersionFunc    func(ctx context.Context, templateID int64, version *int) error
+	deleteTemplateFunc      func(ctx context.Context, id int64) error
+	getActiveTemplateFunc   func(ctx context.Context, emailType string, locales []string) (*repository.EmailTemplateVersion, error)
 	getAllRecipientsFunc    func(ctx context.Context) ([]models.Recipient, error)
 	getActiveRecipientsFunc func(ctx context.Context) ([]models.Recipient, error)
 	getRecipientByIDFunc    func(ctx context.Context, id int64) (*models.Recipient, error)
@@ -30,27 +68,66 @@ type mockRepository struct {
 	deleteRecipientFunc     func(ctx context.Context, id int64) error
 }
 
-func (m *mockRepository) CreateEmail(ctx context.Context, email *models.Email) error {
+func (m *mockRepository) CreateEmail(ctx context.Context, email *repository.Email) error {
 	if m.createEmailFunc != nil {
 		return m.createEmailFunc(ctx, email)
 	}
 	return nil
 }
 
-func (m *mockRepository) GetEmails(ctx context.Context) ([]models.Email, error) {
-	if m.getEmailsFunc != nil {
-		return m.getEmailsFunc(ctx)
+func (m *mockRepository) CreateThrottledEmail(ctx context.Context, email *repository.Email, since time.Time) (bool, error) {
+	if m.createThrottledFunc != nil {
+		return m.createThrottledFunc(ctx, email, since)
+	}
+	return false, nil
+}
+
+func (m *mockRepository) CreateDedupedEmail(ctx context.Context, email *repository.Email, since time.Time) (bool, error) {
+	if m.createDedupedFunc != nil {
+		return m.createDedupedFunc(ctx, email, since)
+	}
+	return false, nil
+}
+
+func (m *mockRepository) CreateEmailWithIdempotencyKey(ctx context.Context, email *repository.Email, key *repository.IdempotencyKey) error {
+	if m.createWithKeyFunc != nil {
+		return m.createWithKeyFunc(ctx, email, key)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetIdempotencyKey(ctx context.Context, caller, key string) (*repository.IdempotencyKey, error) {
+	if m.getIdempotencyKeyFunc != nil {
+		return m.getIdempotencyKeyFunc(ctx, caller, key)
 	}
 	return nil, nil
 }
 
-func (m *mockRepository) GetEmailByID(ctx context.Context, id int64) (*models.Email, error) {
+func (m *mockRepository) DeleteExpiredIdempotencyKeys(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) GetEmails(ctx context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
+	if m.getEmailsFunc != nil {
+		return m.getEmailsFunc(ctx, filter)
+	}
+	return &repository.EmailPage{}, nil
+}
+
+func (m *mockRepository) GetEmailByID(ctx context.Context, id int64) (*repository.Email, error) {
 	if m.getEmailByIDFunc != nil {
 		return m.getEmailByIDFunc(ctx, id)
 	}
 	return nil, nil
 }
 
+func (m *mockRepository) SearchEmails(ctx context.Context, filter repository.EmailSearchFilter) ([]repository.EmailSearchResult, error) {
+	if m.searchEmailsFunc != nil {
+		return m.searchEmailsFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
 func (m *mockRepository) UpdateEmailStatus(ctx context.Context, id int64, status string, lastError *string) error {
 	if m.updateEmailStatusFunc != nil {
 		return m.updateEmailStatusFunc(ctx, id, status, lastError)
@@ -58,6 +135,230 @@ func (m *mockRepository) UpdateEmailStatus(ctx context.Context, id int64, status
 	return nil
 }
 
+func (m *mockRepository) RetryEmail(ctx context.Context, id int64) error {
+	if m.retryEmailFunc != nil {
+		return m.retryEmailFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) CancelEmail(ctx context.Context, id int64) error {
+	if m.cancelEmailFunc != nil {
+		return m.cancelEmailFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) ApproveEmail(ctx context.Context, id int64) error {
+	if m.approveEmailFunc != nil {
+		return m.approveEmailFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) RejectEmail(ctx context.Context, id int64, block *repository.BlocklistEntry) error {
+	if m.rejectEmailFunc != nil {
+		return m.rejectEmailFunc(ctx, id, block)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetBlocklistEntries(ctx context.Context) ([]repository.BlocklistEntry, error) {
+	if m.getBlocklistFunc != nil {
+		return m.getBlocklistFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetBlocklistEntryByID(ctx context.Context, id int64) (*repository.BlocklistEntry, error) {
+	if m.getBlocklistEntryFunc != nil {
+		return m.getBlocklistEntryFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CreateBlocklistEntry(ctx context.Context, entry *repository.BlocklistEntry) error {
+	if m.createBlocklistFunc != nil {
+		return m.createBlocklistFunc(ctx, entry)
+	}
+	return nil
+}
+
+func (m *mockRepository) UpdateBlocklistEntry(ctx context.Context, entry *repository.BlocklistEntry) error {
+	if m.updateBlocklistFunc != nil {
+		return m.updateBlocklistFunc(ctx, entry)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteBlocklistEntry(ctx context.Context, id int64) error {
+	if m.deleteBlocklistFunc != nil {
+		return m.deleteBlocklistFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetSuppressions(ctx context.Context, filter repository.SuppressionFilter) ([]repository.Suppression, error) {
+	if m.getSuppressionsFunc != nil {
+		return m.getSuppressionsFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetSuppressionByID(ctx context.Context, id int64) (*repository.Suppression, error) {
+	if m.getSuppressionFunc != nil {
+		return m.getSuppressionFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetActiveSuppression(ctx context.Context, address string) (*repository.Suppression, error) {
+	if m.getActiveSuppressFunc != nil {
+		return m.getActiveSuppressFunc(ctx, address)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CreateSuppression(ctx context.Context, suppression *repository.Suppression) error {
+	if m.createSuppressionFunc != nil {
+		return m.createSuppressionFunc(ctx, suppression)
+	}
+	return nil
+}
+
+func (m *mockRepository) LiftSuppression(ctx context.Context, id int64, liftedBy *string) error {
+	if m.liftSuppressionFunc != nil {
+		return m.liftSuppressionFunc(ctx, id, liftedBy)
+	}
+	return nil
+}
+
+func (m *mockRepository) ClaimStalePendingEmails(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error) {
+	if m.claimStalePendingFunc != nil {
+		return m.claimStalePendingFunc(ctx, olderThan, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetAttachment(ctx context.Context, emailID, attachmentID int64) (*repository.Attachment, error) {
+	if m.getAttachmentFunc != nil {
+		return m.getAttachmentFunc(ctx, emailID, attachmentID)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetEmailEvents(ctx context.Context, emailID int64) ([]repository.EmailEvent, error) {
+	if m.getEmailEventsFunc != nil {
+		return m.getEmailEventsFunc(ctx, emailID)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CreateEmailEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) error {
+	if m.createEmailEventFunc != nil {
+		return m.createEmailEventFunc(ctx, emailID, eventType, details)
+	}
+	return nil
+}
+
+func (m *mockRepository) CreateDeliveryEvent(ctx context.Context, event *repository.EmailEvent, status string, lastError *string) (bool, error) {
+	if m.createDeliveryEventFunc != nil {
+		return m.createDeliveryEventFunc(ctx, event, status, lastError)
+	}
+	return true, nil
+}
+
+func (m *mockRepository) ClaimDueOutboxEntries(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]repository.OutboxEntry, error) {
+	if m.claimDueOutboxFunc != nil {
+		return m.claimDueOutboxFunc(ctx, createdBefore, limit, claimUntil)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) MarkOutboxDispatched(ctx context.Context, emailID int64) error {
+	if m.markOutboxFunc != nil {
+		return m.markOutboxFunc(ctx, emailID)
+	}
+	return nil
+}
+
+func (m *mockRepository) RecordOutboxFailure(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	if m.recordOutboxFailureFunc != nil {
+		return m.recordOutboxFailureFunc(ctx, id, lastError, nextAttemptAt)
+	}
+	return nil
+}
+
+func (m *mockRepository) ReleaseDueScheduledEmails(ctx context.Context, now time.Time, limit int) ([]repository.Email, error) {
+	if m.releaseScheduledFunc != nil {
+		return m.releaseScheduledFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CountScheduledEmails(ctx context.Context) (int64, error) {
+	if m.countScheduledFunc != nil {
+		return m.countScheduledFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) GetTemplates(ctx context.Context) ([]repository.EmailTemplate, error) {
+	if m.getTemplatesFunc != nil {
+		return m.getTemplatesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetTemplateByID(ctx context.Context, id int64) (*repository.EmailTemplate, error) {
+	if m.getTemplateByIDFunc != nil {
+		return m.getTemplateByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CreateTemplate(ctx context.Context, template *repository.EmailTemplate, version *repository.EmailTemplateVersion, activate bool) error {
+	if m.createTemplateFunc != nil {
+		return m.createTemplateFunc(ctx, template, version, activate)
+	}
+	return nil
+}
+
+func (m *mockRepository) CreateTemplateVersion(ctx context.Context, templateID int64, version *repository.EmailTemplateVersion, activate bool) error {
+	if m.createVersionFunc != nil {
+		return m.createVersionFunc(ctx, templateID, version, activate)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetTemplateVersions(ctx context.Context, templateID int64) ([]repository.EmailTemplateVersion, error) {
+	if m.getVersionsFunc != nil {
+		return m.getVersionsFunc(ctx, templateID)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) SetActiveTemplateVersion(ctx context.Context, templateID int64, version *int) error {
+	if m.setActiveVersionFunc != nil {
+		return m.setActiveVersionFunc(ctx, templateID, version)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteTemplate(ctx context.Context, id int64) error {
+	if m.deleteTemplateFunc != nil {
+		return m.deleteTemplateFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetActiveTemplateVersion(ctx context.Context, emailType string, locales []string) (*repository.EmailTemplateVersion, error) {
+	if m.getActiveTemplateFunc != nil {
+		return m.getActiveTemplateFunc(ctx, emailType, locales)
+	}
+	return nil, nil
+}
+
 func (m *mockRepository) GetAllRecipients(ctx context.Context) ([]models.Recipient, error) {
 	if m.getAllRecipientsFunc != nil {
 		return m.getAllRecipientsFunc(ctx)
@@ -146,6 +447,35 @@ func (m *mockPublisher) Close() error {
 // Verify mock implements Publisher interface
 var _ queue.Publisher = (*mockPublisher)(nil)
 
+// =============================================================================
+// Mock Action Log
+// =============================================================================
+
+type mockActionLog struct {
+	logs []*commonrepo.ActionLog
+}
+
+func (m *mockActionLog) LogAction(log *commonrepo.ActionLog) error {
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+func (m *mockActionLog) GetActionsByType(_ string, _ int) ([]commonrepo.ActionLog, error) {
+	return nil, nil
+}
+
+func (m *mockActionLog) GetActionsByResource(_ string, _ int64) ([]commonrepo.ActionLog, error) {
+	return nil, nil
+}
+
+func (m *mockActionLog) GetActionsByUser(_ int64, _ int) ([]commonrepo.ActionLog, error) {
+	return nil, nil
+}
+
+func (m *mockActionLog) CountActionsByResource(_ string, _ int64) (int64, error) {
+	return 0, nil
+}
+
 // =============================================================================
 // Test Helpers
 // =============================================================================
@@ -169,8 +499,8 @@ func strPtr(s string) *string {
 	return &s
 }
 
-func createTestEmail() *models.Email {
-	return &models.Email{
+func createTestEmail() *repository.Email {
+	return &repository.Email{Email: models.Email{
 		ID:          1,
 		Type:        models.EmailTypeContactForm,
 		Name:        strPtr("John Doe"),
@@ -180,12 +510,12 @@ func createTestEmail() *models.Email {
 		Status:      models.EmailStatusPending,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
-	}
+	}}
 }
 
-func createTestEmails() []models.Email {
-	return []models.Email{
-		{
+func createTestEmails() []repository.Email {
+	return []repository.Email{
+		{Email: models.Email{
 			ID:          1,
 			Type:        models.EmailTypeContactForm,
 			Name:        strPtr("John Doe"),
@@ -195,8 +525,8 @@ func createTestEmails() []models.Email {
 			Status:      models.EmailStatusPending,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
-		},
-		{
+		}},
+		{Email: models.Email{
 			ID:          2,
 			Type:        models.EmailTypeContactForm,
 			Name:        strPtr("Jane Smith"),
@@ -206,7 +536,7 @@ func createTestEmails() []models.Email {
 			Status:      models.EmailStatusSent,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
-		},
+		}},
 	}
 }
 
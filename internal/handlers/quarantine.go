@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// QuarantineListQuery holds the query parameters for listing quarantined emails
+type QuarantineListQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"omitempty,max=512"`
+}
+
+// EmailRejectRequest optionally blocks the sender of a rejected email
+type EmailRejectRequest struct {
+	// Block adds the sender's address or whole domain to the blocklist
+	Block  string  `json:"block" binding:"omitempty,oneof=address domain"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// GetQuarantinedEmails godoc
+// @Summary List quarantined emails
+// @Description Returns a page of contact messages held by the spam filter, newest first, with their spam score and reasons (admin only)
+// @Tags Emails
+// @Produce json
+// @Param limit query int false "Page size (1-100, default 50)"
+// @Param cursor query string false "Opaque cursor from a previous next_cursor"
+// @Success 200 {object} EmailListResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/quarantine [get]
+func (h *Handler) GetQuarantinedEmails(c *gin.Context) {
+	var query QuarantineListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := repository.EmailFilter{
+		Status: repository.EmailStatusQuarantined,
+		Limit:  query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultEmailPageSize
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil {
+			commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.After = cursor
+	}
+
+	page, err := h.repo.GetEmails(c.Request.Context(), filter)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve quarantined emails")
+		return
+	}
+
+	emails := page.Emails
+	if emails == nil {
+		emails = []repository.Email{}
+	}
+	c.JSON(http.StatusOK, EmailListResponse{
+		Data:       emails,
+		NextCursor: encodeCursor(page.NextCursor),
+		HasMore:    page.NextCursor != nil,
+		Limit:      filter.Limit,
+	})
+}
+
+// ApproveEmail godoc
+// @Summary Approve a quarantined email
+// @Description Releases a contact message held by the spam filter: moves it to pending and queues it for delivery. Requires emails:edit scope.
+// @Tags Emails
+// @Produce json
+// @Param id path int true "Email ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/{id}/approve [post]
+func (h *Handler) ApproveEmail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	email, err := h.repo.GetEmailByID(ctx, id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Email not found", "Failed to retrieve email")
+		return
+	}
+
+	if msg := reviewConflict(email.Status); msg != "" {
+		commonhandlers.RespondError(c, http.StatusConflict, msg)
+		return
+	}
+
+	if err := h.repo.ApproveEmail(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrEmailStatusConflict) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Email status changed, reload and try again")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to approve email")
+		return
+	}
+
+	h.publishEmailEvent(c, id)
+
+	// The sender got no acknowledgement while the message was held
+	if h.contactAck && email.Type == models.EmailTypeContactForm && email.Name != nil && email.SenderEmail != nil {
+		h.sendContactAck(c, id, models.ContactMessageCreate{Name: *email.Name, Email: *email.SenderEmail})
+	}
+
+	if h.metrics != nil {
+		h.metrics.QuarantineApproved.Inc()
+	}
+	h.recordEmailAction(c, actionEmailApprove, id, map[string]interface{}{
+		"spamScore":   email.SpamScore,
+		"spamReasons": email.SpamReasons,
+	})
+	logger.GetLogger(c).Info("Quarantined email approved", "emailId", id, "username", c.GetString("username"))
+
+	c.JSON(http.StatusAccepted, gin.H{"id": id, "message": "Email approved and queued for delivery"})
+}
+
+// RejectEmail godoc
+// @Summary Reject a quarantined email
+// @Description Marks a contact message held by the spam filter as spam. Set block to "address" or "domain" to also add the sender to the blocklist. Requires emails:edit scope.
+// @Tags Emails
+// @Accept json
+// @Produce json
+// @Param id path int true "Email ID"
+// @Param request body EmailRejectRequest false "Blocklist options"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /emails/{id}/reject [post]
+func (h *Handler) RejectEmail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	// The body is optional: an empty request rejects without blocking
+	var req EmailRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	email, err := h.repo.GetEmailByID(ctx, id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Email not found", "Failed to retrieve email")
+		return
+	}
+
+	if msg := reviewConflict(email.Status); msg != "" {
+		commonhandlers.RespondError(c, http.StatusConflict, msg)
+		return
+	}
+
+	block, err := senderBlocklistEntry(c, email, req)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.repo.RejectEmail(ctx, id, block); err != nil {
+		if errors.Is(err, repository.ErrEmailStatusConflict) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Email status changed, reload and try again")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to reject email")
+		return
+	}
+
+	if block != nil {
+		h.blocklist.Invalidate()
+	}
+	if h.metrics != nil {
+		h.metrics.QuarantineRejected.Inc()
+	}
+	metadata := map[string]interface{}{"spamScore": email.SpamScore}
+	resp := gin.H{"id": id, "status": repository.EmailStatusSpam, "message": "Email rejected as spam"}
+	if block != nil {
+		metadata["blocked"] = block.Kind + ":" + block.Value
+		resp["blocked"] = block
+	}
+	h.recordEmailAction(c, actionEmailReject, id, metadata)
+	logger.GetLogger(c).Info("Quarantined email rejected", "emailId", id, "username", c.GetString("username"))
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// reviewConflict explains why an email in the given status cannot be approved or rejected ("" if it can)
+func reviewConflict(status string) string {
+	switch status {
+	case repository.EmailStatusQuarantined:
+		return ""
+	case repository.EmailStatusSpam:
+		return "Email has already been rejected"
+	default:
+		return "Email is not quarantined"
+	}
+}
+
+// senderBlocklistEntry builds the blocklist entry a reject request asks for (nil if none)
+func senderBlocklistEntry(c *gin.Context, email *repository.Email, req EmailRejectRequest) (*repository.BlocklistEntry, error) {
+	if req.Block == "" {
+		return nil, nil
+	}
+	if email.SenderEmail == nil {
+		return nil, errors.New("email has no sender to block")
+	}
+
+	entry := &repository.BlocklistEntry{
+		Kind:   req.Block,
+		Value:  strings.ToLower(*email.SenderEmail),
+		Reason: req.Reason,
+	}
+	if req.Block == repository.BlocklistKindDomain {
+		at := strings.LastIndexByte(entry.Value, '@')
+		if at < 0 || at == len(entry.Value)-1 {
+			return nil, errors.New("sender address has no domain to block")
+		}
+		entry.Value = entry.Value[at+1:]
+	}
+	if username := c.GetString("username"); username != "" {
+		entry.CreatedBy = &username
+	}
+	return entry, nil
+}
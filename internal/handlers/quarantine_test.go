@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// =============================================================================
+// Quarantine Review Tests
+// =============================================================================
+
+func quarantinedTestEmail() *repository.Email {
+	email := createTestEmail()
+	email.Status = repository.EmailStatusQuarantined
+	score := 6
+	email.SpamScore = &score
+	email.SpamReasons = []string{"links: 4 links"}
+	return email
+}
+
+func newQuarantineMetrics() *metrics.Metrics {
+	return &metrics.Metrics{
+		QuarantineApproved: prometheus.NewCounter(prometheus.CounterOpts{Name: "approved"}),
+		QuarantineRejected: prometheus.NewCounter(prometheus.CounterOpts{Name: "rejected"}),
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := c.Write(&metric); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func setupQuarantineRouter(handler *Handler) *gin.Engine {
+	router := setupTestRouter()
+	withUser := func(c *gin.Context) {
+		c.Set("user_id", int64(7))
+		c.Set("username", "admin")
+		c.Next()
+	}
+	router.GET("/api/v1/emails/quarantine", handler.GetQuarantinedEmails)
+	router.POST("/api/v1/emails/:id/approve", withUser, handler.ApproveEmail)
+	router.POST("/api/v1/emails/:id/reject", withUser, handler.RejectEmail)
+	return router
+}
+
+func TestGetQuarantinedEmails(t *testing.T) {
+	var filter repository.EmailFilter
+	mockRepo := &mockRepository{
+		getEmailsFunc: func(_ context.Context, f repository.EmailFilter) (*repository.EmailPage, error) {
+			filter = f
+			return &repository.EmailPage{
+				Emails:     []repository.Email{*quarantinedTestEmail()},
+				NextCursor: &repository.EmailCursor{CreatedAt: time.Now(), ID: 1},
+			}, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupQuarantineRouter(handler), http.MethodGet, "/api/v1/emails/quarantine?limit=10", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if filter.Status != repository.EmailStatusQuarantined || filter.Limit != 10 {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+	var resp struct {
+		Data []struct {
+			SpamScore   int      `json:"spamScore"`
+			SpamReasons []string `json:"spamReasons"`
+		} `json:"data"`
+		NextCursor *string `json:"next_cursor"`
+		HasMore    bool    `json:"has_more"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].SpamScore != 6 || len(resp.Data[0].SpamReasons) != 1 {
+		t.Errorf("unexpected data %+v", resp.Data)
+	}
+	if !resp.HasMore || resp.NextCursor == nil {
+		t.Error("expected a next cursor")
+	}
+}
+
+func TestGetQuarantinedEmails_InvalidCursor(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	w := performRequest(setupQuarantineRouter(handler), http.MethodGet, "/api/v1/emails/quarantine?cursor=bogus", nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestApproveEmail_Success(t *testing.T) {
+	var approvedID int64
+	var published []int64
+	var ack *repository.Email
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return quarantinedTestEmail(), nil
+		},
+		approveEmailFunc: func(_ context.Context, id int64) error {
+			approvedID = id
+			return nil
+		},
+		createThrottledFunc: func(_ context.Context, email *repository.Email, _ time.Time) (bool, error) {
+			ack = email
+			email.ID = 2
+			return true, nil
+		},
+	}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		published = append(published, msg.(models.EmailEvent).EmailID)
+		return nil
+	}}
+	actionLog := &mockActionLog{}
+	m := newQuarantineMetrics()
+	handler := New(mockRepo, pub, WithActionLog(actionLog), WithMetrics(m), WithContactAck(true, time.Hour))
+
+	w := performRequest(setupQuarantineRouter(handler), http.MethodPost, "/api/v1/emails/1/approve", nil)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if approvedID != 1 {
+		t.Errorf("ApproveEmail id = %d, want 1", approvedID)
+	}
+	if len(published) != 2 || published[0] != 1 || published[1] != 2 {
+		t.Errorf("expected approved email and its ack published, got %v", published)
+	}
+	if ack == nil || ack.InReplyToID == nil || *ack.InReplyToID != 1 || *ack.RecipientEmail != "john@example.com" {
+		t.Errorf("expected delayed acknowledgement to the sender, got %+v", ack)
+	}
+	if got := counterValue(t, m.QuarantineApproved); got != 1 {
+		t.Errorf("approved counter = %v, want 1", got)
+	}
+	if len(actionLog.logs) != 1 || actionLog.logs[0].ActionType != actionEmailApprove {
+		t.Errorf("expected an %s audit entry, got %v", actionEmailApprove, actionLog.logs)
+	}
+}
+
+func TestApproveEmail_NotQuarantined(t *testing.T) {
+	tests := []struct {
+		status  string
+		message string
+	}{
+		{models.EmailStatusPending, "Email is not quarantined"},
+		{models.EmailStatusSent, "Email is not quarantined"},
+		{repository.EmailStatusSpam, "Email has already been rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+					email := createTestEmail()
+					email.Status = tt.status
+					return email, nil
+				},
+				approveEmailFunc: func(_ context.Context, _ int64) error {
+					t.Error("ApproveEmail should not be called")
+					return nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			w := performRequest(setupQuarantineRouter(handler), http.MethodPost, "/api/v1/emails/1/approve", nil)
+
+			if w.Code != http.StatusConflict {
+				t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("expected %q, got %s", tt.message, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestApproveEmail_LostRace(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return quarantinedTestEmail(), nil
+		},
+		approveEmailFunc: func(_ context.Context, _ int64) error {
+			return repository.ErrEmailStatusConflict
+		},
+	}
+	m := newQuarantineMetrics()
+	handler := New(mockRepo, &mockPublisher{}, WithMetrics(m))
+
+	w := performRequest(setupQuarantineRouter(handler), http.MethodPost, "/api/v1/emails/1/approve", nil)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if got := counterValue(t, m.QuarantineApproved); got != 0 {
+		t.Errorf("approved counter = %v, want 0", got)
+	}
+}
+
+func TestRejectEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantBlock *repository.BlocklistEntry
+	}{
+		{"no body", "", nil},
+		{"no block", `{}`, nil},
+		{"block address", `{"block":"address","reason":"SEO spam"}`, &repository.BlocklistEntry{Kind: "address", Value: "john@example.com", Reason: strPtr("SEO spam")}},
+		{"block domain", `{"block":"domain"}`, &repository.BlocklistEntry{Kind: "domain", Value: "example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rejectedID int64
+			var block *repository.BlocklistEntry
+			mockRepo := &mockRepository{
+				getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+					return quarantinedTestEmail(), nil
+				},
+				rejectEmailFunc: func(_ context.Context, id int64, b *repository.BlocklistEntry) error {
+					rejectedID, block = id, b
+					return nil
+				},
+			}
+			m := newQuarantineMetrics()
+			handler := New(mockRepo, &mockPublisher{}, WithMetrics(m))
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			w := performRequest(setupQuarantineRouter(handler), http.MethodPost, "/api/v1/emails/1/reject", body)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if rejectedID != 1 {
+				t.Errorf("RejectEmail id = %d, want 1", rejectedID)
+			}
+			if got := counterValue(t, m.QuarantineRejected); got != 1 {
+				t.Errorf("rejected counter = %v, want 1", got)
+			}
+			if tt.wantBlock == nil {
+				if block != nil {
+					t.Errorf("expected no blocklist entry, got %+v", block)
+				}
+				return
+			}
+			if block == nil || block.Kind != tt.wantBlock.Kind || block.Value != tt.wantBlock.Value {
+				t.Fatalf("expected blocklist entry %+v, got %+v", tt.wantBlock, block)
+			}
+			if (tt.wantBlock.Reason == nil) != (block.Reason == nil) || block.CreatedBy == nil || *block.CreatedBy != "admin" {
+				t.Errorf("unexpected reason or creator on %+v", block)
+			}
+		})
+	}
+}
+
+func TestRejectEmail_InvalidBlock(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	w := performRequest(setupQuarantineRouter(handler), http.MethodPost, "/api/v1/emails/1/reject", strings.NewReader(`{"block":"everything"}`))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRejectEmail_RepositoryError(t *testing.T) {
+	mockRepo := &mockRepository{
+		getEmailByIDFunc: func(_ context.Context, _ int64) (*repository.Email, error) {
+			return quarantinedTestEmail(), nil
+		},
+		rejectEmailFunc: func(_ context.Context, _ int64, _ *repository.BlocklistEntry) error {
+			return errors.New("database error")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupQuarantineRouter(handler), http.MethodPost, "/api/v1/emails/1/reject", nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestCreateContactMessage_BlockedSenderDropped(t *testing.T) {
+	loads := 0
+	createCalled := false
+	mockRepo := &mockRepository{
+		getBlocklistFunc: func(_ context.Context) ([]repository.BlocklistEntry, error) {
+			loads++
+			return []repository.BlocklistEntry{{ID: 1, Kind: repository.BlocklistKindDomain, Value: "example.com"}}, nil
+		},
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			createCalled = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if createCalled {
+		t.Error("expected message from blocked sender not to be stored")
+	}
+	if loads != 1 {
+		t.Errorf("expected the blocklist to be loaded once and cached, got %d loads", loads)
+	}
+}
+
+func TestCreateContactMessage_CIDRBlockIgnoresForgedForwardedFor(t *testing.T) {
+	createCalled := false
+	mockRepo := &mockRepository{
+		getBlocklistFunc: func(_ context.Context) ([]repository.BlocklistEntry, error) {
+			return []repository.BlocklistEntry{{ID: 1, Kind: repository.BlocklistKindCIDR, Value: "203.0.113.0/24"}}, nil
+		},
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			createCalled = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/contact", strings.NewReader(contactAckBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.RemoteAddr = "203.0.113.7:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if createCalled {
+		t.Error("expected message from a blocked IP with a forged X-Forwarded-For not to be stored")
+	}
+}
+
+func TestCreateContactMessage_BlocklistErrorFailsOpen(t *testing.T) {
+	createCalled := false
+	mockRepo := &mockRepository{
+		getBlocklistFunc: func(_ context.Context) ([]repository.BlocklistEntry, error) {
+			return nil, errors.New("database error")
+		},
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			createCalled = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if !createCalled {
+		t.Error("expected message to be stored when the blocklist lookup fails")
+	}
+}
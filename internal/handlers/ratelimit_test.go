@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/ratelimit"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+func TestCreateContactMessage_SenderRateLimited(t *testing.T) {
+	created := 0
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			created++
+			return nil
+		},
+	}
+	rejected := prometheus.NewCounter(prometheus.CounterOpts{Name: "sender_rejected"})
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), "contact_sender",
+		ratelimit.Limit{Burst: 2, Period: time.Hour}, rejected)
+	handler := New(mockRepo, &mockPublisher{}, WithSenderRateLimit(limiter))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+	body := `{"name":"Jane","email":"%s","subject":"Hello","message":"Hi there, nice portfolio"}`
+	post := func(email string) (int, string) {
+		w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(strings.Replace(body, "%s", email, 1)))
+		return w.Code, w.Header().Get("Retry-After")
+	}
+
+	for _, email := range []string{"jane@example.com", "Jane@Example.com"} {
+		if code, _ := post(email); code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+	}
+
+	// The address is matched case-insensitively, so the third submission is over the limit
+	code, retryAfter := post("JANE@example.com")
+	if code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, code)
+	}
+	if retryAfter != "1800" {
+		t.Errorf("Retry-After = %q, want 1800", retryAfter)
+	}
+	if code, _ := post("john@example.com"); code != http.StatusCreated {
+		t.Errorf("other sender: expected status %d, got %d", http.StatusCreated, code)
+	}
+	if created != 3 {
+		t.Errorf("expected 3 stored messages, got %d", created)
+	}
+	if got := counterValue(t, rejected); got != 1 {
+		t.Errorf("rejected counter = %v, want 1", got)
+	}
+}
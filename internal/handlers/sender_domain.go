@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+)
+
+// undeliverableSenderReason is recorded on messages held because the sender's domain accepts no mail
+const undeliverableSenderReason = "sender domain: does not accept mail"
+
+// checkSenderDomain checks that the sender's domain has mail exchangers when the check is configured.
+// An undeliverable domain gets 400 and ok=false in reject mode, or is let through with flagged=true
+// to be quarantined otherwise. Lookup failures are logged and let the message through unflagged.
+func (h *Handler) checkSenderDomain(c *gin.Context, address string) (flagged, ok bool) {
+	if h.senderDomains == nil {
+		return false, true
+	}
+
+	deliverable, err := h.senderDomains.Deliverable(c.Request.Context(), address)
+	switch {
+	case err != nil:
+		logger.GetLogger(c).Warn("Failed to check sender domain", "error", err)
+		return false, true
+	case deliverable:
+		return false, true
+	case h.rejectUndeliverable:
+		logger.GetLogger(c).Info("Contact message from undeliverable sender domain rejected")
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Email address domain does not accept mail")
+		return false, false
+	default:
+		return true, true
+	}
+}
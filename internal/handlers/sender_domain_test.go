@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/maildomain"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// fakeResolver knows example.com's mail exchanger; every other domain does not exist unless err is set
+type fakeResolver struct {
+	err error
+}
+
+func (f *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if name == "example.com" {
+		return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (f *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+const typoDomainContactBody = `{"name":"Jane","email":"jane@exmaple.com","subject":"Hello","message":"Hi there, nice portfolio"}`
+
+func TestCreateContactMessage_UndeliverableSenderFlagged(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	checker := maildomain.New(&fakeResolver{}, time.Hour, time.Second)
+	handler := New(mockRepo, &mockPublisher{}, WithSenderDomainCheck(checker, false))
+
+	if code := postContact(t, handler, typoDomainContactBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if created == nil || created.Status != repository.EmailStatusQuarantined {
+		t.Fatalf("expected message to be quarantined, got %+v", created)
+	}
+	if len(created.SpamReasons) != 1 || created.SpamReasons[0] != undeliverableSenderReason {
+		t.Errorf("unexpected spam reasons %v", created.SpamReasons)
+	}
+}
+
+func TestCreateContactMessage_UndeliverableSenderRejected(t *testing.T) {
+	createCalled := false
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			createCalled = true
+			return nil
+		},
+	}
+	checker := maildomain.New(&fakeResolver{}, time.Hour, time.Second)
+	handler := New(mockRepo, &mockPublisher{}, WithSenderDomainCheck(checker, true))
+
+	router := setupTestRouter()
+	router.POST("/api/v1/contact", handler.CreateContactMessage)
+	w := performRequest(router, http.MethodPost, "/api/v1/contact", strings.NewReader(typoDomainContactBody))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "does not accept mail") {
+		t.Errorf("unexpected body %s", w.Body.String())
+	}
+	if createCalled {
+		t.Error("expected message not to be stored")
+	}
+}
+
+func TestCreateContactMessage_DeliverableSenderAccepted(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	checker := maildomain.New(&fakeResolver{}, time.Hour, time.Second)
+	handler := New(mockRepo, &mockPublisher{}, WithSenderDomainCheck(checker, true))
+
+	body := strings.Replace(typoDomainContactBody, "exmaple.com", "example.com", 1)
+	if code := postContact(t, handler, body); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if created == nil || created.Status != models.EmailStatusPending {
+		t.Errorf("expected pending email, got %+v", created)
+	}
+}
+
+func TestCreateContactMessage_SenderDomainLookupFailure(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	resolver := &fakeResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+	handler := New(mockRepo, &mockPublisher{},
+		WithSenderDomainCheck(maildomain.New(resolver, time.Hour, time.Second), true))
+
+	if code := postContact(t, handler, typoDomainContactBody); code != http.StatusCreated {
+		t.Fatalf("expected lookup failures to let the message through, got %d", code)
+	}
+	if created == nil || created.Status != models.EmailStatusPending {
+		t.Errorf("expected pending email, got %+v", created)
+	}
+}
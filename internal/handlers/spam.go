@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/spam"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// Default spam thresholds, used until WithSpamFilter configures a full pipeline
+const (
+	defaultSpamQuarantineScore = 5
+	defaultSpamRejectScore     = 10
+)
+
+// defaultSpamFilter only checks the honeypot, matching the contact form's original behavior
+func defaultSpamFilter() *spam.Pipeline {
+	return spam.NewPipeline(defaultSpamQuarantineScore, defaultSpamRejectScore, spam.Honeypot{})
+}
+
+// ContactMessageRequest is the POST /contact body: the shared contact message plus anti-spam fields
+type ContactMessageRequest struct {
+	models.ContactMessageCreate
+	// FormToken is the timing token from GET /contact/token, issued when the form was loaded
+	FormToken string `json:"formToken" binding:"omitempty,max=200"`
+	// CaptchaToken is the response of the CAPTCHA widget, required when CAPTCHA is enabled
+	CaptchaToken string `json:"captchaToken" binding:"omitempty,max=4096"`
+}
+
+// spamSubmission converts a contact request into the spam pipeline's input
+func (r ContactMessageRequest) spamSubmission() spam.Submission {
+	return spam.Submission{
+		Name:      r.Name,
+		Email:     r.Email,
+		Subject:   r.Subject,
+		Message:   r.Message,
+		Honeypot:  r.Honeypot,
+		FormToken: r.FormToken,
+	}
+}
+
+// senderBlocked reports whether the sender's address or client IP is on the blocklist.
+// Lookup failures are logged and let the message through to the spam filter.
+func (h *Handler) senderBlocked(c *gin.Context, address string) bool {
+	entry, blocked, err := h.blocklist.Match(c.Request.Context(), address, c.ClientIP())
+	if err != nil {
+		logger.GetLogger(c).Warn("Failed to check blocklist", "error", err)
+		return false
+	}
+	if blocked {
+		logger.GetLogger(c).Info("Contact sender matched blocklist", "blocklistEntryId", entry.ID, "kind", entry.Kind)
+	}
+	return blocked
+}
+
+// FormTokenResponse is a contact form timing token
+type FormTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// GetFormToken godoc
+// @Summary Get a contact form token
+// @Description Issues a signed token recording when the contact form was loaded (public endpoint). Send it back as formToken on POST /contact; submissions without one, or sent too quickly, score as likely spam.
+// @Tags Contact
+// @Produce json
+// @Success 200 {object} FormTokenResponse
+// @Failure 404 {object} map[string]string
+// @Router /contact/token [get]
+func (h *Handler) GetFormToken(c *gin.Context) {
+	if h.formTokens == nil {
+		commonhandlers.RespondError(c, http.StatusNotFound, "Form tokens are not enabled")
+		return
+	}
+
+	token, expiresAt := h.formTokens.Issue()
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, FormTokenResponse{Token: token, ExpiresAt: expiresAt})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/messaging-api/internal/spam"
+)
+
+// testSpamFilter quarantines at 5 and rejects at 10; each link beyond one scores 3
+func testSpamFilter() *spam.Pipeline {
+	return spam.NewPipeline(5, 10, spam.Honeypot{}, spam.LinkCount{Allowed: 1, PerLink: 3})
+}
+
+func TestCreateContactMessage_SpamVerdicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantCreated bool
+		wantStatus  string
+		wantScore   int
+		wantPublish bool
+	}{
+		{"accepted", "One link http://a.example", true, "pending", 0, true},
+		{"quarantined", "http://a.example http://b.example http://c.example", true, repository.EmailStatusQuarantined, 6, false},
+		{"rejected", "http://a.example http://b.example http://c.example http://d.example http://e.example", false, "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *repository.Email
+			published := false
+			ackSent := false
+			mockRepo := &mockRepository{
+				createEmailFunc: func(_ context.Context, email *repository.Email) error {
+					created = email
+					return nil
+				},
+				createThrottledFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+					ackSent = true
+					return true, nil
+				},
+			}
+			mockPub := &mockPublisher{
+				publishFunc: func(_ context.Context, _ any) error {
+					published = true
+					return nil
+				},
+			}
+			handler := New(mockRepo, mockPub, WithSpamFilter(testSpamFilter()), WithContactAck(true, time.Hour))
+
+			body, _ := json.Marshal(map[string]string{
+				"name": "Jane", "email": "jane@example.com", "subject": "Hello", "message": tt.message,
+			})
+			if code := postContact(t, handler, string(body)); code != http.StatusCreated {
+				t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+			}
+
+			if (created != nil) != tt.wantCreated {
+				t.Fatalf("expected created=%v, got %v", tt.wantCreated, created != nil)
+			}
+			if published != tt.wantPublish || ackSent != tt.wantPublish {
+				t.Errorf("expected publish and ack %v, got publish=%v ack=%v", tt.wantPublish, published, ackSent)
+			}
+			if created == nil {
+				return
+			}
+			if created.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, created.Status)
+			}
+			if created.SpamScore == nil || *created.SpamScore != tt.wantScore {
+				t.Errorf("expected spam score %d, got %v", tt.wantScore, created.SpamScore)
+			}
+			if tt.wantScore > 0 && (len(created.SpamReasons) != 1 || created.SpamReasons[0] != "links: 3 links") {
+				t.Errorf("unexpected spam reasons %v", created.SpamReasons)
+			}
+		})
+	}
+}
+
+func TestCreateContactMessage_FormTokenChecked(t *testing.T) {
+	tokens := spam.NewTimingToken([]byte("test-secret-key-at-least-32-chars!"), time.Minute, time.Hour, 2, 5)
+	token, _ := tokens.Issue()
+
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithSpamFilter(spam.NewPipeline(5, 10, tokens)))
+
+	// Submitted well under the minimum delay after the form loaded
+	body := `{"name":"Jane","email":"jane@example.com","subject":"Hello","message":"Hi there","formToken":"` + token + `"}`
+	if code := postContact(t, handler, body); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if created == nil || created.Status != repository.EmailStatusQuarantined {
+		t.Fatalf("expected too-fast submission to be quarantined, got %+v", created)
+	}
+}
+
+func TestGetFormToken(t *testing.T) {
+	tokens := spam.NewTimingToken([]byte("test-secret-key-at-least-32-chars!"), 0, time.Hour, 2, 5)
+	handler := New(&mockRepository{}, &mockPublisher{}, WithFormTokens(tokens))
+
+	router := setupTestRouter()
+	router.GET("/api/v1/contact/token", handler.GetFormToken)
+	w := performRequest(router, http.MethodGet, "/api/v1/contact/token", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Error("expected token response not to be cached")
+	}
+	var resp FormTokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !strings.Contains(resp.Token, ".") || time.Until(resp.ExpiresAt) < 59*time.Minute {
+		t.Errorf("unexpected token response %+v", resp)
+	}
+	if r := tokens.Score(context.Background(), spam.Submission{FormToken: resp.Token}); r.Score != 0 {
+		t.Errorf("expected issued token to be accepted, got %+v", r)
+	}
+}
+
+func TestGetFormToken_Disabled(t *testing.T) {
+	handler := New(&mockRepository{}, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.GET("/api/v1/contact/token", handler.GetFormToken)
+	w := performRequest(router, http.MethodGet, "/api/v1/contact/token", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+)
+
+// SuppressionListQuery holds the query parameters of GET /suppressions
+type SuppressionListQuery struct {
+	Email         string `form:"email" binding:"omitempty,max=255"`
+	Reason        string `form:"reason" binding:"omitempty,oneof=hard_bounce complaint manual unsubscribe"`
+	IncludeLifted bool   `form:"include_lifted"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset        int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// SuppressionCreate is the body of POST /suppressions
+type SuppressionCreate struct {
+	Email  string  `json:"email" binding:"required,email,max=255"`
+	Reason string  `json:"reason" binding:"required,oneof=hard_bounce complaint manual unsubscribe"`
+	Note   *string `json:"note" binding:"omitempty,max=500"`
+}
+
+// checkRecipientSuppressed answers 422 with the suppression reason if the recipient is suppressed.
+// It reports whether the email may be sent.
+func (h *Handler) checkRecipientSuppressed(c *gin.Context, address string) bool {
+	suppression, err := h.repo.GetActiveSuppression(c.Request.Context(), address)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create email")
+		return false
+	}
+	if suppression == nil {
+		return true
+	}
+
+	logger.GetLogger(c).Info("Email to suppressed recipient refused", "suppressionId", suppression.ID, "reason", suppression.Reason)
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":              "recipient is suppressed: " + suppression.Reason,
+		"suppression_reason": suppression.Reason,
+		"suppressed_at":      suppression.CreatedAt,
+	})
+	return false
+}
+
+// GetSuppressions godoc
+// @Summary List recipient suppressions
+// @Description Returns active suppressions, newest first; set include_lifted to include lifted ones. POST /emails refuses suppressed recipients with 422. Requires emails:read scope.
+// @Tags Suppressions
+// @Produce json
+// @Param email query string false "Filter by address"
+// @Param reason query string false "Filter by reason" Enums(hard_bounce, complaint, manual, unsubscribe)
+// @Param include_lifted query bool false "Include lifted suppressions"
+// @Param limit query int false "Page size (default and max 100)"
+// @Param offset query int false "Number of suppressions to skip"
+// @Success 200 {array} repository.Suppression
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /suppressions [get]
+func (h *Handler) GetSuppressions(c *gin.Context) {
+	var query SuppressionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suppressions, err := h.repo.GetSuppressions(c.Request.Context(), repository.SuppressionFilter{
+		Email:         query.Email,
+		Reason:        query.Reason,
+		IncludeLifted: query.IncludeLifted,
+		Limit:         query.Limit,
+		Offset:        query.Offset,
+	})
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve suppressions")
+		return
+	}
+	if suppressions == nil {
+		suppressions = []repository.Suppression{}
+	}
+	c.JSON(http.StatusOK, suppressions)
+}
+
+// CreateSuppression godoc
+// @Summary Suppress a recipient
+// @Description Stops emails to an address; POST /emails then answers 422 with the reason. Requires emails:edit scope.
+// @Tags Suppressions
+// @Accept json
+// @Produce json
+// @Param suppression body SuppressionCreate true "Suppression"
+// @Success 201 {object} repository.Suppression
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /suppressions [post]
+func (h *Handler) CreateSuppression(c *gin.Context) {
+	var req SuppressionCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suppression := &repository.Suppression{Email: req.Email, Reason: req.Reason, Note: req.Note}
+	if username := c.GetString("username"); username != "" {
+		suppression.CreatedBy = &username
+	}
+	if err := h.repo.CreateSuppression(c.Request.Context(), suppression); err != nil {
+		if errors.Is(err, repository.ErrSuppressionExists) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Address is already suppressed")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create suppression")
+		return
+	}
+
+	h.recordAction(c, actionSuppressionCreate, auditResourceSuppression, suppression.ID,
+		map[string]interface{}{"email": suppression.Email, "reason": suppression.Reason})
+
+	setLocationHeader(c, suppression.ID)
+	c.JSON(http.StatusCreated, suppression)
+}
+
+// LiftSuppression godoc
+// @Summary Lift a suppression
+// @Description Allows emails to the address again. The suppression is kept as history with liftedAt set. Requires emails:edit scope.
+// @Tags Suppressions
+// @Produce json
+// @Param id path int true "Suppression ID"
+// @Success 200 {object} repository.Suppression
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /suppressions/{id}/lift [post]
+func (h *Handler) LiftSuppression(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var liftedBy *string
+	if username := c.GetString("username"); username != "" {
+		liftedBy = &username
+	}
+	ctx := c.Request.Context()
+	if err := h.repo.LiftSuppression(ctx, id, liftedBy); err != nil {
+		if errors.Is(err, repository.ErrSuppressionLifted) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Suppression has already been lifted")
+			return
+		}
+		commonhandlers.HandleRepositoryError(c, err, "Suppression not found", "Failed to lift suppression")
+		return
+	}
+
+	h.recordAction(c, actionSuppressionLift, auditResourceSuppression, id, nil)
+
+	suppression, err := h.repo.GetSuppressionByID(ctx, id)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve suppression")
+		return
+	}
+	c.JSON(http.StatusOK, suppression)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+const suppressedSendBody = `{"type":"email_verification","recipient_email":"User@Example.com","data":{"username":"test","verify_url":"https://example.com/verify"}}`
+
+func setupSuppressionRouter(handler *Handler) *gin.Engine {
+	router := setupTestRouter()
+	router.GET("/api/v1/suppressions", handler.GetSuppressions)
+	router.POST("/api/v1/suppressions", handler.CreateSuppression)
+	router.POST("/api/v1/suppressions/:id/lift", handler.LiftSuppression)
+	router.POST("/api/v1/emails", handler.SendEmail)
+	return router
+}
+
+func TestSendEmail_SuppressedRecipient(t *testing.T) {
+	var checked string
+	created := false
+	mockRepo := &mockRepository{
+		getActiveSuppressFunc: func(_ context.Context, address string) (*repository.Suppression, error) {
+			checked = address
+			return &repository.Suppression{ID: 4, Email: "user@example.com", Reason: repository.SuppressionReasonHardBounce}, nil
+		},
+		createEmailFunc: func(_ context.Context, _ *repository.Email) error {
+			created = true
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupSuppressionRouter(handler), http.MethodPost, "/api/v1/emails", strings.NewReader(suppressedSendBody))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["suppression_reason"] != repository.SuppressionReasonHardBounce {
+		t.Errorf("expected suppression_reason hard_bounce, got %v", resp["suppression_reason"])
+	}
+	if checked != "User@Example.com" {
+		t.Errorf("expected the recipient to be checked, got %q", checked)
+	}
+	if created {
+		t.Error("expected no email to be created for a suppressed recipient")
+	}
+}
+
+func TestSendEmail_SuppressionCheckError(t *testing.T) {
+	mockRepo := &mockRepository{
+		getActiveSuppressFunc: func(_ context.Context, _ string) (*repository.Suppression, error) {
+			return nil, fmt.Errorf("database error")
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	w := performRequest(setupSuppressionRouter(handler), http.MethodPost, "/api/v1/emails", strings.NewReader(suppressedSendBody))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestCreateContactMessage_SkipsAckForSuppressedSender(t *testing.T) {
+	ackCreated := false
+	mockRepo := &mockRepository{
+		getActiveSuppressFunc: func(_ context.Context, _ string) (*repository.Suppression, error) {
+			return &repository.Suppression{ID: 1, Reason: repository.SuppressionReasonUnsubscribe}, nil
+		},
+		createThrottledFunc: func(_ context.Context, _ *repository.Email, _ time.Time) (bool, error) {
+			ackCreated = true
+			return true, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{}, WithContactAck(true, 24*time.Hour))
+
+	if code := postContact(t, handler, contactAckBody); code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+	}
+	if ackCreated {
+		t.Error("expected no acknowledgement to a suppressed sender")
+	}
+}
+
+func TestGetSuppressions_Filter(t *testing.T) {
+	var filter repository.SuppressionFilter
+	mockRepo := &mockRepository{
+		getSuppressionsFunc: func(_ context.Context, f repository.SuppressionFilter) ([]repository.Suppression, error) {
+			filter = f
+			return nil, nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+	router := setupSuppressionRouter(handler)
+
+	w := performRequest(router, http.MethodGet, "/api/v1/suppressions?reason=complaint&include_lifted=true&limit=20", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("expected an empty array, got %s", w.Body.String())
+	}
+	if filter.Reason != repository.SuppressionReasonComplaint || !filter.IncludeLifted || filter.Limit != 20 {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+
+	w = performRequest(router, http.MethodGet, "/api/v1/suppressions?reason=soft_bounce", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an unknown reason, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestCreateSuppression(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"manual", `{"email":"user@example.com","reason":"manual","note":"asked by phone"}`, nil, http.StatusCreated},
+		{"already suppressed", `{"email":"user@example.com","reason":"complaint"}`,
+			fmt.Errorf("failed to create suppression: %w", repository.ErrSuppressionExists), http.StatusConflict},
+		{"unknown reason", `{"email":"user@example.com","reason":"soft_bounce"}`, nil, http.StatusBadRequest},
+		{"invalid email", `{"email":"not-an-address","reason":"manual"}`, nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				createSuppressionFunc: func(_ context.Context, s *repository.Suppression) error {
+					s.ID = 5
+					return tt.err
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			w := performRequest(setupSuppressionRouter(handler), http.MethodPost, "/api/v1/suppressions", strings.NewReader(tt.body))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode == http.StatusCreated && !strings.HasSuffix(w.Header().Get("Location"), "/5") {
+				t.Errorf("unexpected Location header %q", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestLiftSuppression(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"lifted", nil, http.StatusOK},
+		{"not found", fmt.Errorf("failed to lift suppression: %w", gorm.ErrRecordNotFound), http.StatusNotFound},
+		{"already lifted", fmt.Errorf("failed to lift suppression: %w", repository.ErrSuppressionLifted), http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			liftedAt := time.Now()
+			mockRepo := &mockRepository{
+				liftSuppressionFunc: func(_ context.Context, _ int64, _ *string) error {
+					return tt.err
+				},
+				getSuppressionFunc: func(_ context.Context, id int64) (*repository.Suppression, error) {
+					return &repository.Suppression{ID: id, Reason: repository.SuppressionReasonManual, LiftedAt: &liftedAt}, nil
+				},
+			}
+			handler := New(mockRepo, &mockPublisher{})
+
+			w := performRequest(setupSuppressionRouter(handler), http.MethodPost, "/api/v1/suppressions/3/lift", nil)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
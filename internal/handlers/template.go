@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtemplate"
+	"github.com/GunarsK-portfolio/messaging-api/internal/emailtypes"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+)
+
+// TemplateVersionCreate is the DTO for adding an immutable template version
+type TemplateVersionCreate struct {
+	Subject  string  `json:"subject" binding:"required,max=500"`
+	HTMLBody string  `json:"htmlBody" binding:"required,max=200000"`
+	TextBody *string `json:"textBody" binding:"omitempty,max=100000"`
+	// Activate makes the new version the one POST /emails renders (default true)
+	Activate *bool `json:"activate"`
+}
+
+// TemplateCreate is the DTO for creating a template with its first version
+type TemplateCreate struct {
+	Type string `json:"type" binding:"required,max=50"`
+	// Locale is the language tag the template serves; omit for the default template
+	Locale string `json:"locale" binding:"omitempty,max=35"`
+	TemplateVersionCreate
+}
+
+// TemplateActivate is the DTO for choosing a template's active version
+type TemplateActivate struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// TemplateResponse is a template with its version history, newest first
+type TemplateResponse struct {
+	repository.EmailTemplate
+	Versions []repository.EmailTemplateVersion `json:"versions"`
+}
+
+// GetTemplates godoc
+// @Summary List email templates
+// @Description Returns all database-managed templates with their active version (admin only)
+// @Tags Templates
+// @Produce json
+// @Success 200 {array} repository.EmailTemplate
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates [get]
+func (h *Handler) GetTemplates(c *gin.Context) {
+	templates, err := h.repo.GetTemplates(c.Request.Context())
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve templates")
+		return
+	}
+	if templates == nil {
+		templates = []repository.EmailTemplate{}
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary Get email template by ID
+// @Description Returns a template with its active version and full version history (admin only)
+// @Tags Templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} TemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates/{id} [get]
+func (h *Handler) GetTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	template, err := h.repo.GetTemplateByID(ctx, id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Template not found", "Failed to retrieve template")
+		return
+	}
+
+	versions, err := h.repo.GetTemplateVersions(ctx, id)
+	if err != nil {
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to retrieve template versions")
+		return
+	}
+	if versions == nil {
+		versions = []repository.EmailTemplateVersion{}
+	}
+
+	c.JSON(http.StatusOK, TemplateResponse{EmailTemplate: *template, Versions: versions})
+}
+
+// CreateTemplate godoc
+// @Summary Create an email template
+// @Description Creates a database template for an email type and optional locale with its first version, active by default (admin only)
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Param template body TemplateCreate true "Template data"
+// @Success 201 {object} TemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates [post]
+func (h *Handler) CreateTemplate(c *gin.Context) {
+	var req TemplateCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := emailtypes.Lookup(req.Type); !ok {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "unsupported email type: "+req.Type)
+		return
+	}
+	tag, ok := h.resolveLocale(c, req.Locale)
+	if !ok {
+		return
+	}
+
+	version, ok := newTemplateVersion(c, req.TemplateVersionCreate)
+	if !ok {
+		return
+	}
+
+	template := &repository.EmailTemplate{Type: req.Type, Locale: tag}
+	activate := req.Activate == nil || *req.Activate
+	if err := h.repo.CreateTemplate(c.Request.Context(), template, version, activate); err != nil {
+		if errors.Is(err, repository.ErrTemplateExists) {
+			commonhandlers.RespondError(c, http.StatusConflict, "A template already exists for this email type and locale, add a version instead")
+			return
+		}
+		commonhandlers.LogAndRespondError(c, http.StatusInternalServerError, err, "Failed to create template")
+		return
+	}
+	if activate {
+		template.ActiveVersion = version
+	}
+
+	setLocationHeader(c, template.ID)
+	c.JSON(http.StatusCreated, TemplateResponse{EmailTemplate: *template, Versions: []repository.EmailTemplateVersion{*version}})
+}
+
+// CreateTemplateVersion godoc
+// @Summary Add a template version
+// @Description Appends an immutable version to a template, active by default (admin only)
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param version body TemplateVersionCreate true "Version data"
+// @Success 201 {object} repository.EmailTemplateVersion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates/{id}/versions [post]
+func (h *Handler) CreateTemplateVersion(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var req TemplateVersionCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := newTemplateVersion(c, req)
+	if !ok {
+		return
+	}
+
+	activate := req.Activate == nil || *req.Activate
+	if err := h.repo.CreateTemplateVersion(c.Request.Context(), id, version, activate); err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Template not found", "Failed to create template version")
+		return
+	}
+
+	c.JSON(http.StatusCreated, version)
+}
+
+// ActivateTemplateVersion godoc
+// @Summary Set the active template version
+// @Description Makes an existing version the one POST /emails renders, e.g. to roll back (admin only)
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param active body TemplateActivate true "Version to activate"
+// @Success 200 {object} repository.EmailTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates/{id}/active [put]
+func (h *Handler) ActivateTemplateVersion(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var req TemplateActivate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repo.SetActiveTemplateVersion(ctx, id, &req.Version); err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Template version not found", "Failed to activate template version")
+		return
+	}
+
+	template, err := h.repo.GetTemplateByID(ctx, id)
+	if err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Template not found", "Failed to retrieve template")
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeactivateTemplate godoc
+// @Summary Deactivate a template
+// @Description Clears the active version so POST /emails falls back to the built-in template (admin only)
+// @Tags Templates
+// @Param id path int true "Template ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates/{id}/active [delete]
+func (h *Handler) DeactivateTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.repo.SetActiveTemplateVersion(c.Request.Context(), id, nil); err != nil {
+		commonhandlers.HandleRepositoryError(c, err, "Template not found", "Failed to deactivate template")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a template
+// @Description Deletes a template and all its versions; the email type falls back to the built-in template (admin only). A template that emails were rendered from gets 409; deactivate it instead.
+// @Tags Templates
+// @Param id path int true "Template ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /templates/{id} [delete]
+func (h *Handler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.repo.DeleteTemplate(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrTemplateInUse) {
+			commonhandlers.RespondError(c, http.StatusConflict, "Emails were rendered from this template, deactivate it instead")
+			return
+		}
+		commonhandlers.HandleRepositoryError(c, err, "Template not found", "Failed to delete template")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// newTemplateVersion validates template syntax and builds a version attributed to the current user.
+// Writes a 400 and returns false if any part fails to parse.
+func newTemplateVersion(c *gin.Context, req TemplateVersionCreate) (*repository.EmailTemplateVersion, bool) {
+	if err := emailtemplate.Validate(req.Subject, req.HTMLBody, req.TextBody); err != nil {
+		commonhandlers.RespondError(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	version := &repository.EmailTemplateVersion{
+		Subject:  req.Subject,
+		HTMLBody: req.HTMLBody,
+		TextBody: req.TextBody,
+	}
+	if username := c.GetString("username"); username != "" {
+		version.CreatedBy = &username
+	}
+	return version, true
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+)
+
+func activeVerificationTemplate() *repository.EmailTemplateVersion {
+	return &repository.EmailTemplateVersion{
+		ID:         42,
+		TemplateID: 1,
+		Version:    3,
+		Subject:    "Welcome {{.username}}, please verify",
+		HTMLBody:   `<p>Hi {{.username}}</p><p><a href="{{.verify_url}}">Verify</a></p>`,
+	}
+}
+
+// =============================================================================
+// Active template rendering Tests
+// =============================================================================
+
+func TestSendEmail_UsesActiveTemplateVersion(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		getActiveTemplateFunc: func(_ context.Context, emailType string, _ []string) (*repository.EmailTemplateVersion, error) {
+			if emailType != "email_verification" {
+				t.Errorf("expected lookup for email_verification, got %q", emailType)
+			}
+			return activeVerificationTemplate(), nil
+		},
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			email.ID = 10
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if created == nil {
+		t.Fatal("expected email to be created")
+	}
+	if created.Subject != "Welcome testuser, please verify" {
+		t.Errorf("subject = %q", created.Subject)
+	}
+	if !strings.Contains(created.Message, `href="https://example.com/verify"`) {
+		t.Errorf("expected database template body, got %q", created.Message)
+	}
+	if created.TemplateVersionID == nil || *created.TemplateVersionID != 42 {
+		t.Errorf("expected template version 42 recorded, got %v", created.TemplateVersionID)
+	}
+}
+
+func TestSendEmail_BuiltInTemplateHasNoVersion(t *testing.T) {
+	var created *repository.Email
+	mockRepo := &mockRepository{
+		createEmailFunc: func(_ context.Context, email *repository.Email) error {
+			created = email
+			return nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails", handler.SendEmail)
+
+	body := `{"type":"email_verification","recipient_email":"user@example.com","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if created.TemplateVersionID != nil {
+		t.Errorf("expected no template version, got %v", *created.TemplateVersionID)
+	}
+}
+
+func TestPreviewEmail_ActiveTemplateVersion(t *testing.T) {
+	mockRepo := &mockRepository{
+		getActiveTemplateFunc: func(_ context.Context, _ string, _ []string) (*repository.EmailTemplateVersion, error) {
+			return activeVerificationTemplate(), nil
+		},
+	}
+	handler := New(mockRepo, &mockPublisher{})
+
+	router := setupTestRouter()
+	router.POST("/api/v1/emails/preview", handler.PreviewEmail)
+
+	body := `{"type":"email_verification","data":{"username":"testuser","verify_url":"https://example.com/verify"}}`
+	w := performRequest(router, http.MethodPost, "/api/v1/emails/preview", strings.NewReader(body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp EmailPreviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.TemplateVersion == nil || *resp.TemplateVersion != 3 {
+		t.Errorf("expected template_version 3, got %v", resp.TemplateVersion)
+	}
+	if resp.Text != "Hi testuser\n\nVerify (https://example.com/verify)" {
+		t.Errorf("expected derived plain text, got %q", resp.Text)
+	}
+}
+
+// =============================================================================
+// Template admin Tests
+// =============================================================================
+
+func setupTemplateRouter(handler *Handler) *gin.Engine {
+	router := setupTestRouter()
+	withUser := func(c *gin.Context) {
+		c.Set("username", "admin")
+		c.Next()
+	}
+	router.GET("/api/v1/templates/:id", handler.GetTemplate)
+	router.POST("/api/v1/templates", withUser, handler.CreateTemplate)
+	router.POST("/api/v1/templates/:id/versions", withUser, handler.CreateTemplateVersion)
+	router.PUT("/api/v1/templates/:id/active", handler.ActivateTemplateVersion)
+	router.DELETE("/api/v1/templates/:id/active", handler.DeactivateTemplate)
+	router.DELETE("/api/v1/templates/:id", handler.DeleteTemplate)
+	return router
+}
+
+func TestCreateTemplate_Success(t *testing.T) {
+	var gotVersion *repository.EmailTemplateVersion
+	var gotActivate bool
+	mockRepo := &mockRepository{
+		createTemplateFunc: func(_ context.Context, template *repository.EmailTemplate, version *repository.EmailTemplateVersion, activate bool) error {
+			template.ID = 5
+			version.Version = 1
+			gotVersion = version
+			gotActivate = activate
+			return nil
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	body := `{"type":"email_verification","subject":"Verify {{.username}}","htmlBody":"<p>{{.verify_url}}</p>"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/templates", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if !gotActivate {
+		t.Error("expected first version to be activated by default")
+	}
+	if gotVersion.CreatedBy == nil || *gotVersion.CreatedBy != "admin" {
+		t.Errorf("expected createdBy admin, got %v", gotVersion.CreatedBy)
+	}
+	if loc := w.Header().Get("Location"); !strings.HasSuffix(loc, "/5") {
+		t.Errorf("expected Location ending in /5, got %q", loc)
+	}
+
+	var resp TemplateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.ActiveVersion == nil || len(resp.Versions) != 1 {
+		t.Errorf("expected active version and one version, got %+v", resp)
+	}
+}
+
+func TestCreateTemplate_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"missing_fields", `{"type":"email_verification"}`, ""},
+		{"unsupported_type", `{"type":"unknown","subject":"s","htmlBody":"<p></p>"}`, "unsupported email type"},
+		{"bad_syntax", `{"type":"email_verification","subject":"s","htmlBody":"<p>{{.username</p>"}`, "html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				createTemplateFunc: func(_ context.Context, _ *repository.EmailTemplate, _ *repository.EmailTemplateVersion, _ bool) error {
+					t.Error("repository should not be called")
+					return nil
+				},
+			}
+			router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+			w := performRequest(router, http.MethodPost, "/api/v1/templates", strings.NewReader(tt.body))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected error to mention %q, got %s", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateTemplate_Conflict(t *testing.T) {
+	mockRepo := &mockRepository{
+		createTemplateFunc: func(_ context.Context, _ *repository.EmailTemplate, _ *repository.EmailTemplateVersion, _ bool) error {
+			return repository.ErrTemplateExists
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	body := `{"type":"email_verification","subject":"s","htmlBody":"<p></p>"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/templates", strings.NewReader(body))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestCreateTemplateVersion_NotActivated(t *testing.T) {
+	var gotActivate = true
+	mockRepo := &mockRepository{
+		createVersionFunc: func(_ context.Context, templateID int64, version *repository.EmailTemplateVersion, activate bool) error {
+			if templateID != 5 {
+				t.Errorf("expected template 5, got %d", templateID)
+			}
+			version.Version = 2
+			gotActivate = activate
+			return nil
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	body := `{"subject":"s","htmlBody":"<p></p>","activate":false}`
+	w := performRequest(router, http.MethodPost, "/api/v1/templates/5/versions", strings.NewReader(body))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if gotActivate {
+		t.Error("expected version not to be activated")
+	}
+}
+
+func TestCreateTemplateVersion_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		createVersionFunc: func(_ context.Context, _ int64, _ *repository.EmailTemplateVersion, _ bool) error {
+			return gorm.ErrRecordNotFound
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	body := `{"subject":"s","htmlBody":"<p></p>"}`
+	w := performRequest(router, http.MethodPost, "/api/v1/templates/999/versions", strings.NewReader(body))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestGetTemplate_IncludesVersions(t *testing.T) {
+	mockRepo := &mockRepository{
+		getTemplateByIDFunc: func(_ context.Context, id int64) (*repository.EmailTemplate, error) {
+			return &repository.EmailTemplate{ID: id, Type: "email_verification"}, nil
+		},
+		getVersionsFunc: func(_ context.Context, _ int64) ([]repository.EmailTemplateVersion, error) {
+			return []repository.EmailTemplateVersion{{Version: 2}, {Version: 1}}, nil
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	w := performRequest(router, http.MethodGet, "/api/v1/templates/5", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp TemplateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.ID != 5 || len(resp.Versions) != 2 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestActivateTemplateVersion(t *testing.T) {
+	var gotVersion *int
+	mockRepo := &mockRepository{
+		setActiveVersionFunc: func(_ context.Context, _ int64, version *int) error {
+			gotVersion = version
+			return nil
+		},
+		getTemplateByIDFunc: func(_ context.Context, id int64) (*repository.EmailTemplate, error) {
+			return &repository.EmailTemplate{ID: id}, nil
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	w := performRequest(router, http.MethodPut, "/api/v1/templates/5/active", strings.NewReader(`{"version":1}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if gotVersion == nil || *gotVersion != 1 {
+		t.Errorf("expected version 1 activated, got %v", gotVersion)
+	}
+}
+
+func TestActivateTemplateVersion_UnknownVersion(t *testing.T) {
+	mockRepo := &mockRepository{
+		setActiveVersionFunc: func(_ context.Context, _ int64, _ *int) error {
+			return gorm.ErrRecordNotFound
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	w := performRequest(router, http.MethodPut, "/api/v1/templates/5/active", strings.NewReader(`{"version":9}`))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestDeactivateTemplate(t *testing.T) {
+	called := false
+	mockRepo := &mockRepository{
+		setActiveVersionFunc: func(_ context.Context, _ int64, version *int) error {
+			called = true
+			if version != nil {
+				t.Errorf("expected nil version, got %d", *version)
+			}
+			return nil
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	w := performRequest(router, http.MethodDelete, "/api/v1/templates/5/active", nil)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if !called {
+		t.Error("expected SetActiveTemplateVersion to be called")
+	}
+}
+
+func TestDeleteTemplate_NotFound(t *testing.T) {
+	mockRepo := &mockRepository{
+		deleteTemplateFunc: func(_ context.Context, _ int64) error {
+			return gorm.ErrRecordNotFound
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	w := performRequest(router, http.MethodDelete, "/api/v1/templates/5", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestDeleteTemplate_InUse(t *testing.T) {
+	mockRepo := &mockRepository{
+		deleteTemplateFunc: func(_ context.Context, _ int64) error {
+			return fmt.Errorf("failed to delete template: %w", repository.ErrTemplateInUse)
+		},
+	}
+	router := setupTemplateRouter(New(mockRepo, &mockPublisher{}))
+
+	w := performRequest(router, http.MethodDelete, "/api/v1/templates/5", nil)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
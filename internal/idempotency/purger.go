@@ -0,0 +1,64 @@
+// Package idempotency purges expired Idempotency-Key records of POST /emails.
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Store is the subset of the repository used by the purger
+type Store interface {
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Purger periodically deletes expired idempotency keys, keeping the purge off the request path
+type Purger struct {
+	store    Store
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewPurger creates a new Purger instance
+func NewPurger(store Store, interval time.Duration, logger *slog.Logger) *Purger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Purger{
+		store:    store,
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run purges expired keys every interval until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	p.logger.Info("Idempotency key purger started", "interval", p.interval.String())
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Idempotency key purger stopped")
+			return
+		case <-ticker.C:
+			p.PurgeOnce(ctx)
+		}
+	}
+}
+
+// PurgeOnce deletes the keys expired by now and returns how many were deleted
+func (p *Purger) PurgeOnce(ctx context.Context) int64 {
+	deleted, err := p.store.DeleteExpiredIdempotencyKeys(ctx, p.now())
+	if err != nil {
+		p.logger.Error("Failed to purge expired idempotency keys", "error", err)
+		return 0
+	}
+	if deleted > 0 {
+		p.logger.Info("Expired idempotency keys purged", "count", deleted)
+	}
+	return deleted
+}
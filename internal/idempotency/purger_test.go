@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockStore struct {
+	deleted   int64
+	deleteErr error
+	now       time.Time
+}
+
+func (m *mockStore) DeleteExpiredIdempotencyKeys(_ context.Context, now time.Time) (int64, error) {
+	m.now = now
+	return m.deleted, m.deleteErr
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+var fixedNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestPurger(store Store) *Purger {
+	p := NewPurger(store, time.Hour, nil)
+	p.now = func() time.Time { return fixedNow }
+	return p
+}
+
+// =============================================================================
+// PurgeOnce Tests
+// =============================================================================
+
+func TestPurgeOnce_DeletesExpiredKeys(t *testing.T) {
+	store := &mockStore{deleted: 3}
+
+	deleted := newTestPurger(store).PurgeOnce(context.Background())
+
+	if deleted != 3 {
+		t.Errorf("deleted = %d, want 3", deleted)
+	}
+	if !store.now.Equal(fixedNow) {
+		t.Errorf("now = %v, want %v", store.now, fixedNow)
+	}
+}
+
+func TestPurgeOnce_StoreError(t *testing.T) {
+	store := &mockStore{deleted: 3, deleteErr: errors.New("database error")}
+
+	if deleted := newTestPurger(store).PurgeOnce(context.Background()); deleted != 0 {
+		t.Errorf("deleted = %d, want 0", deleted)
+	}
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	p := newTestPurger(&mockStore{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop after context cancellation")
+	}
+}
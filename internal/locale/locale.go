@@ -0,0 +1,90 @@
+// Package locale normalizes BCP 47 language tags and resolves template fallback chains.
+package locale
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxLength bounds a stored tag (language-Script-REGION with room for extensions)
+const MaxLength = 35
+
+// Parse validates a language tag of the form language[-Script][-REGION] and returns it
+// in canonical case, e.g. "lv_lv" becomes "lv-LV" and "zh-hant-tw" becomes "zh-Hant-TW".
+func Parse(tag string) (string, error) {
+	if tag == "" || len(tag) > MaxLength {
+		return "", fmt.Errorf("invalid locale %q", tag)
+	}
+
+	parts := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	if !isAlpha(parts[0]) || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return "", fmt.Errorf("invalid locale %q: language must be 2-3 letters", tag)
+	}
+	out := []string{strings.ToLower(parts[0])}
+
+	rest := parts[1:]
+	if len(rest) > 0 && len(rest[0]) == 4 && isAlpha(rest[0]) {
+		out = append(out, strings.ToUpper(rest[0][:1])+strings.ToLower(rest[0][1:]))
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		switch region := rest[0]; {
+		case len(region) == 2 && isAlpha(region):
+			out = append(out, strings.ToUpper(region))
+		case len(region) == 3 && isDigits(region):
+			out = append(out, region)
+		default:
+			return "", fmt.Errorf("invalid locale %q: unrecognized subtag %q", tag, region)
+		}
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		return "", fmt.Errorf("invalid locale %q: unrecognized subtag %q", tag, rest[0])
+	}
+
+	return strings.Join(out, "-"), nil
+}
+
+// Fallbacks returns a canonical tag followed by its less specific parents,
+// e.g. "zh-Hant-TW" yields ["zh-Hant-TW", "zh-Hant", "zh"].
+func Fallbacks(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	chain := []string{tag}
+	for i := strings.LastIndex(tag, "-"); i > 0; i = strings.LastIndex(tag, "-") {
+		tag = tag[:i]
+		chain = append(chain, tag)
+	}
+	return chain
+}
+
+// Supported reports whether a canonical tag or one of its parents is in the supported list
+func Supported(tag string, supported []string) bool {
+	for _, candidate := range Fallbacks(tag) {
+		for _, s := range supported {
+			if candidate == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
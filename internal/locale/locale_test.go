@@ -0,0 +1,76 @@
+package locale
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"lv", "lv", false},
+		{"LV", "lv", false},
+		{"lv-LV", "lv-LV", false},
+		{"lv_lv", "lv-LV", false},
+		{"en-gb", "en-GB", false},
+		{"zh-hant-tw", "zh-Hant-TW", false},
+		{"sr-Latn", "sr-Latn", false},
+		{"es-419", "es-419", false},
+		{"", "", true},
+		{"l", "", true},
+		{"english", "", true},
+		{"lv-", "", true},
+		{"lv-LV-x", "", true},
+		{"lv-12", "", true},
+		{"e1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"lv-LV", []string{"lv-LV", "lv"}},
+		{"lv", []string{"lv"}},
+		{"zh-Hant-TW", []string{"zh-Hant-TW", "zh-Hant", "zh"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Fallbacks(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Fallbacks(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	supported := []string{"en", "lv"}
+
+	for tag, want := range map[string]bool{
+		"lv":    true,
+		"lv-LV": true,
+		"en-GB": true,
+		"de":    false,
+		"de-LV": false,
+	} {
+		if got := Supported(tag, supported); got != want {
+			t.Errorf("Supported(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
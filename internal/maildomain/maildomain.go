@@ -0,0 +1,133 @@
+// Package maildomain checks whether an email address's domain can receive mail, using its MX records
+// or, failing those, its address records (RFC 5321 section 5.1). Results are cached per domain.
+package maildomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheSweepInterval is how often expired results are dropped from the cache
+const cacheSweepInterval = 10 * time.Minute
+
+// Resolver looks up DNS records; *net.Resolver implements it
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// cached is a cached result for a domain
+type cached struct {
+	deliverable bool
+	expires     time.Time
+}
+
+// Checker reports whether domains accept mail
+type Checker struct {
+	resolver Resolver
+	ttl      time.Duration
+	timeout  time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cache     map[string]cached
+	lastSweep time.Time
+}
+
+// New creates a checker that caches each definite answer for ttl and gives up on a lookup after timeout
+func New(resolver Resolver, ttl, timeout time.Duration) *Checker {
+	return &Checker{
+		resolver: resolver,
+		ttl:      ttl,
+		timeout:  timeout,
+		now:      time.Now,
+		cache:    make(map[string]cached),
+	}
+}
+
+// Deliverable reports whether the domain of address accepts mail. An error means DNS could not
+// give a definite answer (e.g. a timeout); such failures are not cached.
+func (c *Checker) Deliverable(ctx context.Context, address string) (bool, error) {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return false, nil
+	}
+	domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(address[at+1:])), ".")
+	if domain == "" {
+		return false, nil
+	}
+
+	if deliverable, ok := c.cached(domain); ok {
+		return deliverable, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	deliverable, err := c.lookup(ctx, domain)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up mail domain %s: %w", domain, err)
+	}
+	c.store(domain, deliverable)
+	return deliverable, nil
+}
+
+// lookup resolves a domain's mail exchangers, falling back to its address records when it has none
+func (c *Checker) lookup(ctx context.Context, domain string) (bool, error) {
+	mx, err := c.resolver.LookupMX(ctx, domain)
+	switch {
+	case err == nil && len(mx) > 0:
+		// A single "." exchanger is a null MX: the domain explicitly accepts no mail (RFC 7505)
+		if len(mx) == 1 && (mx[0].Host == "." || mx[0].Host == "") {
+			return false, nil
+		}
+		return true, nil
+	case err != nil && !isNotFound(err):
+		return false, err
+	}
+
+	hosts, err := c.resolver.LookupHost(ctx, domain)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(hosts) > 0, nil
+}
+
+// isNotFound reports whether err is a definite "no such domain" or "no such record" answer
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+func (c *Checker) cached(domain string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.cache[domain]
+	if !ok || !c.now().Before(entry.expires) {
+		return false, false
+	}
+	return entry.deliverable, true
+}
+
+func (c *Checker) store(domain string, deliverable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= cacheSweepInterval {
+		c.lastSweep = now
+		for d, entry := range c.cache {
+			if !now.Before(entry.expires) {
+				delete(c.cache, d)
+			}
+		}
+	}
+	c.cache[domain] = cached{deliverable: deliverable, expires: now.Add(c.ttl)}
+}
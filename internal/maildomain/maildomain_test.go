@@ -0,0 +1,114 @@
+package maildomain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeResolver answers from fixed records; domains missing from both maps do not exist
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	err     error
+	lookups int
+}
+
+func (f *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	f.lookups++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if mx, ok := f.mx[name]; ok {
+		return mx, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (f *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if hosts, ok := f.hosts[host]; ok {
+		return hosts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestDeliverable(t *testing.T) {
+	resolver := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx1.example.com.", Pref: 10}},
+			"nomail.com":  {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{"a-only.com": {"192.0.2.1"}},
+	}
+	checker := New(resolver, time.Hour, time.Second)
+
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"jane@example.com", true},
+		{"jane@EXAMPLE.com.", true},
+		{"jane@a-only.com", true},
+		{"jane@nomail.com", false},
+		{"jane@exmaple.com", false},
+		{"no-at-sign", false},
+		{"jane@", false},
+	}
+	for _, tt := range tests {
+		got, err := checker.Deliverable(context.Background(), tt.address)
+		if err != nil {
+			t.Errorf("Deliverable(%q) error = %v", tt.address, err)
+		}
+		if got != tt.want {
+			t.Errorf("Deliverable(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverable_Caches(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	resolver := &fakeResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com."}}}}
+	checker := New(resolver, time.Hour, time.Second)
+	checker.now = func() time.Time { return now }
+
+	for range 3 {
+		_, _ = checker.Deliverable(context.Background(), "jane@example.com")
+		_, _ = checker.Deliverable(context.Background(), "typo@exmaple.com")
+	}
+	if resolver.lookups != 2 {
+		t.Errorf("expected one lookup per domain, got %d", resolver.lookups)
+	}
+
+	now = now.Add(time.Hour)
+	_, _ = checker.Deliverable(context.Background(), "jane@example.com")
+	if resolver.lookups != 3 {
+		t.Errorf("expected an expired result to be looked up again, got %d lookups", resolver.lookups)
+	}
+}
+
+func TestDeliverable_TemporaryFailureNotCached(t *testing.T) {
+	resolver := &fakeResolver{err: &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}}
+	checker := New(resolver, time.Hour, time.Second)
+
+	if _, err := checker.Deliverable(context.Background(), "jane@example.com"); err == nil {
+		t.Fatal("expected an error for a DNS timeout")
+	}
+
+	resolver.err = nil
+	resolver.mx = map[string][]*net.MX{"example.com": {{Host: "mx.example.com."}}}
+	got, err := checker.Deliverable(context.Background(), "jane@example.com")
+	if err != nil || !got {
+		t.Errorf("expected a fresh lookup after the failure, got %v, %v", got, err)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if isNotFound(errors.New("boom")) {
+		t.Error("expected plain errors not to count as not found")
+	}
+	if !isNotFound(&net.DNSError{IsNotFound: true}) {
+		t.Error("expected NXDOMAIN to count as not found")
+	}
+}
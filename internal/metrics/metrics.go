@@ -0,0 +1,140 @@
+// Package metrics extends the common Prometheus collectors with messaging-specific metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	commonmetrics "github.com/GunarsK-portfolio/portfolio-common/metrics"
+)
+
+// Metrics holds the common HTTP/DB metrics plus messaging-specific collectors
+type Metrics struct {
+	*commonmetrics.Metrics
+
+	// Reconciler metrics
+	EmailsReconciled       prometheus.Counter
+	EmailReconcileFailures prometheus.Counter
+
+	// Scheduler metrics
+	ScheduledBacklog  prometheus.Gauge
+	ScheduledReleased prometheus.Counter
+	ScheduledLateness prometheus.Histogram
+
+	// Quarantine review metrics
+	QuarantineApproved prometheus.Counter
+	QuarantineRejected prometheus.Counter
+
+	// ContactRateLimited counts contact submissions refused by a rate limit, by bucket (ip or sender)
+	ContactRateLimited *prometheus.CounterVec
+	// ContactDuplicates counts repeated contact submissions that were not stored again
+	ContactDuplicates prometheus.Counter
+
+	// DeliveryEvents counts provider delivery webhook events, by provider and type (delivered, bounce, complaint)
+	DeliveryEvents *prometheus.CounterVec
+}
+
+// New creates a new Metrics instance with registered Prometheus metrics
+func New(cfg commonmetrics.Config) *Metrics {
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace = "portfolio"
+	}
+
+	return &Metrics{
+		Metrics: commonmetrics.New(cfg),
+
+		EmailsReconciled: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "emails_reconciled_total",
+				Help:      "Total number of stale pending emails re-published by the reconciler",
+			},
+		),
+
+		EmailReconcileFailures: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "email_reconcile_failures_total",
+				Help:      "Total number of stale pending emails the reconciler failed to re-publish",
+			},
+		),
+
+		ScheduledBacklog: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "scheduled_emails",
+				Help:      "Number of scheduled emails waiting for their send_at time",
+			},
+		),
+
+		ScheduledReleased: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "scheduled_emails_released_total",
+				Help:      "Total number of scheduled emails released for delivery",
+			},
+		),
+
+		ScheduledLateness: promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "scheduled_email_lateness_seconds",
+				Help:      "Delay between a scheduled email's send_at and its release for delivery",
+				Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+			},
+		),
+
+		QuarantineApproved: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "quarantine_approved_total",
+				Help:      "Total number of quarantined contact messages approved for delivery",
+			},
+		),
+
+		QuarantineRejected: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "quarantine_rejected_total",
+				Help:      "Total number of quarantined contact messages rejected as spam",
+			},
+		),
+
+		ContactRateLimited: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "contact_rate_limited_total",
+				Help:      "Total number of contact form submissions rejected by rate limiting",
+			},
+			[]string{"bucket"},
+		),
+
+		ContactDuplicates: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "contact_duplicates_total",
+				Help:      "Total number of duplicate contact form submissions ignored",
+			},
+		),
+
+		DeliveryEvents: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: cfg.ServiceName,
+				Name:      "delivery_events_total",
+				Help:      "Total number of delivery events received from email provider webhooks",
+			},
+			[]string{"provider", "type"},
+		),
+	}
+}
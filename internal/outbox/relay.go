@@ -0,0 +1,111 @@
+// Package outbox publishes EmailEvents recorded in the transactional outbox.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	"github.com/GunarsK-portfolio/portfolio-common/queue"
+)
+
+// Store is the subset of the repository used by the relay
+type Store interface {
+	ClaimDueOutboxEntries(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]repository.OutboxEntry, error)
+	MarkOutboxDispatched(ctx context.Context, emailID int64) error
+	RecordOutboxFailure(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+}
+
+// Relay periodically publishes undispatched outbox entries with exponential backoff
+type Relay struct {
+	store     Store
+	publisher queue.Publisher
+	cfg       config.OutboxConfig
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewRelay creates a new Relay instance
+func NewRelay(store Store, publisher queue.Publisher, cfg config.OutboxConfig, logger *slog.Logger) *Relay {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run drains the outbox every poll interval until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started", "interval", r.cfg.PollInterval.String())
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			r.DispatchBatch(ctx)
+		}
+	}
+}
+
+// DispatchBatch claims and publishes one batch of due entries and returns how many were dispatched.
+// Entries this relay fails to settle become due again once the claim times out.
+func (r *Relay) DispatchBatch(ctx context.Context) int {
+	now := r.now()
+	entries, err := r.store.ClaimDueOutboxEntries(ctx, now.Add(-r.cfg.GracePeriod), r.cfg.BatchSize, now.Add(r.cfg.ClaimTimeout))
+	if err != nil {
+		r.logger.Error("Failed to load outbox entries", "error", err)
+		return 0
+	}
+
+	dispatched := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		event := models.EmailEvent{EmailID: entry.EmailID}
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			next := r.now().Add(Backoff(entry.Attempts, r.cfg.BaseBackoff, r.cfg.MaxBackoff))
+			r.logger.Warn("Outbox publish failed", "error", err, "emailId", entry.EmailID, "attempts", entry.Attempts+1, "nextAttemptAt", next)
+			if recErr := r.store.RecordOutboxFailure(ctx, entry.ID, err.Error(), next); recErr != nil {
+				r.logger.Error("Failed to record outbox failure", "error", recErr, "outboxId", entry.ID)
+			}
+			continue
+		}
+
+		if err := r.store.MarkOutboxDispatched(ctx, entry.EmailID); err != nil {
+			r.logger.Error("Failed to mark outbox entry dispatched", "error", err, "emailId", entry.EmailID)
+			continue
+		}
+		dispatched++
+	}
+
+	if dispatched > 0 {
+		r.logger.Info("Outbox entries dispatched", "count", dispatched)
+	}
+	return dispatched
+}
+
+// Backoff returns base * 2^attempts, capped at maxDelay
+func Backoff(attempts int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return min(delay, maxDelay)
+}
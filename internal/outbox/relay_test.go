@@ -0,0 +1,211 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockStore struct {
+	entries       []repository.OutboxEntry
+	getErr        error
+	createdBefore time.Time
+	claimUntil    time.Time
+	dispatched    []int64
+	failures      map[int64]time.Time
+}
+
+func (m *mockStore) ClaimDueOutboxEntries(_ context.Context, createdBefore time.Time, _ int, claimUntil time.Time) ([]repository.OutboxEntry, error) {
+	m.createdBefore = createdBefore
+	m.claimUntil = claimUntil
+	return m.entries, m.getErr
+}
+
+func (m *mockStore) MarkOutboxDispatched(_ context.Context, emailID int64) error {
+	m.dispatched = append(m.dispatched, emailID)
+	return nil
+}
+
+func (m *mockStore) RecordOutboxFailure(_ context.Context, id int64, _ string, nextAttemptAt time.Time) error {
+	if m.failures == nil {
+		m.failures = map[int64]time.Time{}
+	}
+	m.failures[id] = nextAttemptAt
+	return nil
+}
+
+type mockPublisher struct {
+	publishFunc func(ctx context.Context, message interface{}) error
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, message interface{}) error {
+	if m.publishFunc != nil {
+		return m.publishFunc(ctx, message)
+	}
+	return nil
+}
+
+func (m *mockPublisher) PublishToRetry(_ context.Context, _ int, _ []byte, _ string, _ amqp.Table) error {
+	return nil
+}
+
+func (m *mockPublisher) PublishToDLQ(_ context.Context, _ []byte, _ string) error {
+	return nil
+}
+
+func (m *mockPublisher) MaxRetries() int {
+	return 0
+}
+
+func (m *mockPublisher) Close() error {
+	return nil
+}
+
+func testConfig() config.OutboxConfig {
+	return config.OutboxConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		GracePeriod:  30 * time.Second,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   time.Minute,
+		ClaimTimeout: time.Minute,
+	}
+}
+
+var fixedNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestRelay(store Store, pub *mockPublisher) *Relay {
+	relay := NewRelay(store, pub, testConfig(), nil)
+	relay.now = func() time.Time { return fixedNow }
+	return relay
+}
+
+// =============================================================================
+// DispatchBatch Tests
+// =============================================================================
+
+func TestDispatchBatch_PublishesAndMarksDispatched(t *testing.T) {
+	store := &mockStore{entries: []repository.OutboxEntry{
+		{ID: 1, EmailID: 10},
+		{ID: 2, EmailID: 20},
+	}}
+	var published []int64
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		evt, ok := msg.(models.EmailEvent)
+		if !ok {
+			t.Fatalf("expected EmailEvent, got %T", msg)
+		}
+		published = append(published, evt.EmailID)
+		return nil
+	}}
+
+	got := newTestRelay(store, pub).DispatchBatch(context.Background())
+
+	if got != 2 {
+		t.Errorf("dispatched = %d, want 2", got)
+	}
+	if len(published) != 2 || published[0] != 10 || published[1] != 20 {
+		t.Errorf("published = %v, want [10 20]", published)
+	}
+	if len(store.dispatched) != 2 {
+		t.Errorf("marked dispatched = %v, want 2 entries", store.dispatched)
+	}
+	if want := fixedNow.Add(-30 * time.Second); !store.createdBefore.Equal(want) {
+		t.Errorf("createdBefore = %v, want %v", store.createdBefore, want)
+	}
+	if want := fixedNow.Add(time.Minute); !store.claimUntil.Equal(want) {
+		t.Errorf("claimUntil = %v, want %v", store.claimUntil, want)
+	}
+}
+
+func TestDispatchBatch_PublishFailureSchedulesRetry(t *testing.T) {
+	store := &mockStore{entries: []repository.OutboxEntry{
+		{ID: 1, EmailID: 10, Attempts: 2},
+	}}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, _ interface{}) error {
+		return errors.New("mq down")
+	}}
+
+	got := newTestRelay(store, pub).DispatchBatch(context.Background())
+
+	if got != 0 {
+		t.Errorf("dispatched = %d, want 0", got)
+	}
+	if len(store.dispatched) != 0 {
+		t.Errorf("expected nothing marked dispatched, got %v", store.dispatched)
+	}
+	next, ok := store.failures[1]
+	if !ok {
+		t.Fatal("expected failure to be recorded")
+	}
+	if want := fixedNow.Add(20 * time.Second); !next.Equal(want) {
+		t.Errorf("nextAttemptAt = %v, want %v", next, want)
+	}
+}
+
+func TestDispatchBatch_StoreError(t *testing.T) {
+	store := &mockStore{getErr: errors.New("database error")}
+	publishCalled := false
+	pub := &mockPublisher{publishFunc: func(_ context.Context, _ interface{}) error {
+		publishCalled = true
+		return nil
+	}}
+
+	if got := newTestRelay(store, pub).DispatchBatch(context.Background()); got != 0 {
+		t.Errorf("dispatched = %d, want 0", got)
+	}
+	if publishCalled {
+		t.Error("expected publish NOT to be called when loading entries fails")
+	}
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	relay := newTestRelay(&mockStore{}, &mockPublisher{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after context cancellation")
+	}
+}
+
+// =============================================================================
+// Backoff Tests
+// =============================================================================
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 10 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{50, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts, 5*time.Second, time.Minute); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
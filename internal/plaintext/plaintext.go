@@ -0,0 +1,128 @@
+// Package plaintext converts between rendered HTML email bodies and their plain-text alternative.
+package plaintext
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// blockElements start on a new line in the text rendering
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Tr: true, atom.Table: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Hr: true, atom.Blockquote: true, atom.Pre: true,
+}
+
+// skippedElements hold content that is never shown to a reader
+var skippedElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Title: true, atom.Style: true, atom.Script: true,
+}
+
+var (
+	spaceRun   = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// FromHTML renders an HTML document as plain text.
+// Block elements become line breaks, links keep their target as "text (url)", and markup is dropped.
+func FromHTML(input string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	skipDepth := 0
+	var href string
+	var linkText strings.Builder
+	inLink := false
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return normalize(b.String())
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if skippedElements[token.DataAtom] {
+				if token.Type == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if blockElements[token.DataAtom] {
+				b.WriteString("\n")
+			}
+			if token.DataAtom == atom.Li {
+				b.WriteString("- ")
+			}
+			if token.DataAtom == atom.A && token.Type == html.StartTagToken {
+				inLink = true
+				href = attr(token, "href")
+				linkText.Reset()
+			}
+
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if skippedElements[token.DataAtom] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if token.DataAtom == atom.A && inLink {
+				inLink = false
+				text := strings.TrimSpace(linkText.String())
+				if href != "" && href != text && !strings.HasPrefix(href, "#") {
+					// Keep the target on the same line as the link text
+					rendered := strings.TrimRight(b.String(), " \t\r\n")
+					b.Reset()
+					b.WriteString(rendered + " (" + href + ")")
+				}
+			}
+			if blockElements[token.DataAtom] {
+				b.WriteString("\n")
+			}
+
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			text := string(tokenizer.Text())
+			b.WriteString(text)
+			if inLink {
+				linkText.WriteString(text)
+			}
+		}
+	}
+}
+
+// attr returns the value of the named attribute, or "" if absent
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// normalize collapses template indentation and runs of blank lines
+func normalize(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRun.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// ToHTML escapes user-supplied plain text for the HTML part of an email, keeping line breaks as <br>
+func ToHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(NormalizeNewlines(text)), "\n", "<br>\n")
+}
+
+// NormalizeNewlines converts CRLF and lone CR line endings to LF
+func NormalizeNewlines(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+}
@@ -0,0 +1,83 @@
+package plaintext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GunarsK-portfolio/portfolio-common/renderer"
+)
+
+func TestFromHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text", "Hello", "Hello"},
+		{"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo"},
+		{"line break", "One<br>Two", "One\nTwo"},
+		{"entities decoded", "<p>Tom &amp; Jerry &lt;3</p>", "Tom & Jerry <3"},
+		{"link with target", `<a href="https://example.com/x">Open</a>`, "Open (https://example.com/x)"},
+		{"link showing its url", `<a href="https://example.com">https://example.com</a>`, "https://example.com"},
+		{"list items", "<ul><li>a</li><li>b</li></ul>", "- a\n\n- b"},
+		{"head and style dropped", "<html><head><title>T</title><style>p{}</style></head><body>Body</body></html>", "Body"},
+		{"script dropped", "<p>Hi</p><script>alert(1)</script>", "Hi"},
+		{"whitespace collapsed", "<p>  lots   of\n\t  space  </p>", "lots of\nspace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromHTML(tt.input); got != tt.want {
+				t.Errorf("FromHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromHTML_RenderedTemplate(t *testing.T) {
+	html, err := renderer.Render("email_verification", map[string]string{
+		"username":   "testuser",
+		"verify_url": "https://example.com/verify?token=abc",
+	})
+	if err != nil {
+		t.Fatalf("failed to render template: %v", err)
+	}
+
+	text := FromHTML(html)
+
+	for _, want := range []string{"Hi testuser,", "Verify Email (https://example.com/verify?token=abc)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected text to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "<") || strings.Contains(text, "style=") {
+		t.Errorf("expected no markup in text, got:\n%s", text)
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "Hello there", "Hello there"},
+		{"markup_escaped", `<script>alert("x")</script> & 'co'`, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &#39;co&#39;"},
+		{"line_breaks", "Hi,\r\nline two\rline three", "Hi,<br>\nline two<br>\nline three"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.input); got != tt.want {
+				t.Errorf("ToHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToHTML_RoundTrip(t *testing.T) {
+	input := "Hi <team>,\n\nPlease call me & reply."
+	if got := FromHTML(ToHTML(input)); got != input {
+		t.Errorf("FromHTML(ToHTML()) = %q, want %q", got, input)
+	}
+}
@@ -0,0 +1,100 @@
+// Package ratelimit throttles public submissions with token buckets held in memory or in Redis.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	commonhandlers "github.com/GunarsK-portfolio/portfolio-common/handlers"
+	"github.com/GunarsK-portfolio/portfolio-common/logger"
+)
+
+// Limit is a token bucket holding Burst tokens that refills completely over Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// interval is the time it takes to refill one token
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Burst)
+}
+
+// Decision is the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed bool
+	// RetryAfter is how long until the next token is available (zero when allowed)
+	RetryAfter time.Duration
+}
+
+// Store holds token buckets. Take removes one token from the bucket under key,
+// creating a full bucket if there is none.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
+
+// Limiter applies one limit to a family of buckets, e.g. one bucket per client IP
+type Limiter struct {
+	store    Store
+	name     string
+	limit    Limit
+	rejected prometheus.Counter
+}
+
+// New creates a limiter whose buckets are namespaced by name. rejected, if not nil,
+// counts the requests turned away.
+func New(store Store, name string, limit Limit, rejected prometheus.Counter) *Limiter {
+	return &Limiter{store: store, name: name, limit: limit, rejected: rejected}
+}
+
+// Allow takes a token from the bucket for key
+func (l *Limiter) Allow(ctx context.Context, key string) (Decision, error) {
+	d, err := l.store.Take(ctx, "ratelimit:"+l.name+":"+key, l.limit)
+	if err != nil {
+		return Decision{Allowed: true}, err
+	}
+	if !d.Allowed && l.rejected != nil {
+		l.rejected.Inc()
+	}
+	return d, nil
+}
+
+// Check takes a token for key and answers 429 with Retry-After when the bucket is empty.
+// Store failures are logged and let the request through. It reports whether the request may proceed.
+func (l *Limiter) Check(c *gin.Context, key string) bool {
+	d, err := l.Allow(c.Request.Context(), key)
+	if err != nil {
+		logger.GetLogger(c).Warn("Rate limit check failed", "limiter", l.name, "error", err)
+		return true
+	}
+	if d.Allowed {
+		return true
+	}
+
+	logger.GetLogger(c).Info("Request rate limited", "limiter", l.name, "retryAfter", d.RetryAfter)
+	c.Header("Retry-After", retryAfterSeconds(d.RetryAfter))
+	commonhandlers.RespondError(c, http.StatusTooManyRequests, "Too many requests, please try again later")
+	return false
+}
+
+// ByClientIP returns middleware that limits requests per client IP
+func (l *Limiter) ByClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.Check(c, c.ClientIP()) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// retryAfterSeconds formats a Retry-After value, rounding up to whole seconds
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
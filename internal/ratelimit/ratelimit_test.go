@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// fakeClock is a manually advanced clock for MemoryStore
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time          { return f.t }
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	return s, clock
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	s, clock := newTestStore()
+	limit := Limit{Burst: 3, Period: time.Minute}
+	ctx := context.Background()
+
+	for i := range 3 {
+		if d, _ := s.Take(ctx, "a", limit); !d.Allowed {
+			t.Fatalf("take %d: expected allowed", i+1)
+		}
+	}
+	d, _ := s.Take(ctx, "a", limit)
+	if d.Allowed {
+		t.Fatal("expected bucket to be empty after burst")
+	}
+	if d.RetryAfter != 20*time.Second {
+		t.Errorf("RetryAfter = %v, want 20s", d.RetryAfter)
+	}
+
+	// Other keys have their own bucket
+	if d, _ := s.Take(ctx, "b", limit); !d.Allowed {
+		t.Error("expected a separate bucket for another key")
+	}
+
+	// One token refills every Period/Burst
+	clock.advance(20 * time.Second)
+	if d, _ := s.Take(ctx, "a", limit); !d.Allowed {
+		t.Error("expected a token after one refill interval")
+	}
+	if d, _ := s.Take(ctx, "a", limit); d.Allowed {
+		t.Error("expected only one token to have refilled")
+	}
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	s, clock := newTestStore()
+	limit := Limit{Burst: 2, Period: time.Minute}
+	ctx := context.Background()
+
+	_, _ = s.Take(ctx, "idle", limit)
+	clock.advance(2 * time.Minute)
+	_, _ = s.Take(ctx, "active", limit)
+
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("expected refilled bucket to be swept")
+	}
+	if _, ok := s.buckets["active"]; !ok {
+		t.Error("expected active bucket to be kept")
+	}
+}
+
+// failingStore always errors, like an unreachable Redis
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Decision, error) {
+	return Decision{}, errors.New("connection refused")
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestByClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store, _ := newTestStore()
+	rejected := prometheus.NewCounter(prometheus.CounterOpts{Name: "rejected"})
+	limiter := New(store, "contact_ip", Limit{Burst: 1, Period: 90 * time.Second}, rejected)
+
+	router := gin.New()
+	router.POST("/contact", limiter.ByClientIP(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	post := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/contact", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("203.0.113.7"); w.Code != http.StatusCreated {
+		t.Fatalf("first request: expected %d, got %d", http.StatusCreated, w.Code)
+	}
+	w := post("203.0.113.7")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: expected %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("Retry-After = %q, want 90", got)
+	}
+	if w := post("198.51.100.1"); w.Code != http.StatusCreated {
+		t.Errorf("other IP: expected %d, got %d", http.StatusCreated, w.Code)
+	}
+	if got := counterValue(t, rejected); got != 1 {
+		t.Errorf("rejected counter = %v, want 1", got)
+	}
+}
+
+func TestByClientIP_IgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store, _ := newTestStore()
+	limiter := New(store, "contact_ip", Limit{Burst: 1, Period: time.Minute}, nil)
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	router.POST("/contact", limiter.ByClientIP(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	post := func(forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/contact", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("198.51.100.1"); w.Code != http.StatusCreated {
+		t.Fatalf("first request: expected %d, got %d", http.StatusCreated, w.Code)
+	}
+	if w := post("198.51.100.2"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("spoofed X-Forwarded-For: expected %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+}
+
+func TestCheck_FailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := New(failingStore{}, "contact_ip", Limit{Burst: 1, Period: time.Minute}, nil)
+
+	router := gin.New()
+	router.POST("/contact", limiter.ByClientIP(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/contact", nil))
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected store errors to let requests through, got %d", w.Code)
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                      "1",
+		300 * time.Millisecond: "1",
+		20 * time.Second:       "20",
+		20*time.Second + 1:     "21",
+		12 * time.Minute:       "720",
+	} {
+		if got := retryAfterSeconds(d); got != want {
+			t.Errorf("retryAfterSeconds(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
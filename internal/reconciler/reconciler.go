@@ -0,0 +1,112 @@
+// Package reconciler publishes pending emails created before the outbox, which have no entry to relay them.
+package reconciler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	"github.com/GunarsK-portfolio/portfolio-common/queue"
+)
+
+// Store is the subset of the repository used by the reconciler
+type Store interface {
+	ClaimStalePendingEmails(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error)
+	MarkOutboxDispatched(ctx context.Context, emailID int64) error
+	CreateEmailEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) error
+}
+
+// Reconciler periodically re-publishes EmailEvents for stale pending emails
+type Reconciler struct {
+	store     Store
+	publisher queue.Publisher
+	cfg       config.ReconcilerConfig
+	metrics   *metrics.Metrics
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// New creates a new Reconciler instance
+func New(store Store, publisher queue.Publisher, cfg config.ReconcilerConfig, m *metrics.Metrics, logger *slog.Logger) *Reconciler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Reconciler{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+		metrics:   m,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run reconciles every interval until ctx is cancelled
+func (r *Reconciler) Run(ctx context.Context) {
+	r.logger.Info("Reconciler started", "interval", r.cfg.Interval.String(), "minAge", r.cfg.MinAge.String())
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Reconciler stopped")
+			return
+		case <-ticker.C:
+			r.ReconcileOnce(ctx)
+		}
+	}
+}
+
+// ReconcileOnce claims and re-publishes one batch of stale pending emails.
+// Claiming gives each email an outbox entry, so those that fail to publish are left to the outbox relay.
+// Returns the number of emails reconciled and the number that failed.
+func (r *Reconciler) ReconcileOnce(ctx context.Context) (reconciled, failed int) {
+	emails, err := r.store.ClaimStalePendingEmails(ctx, r.now().Add(-r.cfg.MinAge), r.cfg.BatchSize)
+	if err != nil {
+		r.logger.Error("Failed to load stale pending emails", "error", err)
+		return 0, 0
+	}
+
+	for _, email := range emails {
+		if ctx.Err() != nil {
+			break
+		}
+
+		event := models.EmailEvent{EmailID: email.ID}
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			r.logger.Warn("Failed to re-publish stale email", "error", err, "emailId", email.ID)
+			r.recordEvent(ctx, email.ID, repository.EmailEventPublishFailed, map[string]any{"source": eventSource, "error": err.Error()})
+			failed++
+			continue
+		}
+
+		if err := r.store.MarkOutboxDispatched(ctx, email.ID); err != nil {
+			r.logger.Error("Failed to mark outbox entry dispatched", "error", err, "emailId", email.ID)
+		}
+		reconciled++
+	}
+
+	if r.metrics != nil {
+		r.metrics.EmailsReconciled.Add(float64(reconciled))
+		r.metrics.EmailReconcileFailures.Add(float64(failed))
+	}
+	if reconciled > 0 || failed > 0 {
+		r.logger.Info("Reconciled stale pending emails", "reconciled", reconciled, "failed", failed)
+	}
+	return reconciled, failed
+}
+
+// eventSource marks timeline events written by the reconciler
+const eventSource = "reconciler"
+
+// recordEvent appends to an email's timeline; failures are only logged
+func (r *Reconciler) recordEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) {
+	if err := r.store.CreateEmailEvent(ctx, emailID, eventType, details); err != nil {
+		r.logger.Warn("Failed to record email event", "error", err, "emailId", emailID, "type", eventType)
+	}
+}
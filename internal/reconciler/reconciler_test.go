@@ -0,0 +1,189 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockStore struct {
+	emails     []models.Email
+	getErr     error
+	olderThan  time.Time
+	dispatched []int64
+	events     []string
+}
+
+func (m *mockStore) ClaimStalePendingEmails(_ context.Context, olderThan time.Time, _ int) ([]models.Email, error) {
+	m.olderThan = olderThan
+	return m.emails, m.getErr
+}
+
+func (m *mockStore) MarkOutboxDispatched(_ context.Context, emailID int64) error {
+	m.dispatched = append(m.dispatched, emailID)
+	return nil
+}
+
+func (m *mockStore) CreateEmailEvent(_ context.Context, emailID int64, eventType string, _ map[string]any) error {
+	m.events = append(m.events, fmt.Sprintf("%d:%s", emailID, eventType))
+	return nil
+}
+
+type mockPublisher struct {
+	publishFunc func(ctx context.Context, message interface{}) error
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, message interface{}) error {
+	if m.publishFunc != nil {
+		return m.publishFunc(ctx, message)
+	}
+	return nil
+}
+
+func (m *mockPublisher) PublishToRetry(_ context.Context, _ int, _ []byte, _ string, _ amqp.Table) error {
+	return nil
+}
+
+func (m *mockPublisher) PublishToDLQ(_ context.Context, _ []byte, _ string) error {
+	return nil
+}
+
+func (m *mockPublisher) MaxRetries() int {
+	return 0
+}
+
+func (m *mockPublisher) Close() error {
+	return nil
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+var fixedNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestMetrics() *metrics.Metrics {
+	return &metrics.Metrics{
+		EmailsReconciled:       prometheus.NewCounter(prometheus.CounterOpts{Name: "reconciled"}),
+		EmailReconcileFailures: prometheus.NewCounter(prometheus.CounterOpts{Name: "failures"}),
+	}
+}
+
+func newTestReconciler(store Store, pub *mockPublisher, m *metrics.Metrics) *Reconciler {
+	cfg := config.ReconcilerConfig{
+		Enabled:   true,
+		Interval:  time.Minute,
+		MinAge:    15 * time.Minute,
+		BatchSize: 50,
+	}
+	r := New(store, pub, cfg, m, nil)
+	r.now = func() time.Time { return fixedNow }
+	return r
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := c.Write(&metric); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+// =============================================================================
+// ReconcileOnce Tests
+// =============================================================================
+
+func TestReconcileOnce_RepublishesStaleEmails(t *testing.T) {
+	store := &mockStore{emails: []models.Email{{ID: 1}, {ID: 2}}}
+	var published []int64
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		published = append(published, msg.(models.EmailEvent).EmailID)
+		return nil
+	}}
+	m := newTestMetrics()
+
+	reconciled, failed := newTestReconciler(store, pub, m).ReconcileOnce(context.Background())
+
+	if reconciled != 2 || failed != 0 {
+		t.Errorf("reconciled=%d failed=%d, want 2/0", reconciled, failed)
+	}
+	if !slices.Equal(published, []int64{1, 2}) || !slices.Equal(store.dispatched, []int64{1, 2}) {
+		t.Errorf("published = %v, dispatched = %v, want [1 2]", published, store.dispatched)
+	}
+	if want := fixedNow.Add(-15 * time.Minute); !store.olderThan.Equal(want) {
+		t.Errorf("olderThan = %v, want %v", store.olderThan, want)
+	}
+	if got := counterValue(t, m.EmailsReconciled); got != 2 {
+		t.Errorf("reconciled counter = %v, want 2", got)
+	}
+}
+
+func TestReconcileOnce_PublishFailureCounted(t *testing.T) {
+	store := &mockStore{emails: []models.Email{{ID: 1}, {ID: 2}}}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		if msg.(models.EmailEvent).EmailID == 2 {
+			return errors.New("mq down")
+		}
+		return nil
+	}}
+	m := newTestMetrics()
+
+	reconciled, failed := newTestReconciler(store, pub, m).ReconcileOnce(context.Background())
+
+	if reconciled != 1 || failed != 1 {
+		t.Errorf("reconciled=%d failed=%d, want 1/1", reconciled, failed)
+	}
+	if got := counterValue(t, m.EmailReconcileFailures); got != 1 {
+		t.Errorf("failure counter = %v, want 1", got)
+	}
+	if !slices.Equal(store.dispatched, []int64{1}) {
+		t.Errorf("dispatched = %v, want [1]", store.dispatched)
+	}
+	if want := []string{"2:publish_failed"}; !slices.Equal(store.events, want) {
+		t.Errorf("events = %v, want %v", store.events, want)
+	}
+}
+
+func TestReconcileOnce_StoreError(t *testing.T) {
+	store := &mockStore{getErr: errors.New("database error")}
+
+	reconciled, failed := newTestReconciler(store, &mockPublisher{}, nil).ReconcileOnce(context.Background())
+
+	if reconciled != 0 || failed != 0 {
+		t.Errorf("reconciled=%d failed=%d, want 0/0", reconciled, failed)
+	}
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	r := newTestReconciler(&mockStore{}, &mockPublisher{}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reconciler did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Attachment is a file linked to an email. The blob lives in attachment storage under StorageKey.
+type Attachment struct {
+	ID          int64     `json:"id" gorm:"primaryKey"`
+	EmailID     int64     `json:"emailId" gorm:"column:email_id"`
+	Filename    string    `json:"filename" gorm:"column:filename"`
+	ContentType string    `json:"contentType" gorm:"column:content_type"`
+	SizeBytes   int64     `json:"sizeBytes" gorm:"column:size_bytes"`
+	StorageKey  string    `json:"-" gorm:"column:storage_key"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (Attachment) TableName() string {
+	return "messaging.email_attachments"
+}
+
+// GetAttachment retrieves an attachment of an email
+func (r *repository) GetAttachment(ctx context.Context, emailID, attachmentID int64) (*Attachment, error) {
+	var attachment Attachment
+	err := r.db.WithContext(ctx).
+		Where("email_id = ?", emailID).
+		First(&attachment, attachmentID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment %d of email %d: %w", attachmentID, emailID, err)
+	}
+	return &attachment, nil
+}
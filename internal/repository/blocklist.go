@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrBlocklistEntryExists is returned when an identical blocklist entry already exists
+var ErrBlocklistEntryExists = errors.New("blocklist entry already exists")
+
+// Blocklist entry kinds
+const (
+	BlocklistKindAddress = "address"
+	BlocklistKindDomain  = "domain"
+	BlocklistKindPattern = "pattern"
+	BlocklistKindCIDR    = "cidr"
+)
+
+// BlocklistEntry blocks contact messages from an address, a domain and its subdomains, addresses
+// matching a wildcard pattern, or client IPs in a CIDR range. Values are stored lower-cased.
+type BlocklistEntry struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	Kind      string    `json:"kind" gorm:"column:kind"`
+	Value     string    `json:"value" gorm:"column:value"`
+	Reason    *string   `json:"reason,omitempty" gorm:"column:reason"`
+	CreatedBy *string   `json:"createdBy,omitempty" gorm:"column:created_by"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+func (BlocklistEntry) TableName() string {
+	return "messaging.blocklist_entries"
+}
+
+// createBlocklistEntry inserts an entry using the given transaction; an existing identical entry is kept
+func createBlocklistEntry(tx *gorm.DB, entry *BlocklistEntry) error {
+	entry.Value = strings.ToLower(strings.TrimSpace(entry.Value))
+	return tx.Omit("ID", "CreatedAt", "UpdatedAt").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "kind"}, {Name: "value"}}, DoNothing: true}).
+		Create(entry).Error
+}
+
+// GetBlocklistEntries retrieves all blocklist entries, newest first
+func (r *repository) GetBlocklistEntries(ctx context.Context) ([]BlocklistEntry, error) {
+	var entries []BlocklistEntry
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC, id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocklist entries: %w", err)
+	}
+	return entries, nil
+}
+
+// GetBlocklistEntryByID retrieves a blocklist entry by ID
+func (r *repository) GetBlocklistEntryByID(ctx context.Context, id int64) (*BlocklistEntry, error) {
+	var entry BlocklistEntry
+	err := r.db.WithContext(ctx).First(&entry, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocklist entry by id %d: %w", id, err)
+	}
+	return &entry, nil
+}
+
+// CreateBlocklistEntry creates a blocklist entry.
+// Returns ErrBlocklistEntryExists if the same kind and value are already blocked.
+func (r *repository) CreateBlocklistEntry(ctx context.Context, entry *BlocklistEntry) error {
+	entry.Value = strings.ToLower(strings.TrimSpace(entry.Value))
+	result := r.db.WithContext(ctx).
+		Omit("ID", "CreatedAt", "UpdatedAt").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "kind"}, {Name: "value"}}, DoNothing: true}).
+		Create(entry)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create blocklist entry: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to create blocklist entry: %w", ErrBlocklistEntryExists)
+	}
+	return nil
+}
+
+// UpdateBlocklistEntry updates an entry's kind, value and reason.
+// Returns ErrBlocklistEntryExists if another entry already has the same kind and value.
+func (r *repository) UpdateBlocklistEntry(ctx context.Context, entry *BlocklistEntry) error {
+	entry.Value = strings.ToLower(strings.TrimSpace(entry.Value))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&BlocklistEntry{}).
+			Where("kind = ? AND value = ? AND id <> ?", entry.Kind, entry.Value, entry.ID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrBlocklistEntryExists
+		}
+
+		result := tx.Model(&BlocklistEntry{ID: entry.ID}).Updates(map[string]any{
+			"kind":       entry.Kind,
+			"value":      entry.Value,
+			"reason":     entry.Reason,
+			"updated_at": tx.NowFunc(),
+		})
+		return checkRowsAffected(result)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update blocklist entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteBlocklistEntry deletes a blocklist entry by ID
+func (r *repository) DeleteBlocklistEntry(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&BlocklistEntry{}, id)
+	if err := checkRowsAffected(result); err != nil {
+		return fmt.Errorf("failed to delete blocklist entry: %w", err)
+	}
+	return nil
+}
@@ -3,49 +3,241 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/GunarsK-portfolio/portfolio-common/models"
 	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// CreateEmail creates a new email record
-func (r *repository) CreateEmail(ctx context.Context, email *models.Email) error {
-	err := r.db.WithContext(ctx).
-		Omit("ID", "CreatedAt", "UpdatedAt").
-		Create(email).Error
+// CreateEmail creates a new email record and its outbox entry in one transaction
+func (r *repository) CreateEmail(ctx context.Context, email *Email) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createEmail(tx, email)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create email: %w", err)
 	}
 	return nil
 }
 
-// defaultEmailLimit caps the number of emails returned to prevent OOM on large datasets
-const defaultEmailLimit = 100
+// createEmail inserts an email using the given transaction. Only pending emails get an outbox
+// entry; held emails (e.g. quarantined or scheduled) are queued when they are released.
+func createEmail(tx *gorm.DB, email *Email) error {
+	if err := tx.Omit("ID", "CreatedAt", "UpdatedAt").Create(email).Error; err != nil {
+		return err
+	}
+	details := map[string]any{"type": email.Type, "status": email.Status}
+	if email.SendAt != nil {
+		details["sendAt"] = email.SendAt
+	}
+	if err := createEmailEvent(tx, email.ID, EmailEventCreated, details); err != nil {
+		return err
+	}
+	if email.Status != models.EmailStatusPending {
+		return nil
+	}
+	return createOutboxEntry(tx, email.ID)
+}
 
-// GetEmails retrieves recent emails (capped at defaultEmailLimit)
-func (r *repository) GetEmails(ctx context.Context) ([]models.Email, error) {
-	var emails []models.Email
-	err := r.db.WithContext(ctx).
-		Order("created_at DESC").
-		Limit(defaultEmailLimit).
+// CreateThrottledEmail creates an email and its outbox entry unless an email of the same type
+// was already created for the same recipient since the given time. It reports whether the email was created.
+// A per-recipient advisory lock keeps concurrent requests from both passing the check.
+func (r *repository) CreateThrottledEmail(ctx context.Context, email *Email, since time.Time) (bool, error) {
+	if email.RecipientEmail == nil {
+		return false, fmt.Errorf("failed to create throttled email: recipient is required")
+	}
+
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(LOWER(?)))", *email.RecipientEmail).Error; err != nil {
+			return err
+		}
+
+		var recent int64
+		err := tx.Model(&Email{}).
+			Where("type = ? AND LOWER(recipient_email) = LOWER(?) AND created_at >= ?", email.Type, *email.RecipientEmail, since).
+			Limit(1).
+			Count(&recent).Error
+		if err != nil {
+			return err
+		}
+		if recent > 0 {
+			return nil
+		}
+
+		if err := createEmail(tx, email); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create throttled email: %w", err)
+	}
+	return created, nil
+}
+
+// CreateDedupedEmail creates an email and its outbox entry unless an email with the same
+// ContentHash was created since the given time. It reports whether the email was created.
+// A per-hash advisory lock keeps two concurrent submissions of the same content from both passing the check.
+func (r *repository) CreateDedupedEmail(ctx context.Context, email *Email, since time.Time) (bool, error) {
+	if email.ContentHash == nil {
+		return false, fmt.Errorf("failed to create deduplicated email: content hash is required")
+	}
+
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", *email.ContentHash).Error; err != nil {
+			return err
+		}
+
+		var duplicates int64
+		err := tx.Model(&Email{}).
+			Where("content_hash = ? AND created_at >= ?", *email.ContentHash, since).
+			Limit(1).
+			Count(&duplicates).Error
+		if err != nil {
+			return err
+		}
+		if duplicates > 0 {
+			return nil
+		}
+
+		if err := createEmail(tx, email); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create deduplicated email: %w", err)
+	}
+	return created, nil
+}
+
+// maxEmailLimit caps the page size to prevent OOM on large datasets
+const maxEmailLimit = 100
+
+// EmailFilter narrows and pages email listings. Zero values are ignored.
+type EmailFilter struct {
+	Type           string
+	Status         string
+	SenderEmail    string
+	RecipientEmail string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	After          *EmailCursor
+	Limit          int
+}
+
+// EmailCursor identifies the last email of a page in (created_at, id) order
+type EmailCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// EmailPage holds one page of emails and the cursor of the next page (nil on the last page)
+type EmailPage struct {
+	Emails     []Email
+	NextCursor *EmailCursor
+}
+
+// GetEmails retrieves a page of emails, newest first (page size capped at maxEmailLimit)
+func (r *repository) GetEmails(ctx context.Context, filter EmailFilter) (*EmailPage, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > maxEmailLimit {
+		limit = maxEmailLimit
+	}
+
+	query := r.db.WithContext(ctx).Model(&Email{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.SenderEmail != "" {
+		query = query.Where("LOWER(email) = LOWER(?)", filter.SenderEmail)
+	}
+	if filter.RecipientEmail != "" {
+		query = query.Where("LOWER(recipient_email) = LOWER(?)", filter.RecipientEmail)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	// Fetch one extra row to detect whether another page exists
+	var emails []Email
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit + 1).
 		Find(&emails).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get emails: %w", err)
 	}
-	return emails, nil
+
+	page := &EmailPage{Emails: emails}
+	if len(emails) > limit {
+		page.Emails = emails[:limit]
+		last := page.Emails[limit-1]
+		page.NextCursor = &EmailCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
 }
 
-// GetEmailByID retrieves an email by ID
-func (r *repository) GetEmailByID(ctx context.Context, id int64) (*models.Email, error) {
-	var email models.Email
-	err := r.db.WithContext(ctx).First(&email, id).Error
+// GetEmailByID retrieves an email by ID with its attachments and automatic replies
+func (r *repository) GetEmailByID(ctx context.Context, id int64) (*Email, error) {
+	var email Email
+	err := r.db.WithContext(ctx).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Replies", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&email, id).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get email by id %d: %w", id, err)
 	}
 	return &email, nil
 }
 
-// UpdateEmailStatus delegates to the shared helper in portfolio-common
+// ClaimStalePendingEmails claims pending emails not updated since olderThan that have no outbox entry
+// at all (created before the outbox existed) by giving each one an entry, so no email is claimed twice
+// and the outbox relay retries any the caller fails to publish. Emails that already went through the
+// outbox are left alone: once published, they are the worker's.
+// Rows are selected with SKIP LOCKED so concurrent claims never return the same email.
+func (r *repository) ClaimStalePendingEmails(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error) {
+	var emails []models.Email
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND updated_at < ?", models.EmailStatusPending, olderThan).
+			Where("NOT EXISTS (SELECT 1 FROM messaging.email_outbox o WHERE o.email_id = emails.id)").
+			Order("updated_at ASC, id ASC").
+			Limit(limit).
+			Find(&emails).Error
+		if err != nil {
+			return err
+		}
+		for _, email := range emails {
+			if err := createOutboxEntry(tx, email.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim stale pending emails: %w", err)
+	}
+	return emails, nil
+}
+
+// UpdateEmailStatus delegates to the shared helper in portfolio-common. The change reaches the
+// email's timeline through a database trigger, which also records the worker's updates.
 func (r *repository) UpdateEmailStatus(ctx context.Context, id int64, status string, lastError *string) error {
 	return commonrepo.UpdateEmailStatus(r.db, ctx, id, status, lastError)
 }
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// Email is a models.Email plus the columns this service adds to messaging.emails.
+// The shared model is embedded so JSON and column names stay flat.
+// Message holds the HTML body; TextBody is its plain-text alternative.
+type Email struct {
+	models.Email
+	// TextBody is the plain-text alternative to the HTML in Message (nil for emails created before it existed)
+	TextBody *string `json:"textBody,omitempty" gorm:"column:text_body"`
+	// TemplateVersionID is the database template version the email was rendered from (nil for built-in templates)
+	TemplateVersionID *int64 `json:"templateVersionId,omitempty" gorm:"column:template_version_id"`
+	// Locale is the canonical language tag requested by the caller (nil for the default locale)
+	Locale *string `json:"locale,omitempty" gorm:"column:locale"`
+	// Attachments are created with the email and loaded by GetEmailByID
+	Attachments []Attachment `json:"attachments,omitempty" gorm:"foreignKey:EmailID"`
+	// InReplyToID links an automatic reply (e.g. contact_ack) to the email that triggered it
+	InReplyToID *int64 `json:"inReplyToId,omitempty" gorm:"column:in_reply_to_id"`
+	// Replies are the automatic replies to this email, loaded by GetEmailByID
+	Replies []Email `json:"replies,omitempty" gorm:"foreignKey:InReplyToID"`
+	// SpamScore and SpamReasons record the spam pipeline's verdict on contact messages (nil for other emails)
+	SpamScore   *int     `json:"spamScore,omitempty" gorm:"column:spam_score"`
+	SpamReasons []string `json:"spamReasons,omitempty" gorm:"column:spam_reasons;serializer:json"`
+	// ContentHash identifies a contact message by sender, subject and text, to spot repeated submissions
+	ContentHash *string `json:"-" gorm:"column:content_hash"`
+	// SendAt is when a scheduled email is due for delivery (nil for emails sent immediately)
+	SendAt *time.Time `json:"sendAt,omitempty" gorm:"column:send_at"`
+}
+
+func (Email) TableName() string {
+	return "messaging.emails"
+}
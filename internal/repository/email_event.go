@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Email event types
+const (
+	EmailEventCreated       = "created"
+	EmailEventPublished     = "published"
+	EmailEventPublishFailed = "publish_failed"
+	EmailEventSending       = "sending"
+	EmailEventSent          = "sent"
+	EmailEventFailed        = "failed"
+	EmailEventRetried       = "retried"
+	EmailEventCancelled     = "cancelled"
+	EmailEventApproved      = "approved"
+	EmailEventRejected      = "rejected"
+	EmailEventReleased      = "released"
+	EmailEventDelivered     = "delivered"
+	EmailEventBounced       = "bounced"
+	EmailEventComplained    = "complained"
+)
+
+// EmailEvent is one entry in an email's append-only timeline (not to be confused with
+// models.EmailEvent, the queue message). Details hold type-specific context such as errors.
+type EmailEvent struct {
+	ID      int64          `json:"id" gorm:"primaryKey"`
+	EmailID int64          `json:"emailId" gorm:"column:email_id"`
+	Type    string         `json:"type" gorm:"column:type"`
+	Details map[string]any `json:"details,omitempty" gorm:"column:details;serializer:json"`
+	// ProviderEventID is set on delivery events and makes redelivered webhooks a no-op
+	ProviderEventID *string   `json:"providerEventId,omitempty" gorm:"column:provider_event_id"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (EmailEvent) TableName() string {
+	return "messaging.email_events"
+}
+
+// GetEmailEvents returns an email's timeline, oldest first.
+// Returns gorm.ErrRecordNotFound if the email does not exist.
+func (r *repository) GetEmailEvents(ctx context.Context, emailID int64) ([]EmailEvent, error) {
+	var events []EmailEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Email{}).Where("id = ?", emailID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("email_id = ?", emailID).Order("created_at ASC, id ASC").Find(&events).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events for email %d: %w", emailID, err)
+	}
+	return events, nil
+}
+
+// CreateEmailEvent appends an event to an email's timeline
+func (r *repository) CreateEmailEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) error {
+	if err := createEmailEvent(r.db.WithContext(ctx), emailID, eventType, details); err != nil {
+		return fmt.Errorf("failed to create %s event for email %d: %w", eventType, emailID, err)
+	}
+	return nil
+}
+
+// CreateDeliveryEvent appends a provider delivery event to an email's timeline and, if status is set,
+// moves the email to it in the same transaction. An event of the same type with the same
+// ProviderEventID is only recorded once; a repeat changes nothing and reports false.
+func (r *repository) CreateDeliveryEvent(ctx context.Context, event *EmailEvent, status string, lastError *string) (bool, error) {
+	recorded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit("ID", "CreatedAt").Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		recorded = true
+		if status == "" {
+			return nil
+		}
+		return commonrepo.UpdateEmailStatus(tx, ctx, event.EmailID, status, lastError)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create %s event for email %d: %w", event.Type, event.EmailID, err)
+	}
+	return recorded, nil
+}
+
+// createEmailEvent appends an event using the given transaction, so it commits with the change it records
+func createEmailEvent(tx *gorm.DB, emailID int64, eventType string, details map[string]any) error {
+	event := &EmailEvent{EmailID: emailID, Type: eventType, Details: details}
+	return tx.Omit("ID", "CreatedAt").Create(event).Error
+}
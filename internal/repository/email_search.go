@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Snippet markers wrapped around matched terms by SearchEmails.
+// Control characters keep highlights unambiguous so callers can escape the text safely.
+const (
+	SnippetMatchStart = "\x02"
+	SnippetMatchStop  = "\x03"
+)
+
+// maxSearchLimit caps the number of search results per request
+const maxSearchLimit = 50
+
+// searchTextConfig is the PostgreSQL text search configuration used by the search_vector column.
+// "simple" avoids English-only stemming since messages arrive in several languages.
+const searchTextConfig = "simple"
+
+// EmailSearchFilter holds a full-text query and optional narrowing filters
+type EmailSearchFilter struct {
+	Query       string
+	Type        string
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Limit       int
+	Offset      int
+}
+
+// EmailSearchResult is an email matched by full-text search with its rank and highlighted snippet
+type EmailSearchResult struct {
+	Email
+	Rank    float64 `json:"rank" gorm:"column:rank"`
+	Snippet string  `json:"snippet" gorm:"column:snippet"`
+}
+
+// SearchEmails runs a ranked full-text search over subject, message, name and sender email
+func (r *repository) SearchEmails(ctx context.Context, filter EmailSearchFilter) ([]EmailSearchResult, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	headlineOpts := fmt.Sprintf("StartSel=%s,StopSel=%s,MaxFragments=2,MaxWords=30,MinWords=10,FragmentDelimiter=\" … \"",
+		SnippetMatchStart, SnippetMatchStop)
+
+	query := r.db.WithContext(ctx).
+		Table("messaging.emails AS emails, websearch_to_tsquery(?, ?) AS q", searchTextConfig, filter.Query).
+		Select("emails.*, ts_rank_cd(emails.search_vector, q) AS rank, ts_headline(?, COALESCE(emails.text_body, emails.message), q, ?) AS snippet",
+			searchTextConfig, headlineOpts).
+		Where("emails.search_vector @@ q")
+
+	if filter.Type != "" {
+		query = query.Where("emails.type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("emails.status = ?", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("emails.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("emails.created_at < ?", *filter.CreatedTo)
+	}
+
+	var results []EmailSearchResult
+	err := query.
+		Order("rank DESC, emails.created_at DESC, emails.id DESC").
+		Limit(limit).
+		Offset(filter.Offset).
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search emails: %w", err)
+	}
+	return results, nil
+}
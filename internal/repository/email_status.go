@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
+	"gorm.io/gorm"
+)
+
+// EmailStatusCancelled marks an email withdrawn before delivery.
+// Local to this service until portfolio-common knows about it.
+const EmailStatusCancelled = "cancelled"
+
+// EmailStatusQuarantined marks a contact message held for review by the spam pipeline.
+// Quarantined emails get no outbox entry and are never delivered unless approved.
+const EmailStatusQuarantined = "quarantined"
+
+// EmailStatusSpam marks a quarantined email an admin rejected as spam
+const EmailStatusSpam = "spam"
+
+// EmailStatusScheduled marks an email held until its SendAt time.
+// Scheduled emails get no outbox entry until the scheduler releases them to pending.
+const EmailStatusScheduled = "scheduled"
+
+// ErrEmailStatusConflict is returned when an email's current status does not allow the requested transition
+var ErrEmailStatusConflict = errors.New("email status does not allow this transition")
+
+// RetryEmail moves a failed email back to pending with a fresh attempt count and queues a new outbox entry.
+// Returns ErrEmailStatusConflict if the email is no longer failed.
+func (r *repository) RetryEmail(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Compare-and-set on the failed status so concurrent retries queue at most one event
+		result := tx.Model(&models.Email{}).
+			Where("id = ? AND status = ?", id, models.EmailStatusFailed).
+			Update("attempts", 0)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEmailStatusConflict
+		}
+
+		if err := commonrepo.UpdateEmailStatus(tx, ctx, id, models.EmailStatusPending, nil); err != nil {
+			return err
+		}
+		if err := createEmailEvent(tx, id, EmailEventRetried, nil); err != nil {
+			return err
+		}
+		return createOutboxEntry(tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retry email %d: %w", id, err)
+	}
+	return nil
+}
+
+// CancelEmail moves a pending or scheduled email to cancelled and drops its undispatched outbox entries.
+// Returns ErrEmailStatusConflict if a worker has already claimed the email.
+func (r *repository) CancelEmail(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Compare-and-set on pending/scheduled so a worker or the scheduler that already moved the email on wins
+		result := tx.Model(&models.Email{}).
+			Where("id = ? AND status IN ?", id, []string{models.EmailStatusPending, EmailStatusScheduled}).
+			Update("status", EmailStatusCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEmailStatusConflict
+		}
+
+		if err := createEmailEvent(tx, id, EmailEventCancelled, nil); err != nil {
+			return err
+		}
+		return tx.Where("email_id = ? AND dispatched_at IS NULL", id).Delete(&OutboxEntry{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel email %d: %w", id, err)
+	}
+	return nil
+}
+
+// ApproveEmail releases a quarantined email for delivery by moving it to pending with a new outbox entry.
+// Returns ErrEmailStatusConflict if the email is no longer quarantined.
+func (r *repository) ApproveEmail(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Email{}).
+			Where("id = ? AND status = ?", id, EmailStatusQuarantined).
+			Update("status", models.EmailStatusPending)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEmailStatusConflict
+		}
+		if err := createEmailEvent(tx, id, EmailEventApproved, nil); err != nil {
+			return err
+		}
+		return createOutboxEntry(tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to approve email %d: %w", id, err)
+	}
+	return nil
+}
+
+// RejectEmail marks a quarantined email as spam and, if block is non-nil, adds it to the blocklist.
+// Returns ErrEmailStatusConflict if the email is no longer quarantined.
+func (r *repository) RejectEmail(ctx context.Context, id int64, block *BlocklistEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Email{}).
+			Where("id = ? AND status = ?", id, EmailStatusQuarantined).
+			Update("status", EmailStatusSpam)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEmailStatusConflict
+		}
+		if err := createEmailEvent(tx, id, EmailEventRejected, map[string]any{"blocked": block != nil}); err != nil {
+			return err
+		}
+		if block == nil {
+			return nil
+		}
+		return createBlocklistEntry(tx, block)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reject email %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrIdempotencyKeyExists is returned when another request stored the same key first
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// IdempotencyKey ties a client-supplied Idempotency-Key to the email its first request created.
+// Keys are scoped to the caller, so different callers may use the same key.
+type IdempotencyKey struct {
+	Caller      string    `json:"caller" gorm:"column:caller;primaryKey"`
+	Key         string    `json:"key" gorm:"column:key;primaryKey"`
+	RequestHash string    `json:"requestHash" gorm:"column:request_hash"`
+	EmailID     int64     `json:"emailId" gorm:"column:email_id"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at"`
+	ExpiresAt   time.Time `json:"expiresAt" gorm:"column:expires_at"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "messaging.email_idempotency_keys"
+}
+
+// GetIdempotencyKey retrieves a caller's unexpired idempotency key. Returns nil if none exists.
+func (r *repository) GetIdempotencyKey(ctx context.Context, caller, key string) (*IdempotencyKey, error) {
+	var stored IdempotencyKey
+	err := r.db.WithContext(ctx).
+		Where("caller = ? AND key = ? AND expires_at > ?", caller, key, r.db.NowFunc()).
+		Take(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &stored, nil
+}
+
+// CreateEmailWithIdempotencyKey creates an email, its outbox entry and its idempotency key in one transaction.
+// An expired copy of the key is replaced; returns ErrIdempotencyKeyExists if the key is still live.
+func (r *repository) CreateEmailWithIdempotencyKey(ctx context.Context, email *Email, key *IdempotencyKey) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("caller = ? AND key = ? AND expires_at <= ?", key.Caller, key.Key, tx.NowFunc()).
+			Delete(&IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := createEmail(tx, email); err != nil {
+			return err
+		}
+
+		key.EmailID = email.ID
+		result := tx.Omit("CreatedAt").Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrIdempotencyKeyExists
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes keys that expired before now and returns how many were removed
+func (r *repository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxEntry is an EmailEvent awaiting publication to the queue.
+// Written in the same transaction as its email so a broker outage never loses it.
+type OutboxEntry struct {
+	ID            int64      `json:"id" gorm:"primaryKey"`
+	EmailID       int64      `json:"emailId" gorm:"column:email_id"`
+	Attempts      int        `json:"attempts" gorm:"column:attempts;default:0"`
+	LastError     *string    `json:"lastError,omitempty" gorm:"column:last_error"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"column:next_attempt_at"`
+	DispatchedAt  *time.Time `json:"dispatchedAt,omitempty" gorm:"column:dispatched_at"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (OutboxEntry) TableName() string {
+	return "messaging.email_outbox"
+}
+
+// createOutboxEntry inserts an undispatched outbox entry for an email using the given transaction
+func createOutboxEntry(tx *gorm.DB, emailID int64) error {
+	entry := &OutboxEntry{
+		EmailID:       emailID,
+		NextAttemptAt: tx.NowFunc(),
+	}
+	return tx.Omit("ID", "CreatedAt").Create(entry).Error
+}
+
+// ClaimDueOutboxEntries claims undispatched entries whose next attempt is due by pushing their
+// next attempt to claimUntil, so relays on other replicas skip them while this one publishes.
+// Rows are selected with SKIP LOCKED so concurrent claims never return the same entry.
+// Entries created after createdBefore are skipped so in-flight eager publishes are not duplicated.
+func (r *repository) ClaimDueOutboxEntries(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]OutboxEntry, error) {
+	var entries []OutboxEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL AND next_attempt_at <= ? AND created_at <= ?", tx.NowFunc(), createdBefore).
+			Order("next_attempt_at ASC, id ASC").
+			Limit(limit).
+			Find(&entries).Error
+		if err != nil || len(entries) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.ID
+		}
+		return tx.Model(&OutboxEntry{}).Where("id IN ?", ids).Update("next_attempt_at", claimUntil).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due outbox entries: %w", err)
+	}
+	return entries, nil
+}
+
+// MarkOutboxDispatched marks all undispatched outbox entries for an email as dispatched,
+// recording a published event if there were any
+func (r *repository) MarkOutboxDispatched(ctx context.Context, emailID int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&OutboxEntry{}).
+			Where("email_id = ? AND dispatched_at IS NULL", emailID).
+			Update("dispatched_at", tx.NowFunc())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return createEmailEvent(tx, emailID, EmailEventPublished, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox dispatched for email %d: %w", emailID, err)
+	}
+	return nil
+}
+
+// RecordOutboxFailure increments attempts, schedules the next publish attempt and records a publish_failed event
+func (r *repository) RecordOutboxFailure(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entry OutboxEntry
+		result := tx.Model(&entry).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "email_id"}, {Name: "attempts"}}}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"last_error":      lastError,
+				"next_attempt_at": nextAttemptAt,
+			})
+		if err := checkRowsAffected(result); err != nil {
+			return err
+		}
+		return createEmailEvent(tx, entry.EmailID, EmailEventPublishFailed, map[string]any{
+			"error":         lastError,
+			"attempts":      entry.Attempts,
+			"nextAttemptAt": nextAttemptAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record outbox failure %d: %w", id, err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/GunarsK-portfolio/portfolio-common/models"
 	commonrepo "github.com/GunarsK-portfolio/portfolio-common/repository"
@@ -11,10 +12,60 @@ import (
 // Repository defines the interface for messaging data operations
 type Repository interface {
 	// Emails (contact form: create, admin: list/get, S2S: create typed emails)
-	CreateEmail(ctx context.Context, email *models.Email) error
-	GetEmails(ctx context.Context) ([]models.Email, error)
-	GetEmailByID(ctx context.Context, id int64) (*models.Email, error)
+	CreateEmail(ctx context.Context, email *Email) error
+	CreateThrottledEmail(ctx context.Context, email *Email, since time.Time) (bool, error)
+	CreateDedupedEmail(ctx context.Context, email *Email, since time.Time) (bool, error)
+	CreateEmailWithIdempotencyKey(ctx context.Context, email *Email, key *IdempotencyKey) error
+	GetIdempotencyKey(ctx context.Context, caller, key string) (*IdempotencyKey, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+	GetEmails(ctx context.Context, filter EmailFilter) (*EmailPage, error)
+	GetEmailByID(ctx context.Context, id int64) (*Email, error)
+	SearchEmails(ctx context.Context, filter EmailSearchFilter) ([]EmailSearchResult, error)
 	UpdateEmailStatus(ctx context.Context, id int64, status string, lastError *string) error
+	RetryEmail(ctx context.Context, id int64) error
+	CancelEmail(ctx context.Context, id int64) error
+	ApproveEmail(ctx context.Context, id int64) error
+	RejectEmail(ctx context.Context, id int64, block *BlocklistEntry) error
+	ClaimStalePendingEmails(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error)
+	GetAttachment(ctx context.Context, emailID, attachmentID int64) (*Attachment, error)
+
+	// Email timeline (appended with every change, admin: per-email listing)
+	GetEmailEvents(ctx context.Context, emailID int64) ([]EmailEvent, error)
+	CreateEmailEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) error
+	CreateDeliveryEvent(ctx context.Context, event *EmailEvent, status string, lastError *string) (bool, error)
+
+	// Blocklist (contact form: cached sender check, admin: CRUD and reject with block)
+	GetBlocklistEntries(ctx context.Context) ([]BlocklistEntry, error)
+	GetBlocklistEntryByID(ctx context.Context, id int64) (*BlocklistEntry, error)
+	CreateBlocklistEntry(ctx context.Context, entry *BlocklistEntry) error
+	UpdateBlocklistEntry(ctx context.Context, entry *BlocklistEntry) error
+	DeleteBlocklistEntry(ctx context.Context, id int64) error
+
+	// Suppressions (SendEmail: recipient check, admin: list, add and lift)
+	GetSuppressions(ctx context.Context, filter SuppressionFilter) ([]Suppression, error)
+	GetSuppressionByID(ctx context.Context, id int64) (*Suppression, error)
+	GetActiveSuppression(ctx context.Context, address string) (*Suppression, error)
+	CreateSuppression(ctx context.Context, suppression *Suppression) error
+	LiftSuppression(ctx context.Context, id int64, liftedBy *string) error
+
+	// Outbox (written with each email, drained by the relay)
+	ClaimDueOutboxEntries(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]OutboxEntry, error)
+	MarkOutboxDispatched(ctx context.Context, emailID int64) error
+	RecordOutboxFailure(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+
+	// Scheduled emails (SendEmail with send_at, released by the scheduler)
+	ReleaseDueScheduledEmails(ctx context.Context, now time.Time, limit int) ([]Email, error)
+	CountScheduledEmails(ctx context.Context) (int64, error)
+
+	// Templates (admin: CRUD and activation, SendEmail: active version lookup)
+	GetTemplates(ctx context.Context) ([]EmailTemplate, error)
+	GetTemplateByID(ctx context.Context, id int64) (*EmailTemplate, error)
+	CreateTemplate(ctx context.Context, template *EmailTemplate, version *EmailTemplateVersion, activate bool) error
+	CreateTemplateVersion(ctx context.Context, templateID int64, version *EmailTemplateVersion, activate bool) error
+	GetTemplateVersions(ctx context.Context, templateID int64) ([]EmailTemplateVersion, error)
+	SetActiveTemplateVersion(ctx context.Context, templateID int64, version *int) error
+	DeleteTemplate(ctx context.Context, id int64) error
+	GetActiveTemplateVersion(ctx context.Context, emailType string, locales []string) (*EmailTemplateVersion, error)
 
 	// Recipients (admin only)
 	GetAllRecipients(ctx context.Context) ([]models.Recipient, error)
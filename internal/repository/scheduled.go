@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReleaseDueScheduledEmails moves up to limit scheduled emails whose send_at is at or before now to pending,
+// each with an outbox entry and a released event, and returns them (ID and SendAt only).
+// Rows are claimed with SKIP LOCKED so schedulers on several replicas never release the same email twice.
+func (r *repository) ReleaseDueScheduledEmails(ctx context.Context, now time.Time, limit int) ([]Email, error) {
+	var due []Email
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Email{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id", "send_at").
+			Where("status = ? AND send_at <= ?", EmailStatusScheduled, now).
+			Order("send_at ASC, id ASC").
+			Limit(limit).
+			Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(due))
+		for i, email := range due {
+			ids[i] = email.ID
+		}
+		if err := tx.Model(&models.Email{}).Where("id IN ?", ids).Update("status", models.EmailStatusPending).Error; err != nil {
+			return err
+		}
+		for _, email := range due {
+			if err := createEmailEvent(tx, email.ID, EmailEventReleased, map[string]any{"sendAt": email.SendAt}); err != nil {
+				return err
+			}
+			if err := createOutboxEntry(tx, email.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to release scheduled emails: %w", err)
+	}
+	return due, nil
+}
+
+// CountScheduledEmails returns how many emails are waiting for their send_at time
+func (r *repository) CountScheduledEmails(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&Email{}).Where("status = ?", EmailStatusScheduled).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count scheduled emails: %w", err)
+	}
+	return count, nil
+}
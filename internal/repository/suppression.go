@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Suppression reasons
+const (
+	SuppressionReasonHardBounce  = "hard_bounce"
+	SuppressionReasonComplaint   = "complaint"
+	SuppressionReasonManual      = "manual"
+	SuppressionReasonUnsubscribe = "unsubscribe"
+)
+
+// ErrSuppressionExists is returned when an address already has an active suppression
+var ErrSuppressionExists = errors.New("address is already suppressed")
+
+// ErrSuppressionLifted is returned when lifting a suppression that has already been lifted
+var ErrSuppressionLifted = errors.New("suppression has already been lifted")
+
+// Suppression stops emails to an address. Lifted suppressions are kept as history;
+// an address has at most one active (unlifted) suppression. Addresses are stored lower-cased.
+type Suppression struct {
+	ID        int64      `json:"id" gorm:"primaryKey"`
+	Email     string     `json:"email" gorm:"column:email"`
+	Reason    string     `json:"reason" gorm:"column:reason"`
+	Note      *string    `json:"note,omitempty" gorm:"column:note"`
+	CreatedBy *string    `json:"createdBy,omitempty" gorm:"column:created_by"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:created_at"`
+	LiftedAt  *time.Time `json:"liftedAt,omitempty" gorm:"column:lifted_at"`
+	LiftedBy  *string    `json:"liftedBy,omitempty" gorm:"column:lifted_by"`
+}
+
+func (Suppression) TableName() string {
+	return "messaging.suppressions"
+}
+
+// SuppressionFilter narrows and pages suppression listings. Zero values are ignored.
+type SuppressionFilter struct {
+	Email         string
+	Reason        string
+	IncludeLifted bool
+	Limit         int
+	Offset        int
+}
+
+// GetSuppressions lists suppressions, newest first; lifted ones only when IncludeLifted is set
+func (r *repository) GetSuppressions(ctx context.Context, filter SuppressionFilter) ([]Suppression, error) {
+	query := r.db.WithContext(ctx).Model(&Suppression{})
+	if filter.Email != "" {
+		query = query.Where("email = ?", strings.ToLower(strings.TrimSpace(filter.Email)))
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+	if !filter.IncludeLifted {
+		query = query.Where("lifted_at IS NULL")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > maxEmailLimit {
+		limit = maxEmailLimit
+	}
+
+	var suppressions []Suppression
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(filter.Offset).
+		Find(&suppressions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppressions: %w", err)
+	}
+	return suppressions, nil
+}
+
+// GetSuppressionByID retrieves a suppression by ID, lifted or not
+func (r *repository) GetSuppressionByID(ctx context.Context, id int64) (*Suppression, error) {
+	var suppression Suppression
+	err := r.db.WithContext(ctx).First(&suppression, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppression by id %d: %w", id, err)
+	}
+	return &suppression, nil
+}
+
+// GetActiveSuppression returns the active suppression for an address, or nil if it is not suppressed
+func (r *repository) GetActiveSuppression(ctx context.Context, address string) (*Suppression, error) {
+	var suppressions []Suppression
+	err := r.db.WithContext(ctx).
+		Where("email = ? AND lifted_at IS NULL", strings.ToLower(strings.TrimSpace(address))).
+		Limit(1).
+		Find(&suppressions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check suppression: %w", err)
+	}
+	if len(suppressions) == 0 {
+		return nil, nil
+	}
+	return &suppressions[0], nil
+}
+
+// CreateSuppression suppresses an address.
+// Returns ErrSuppressionExists if the address already has an active suppression.
+func (r *repository) CreateSuppression(ctx context.Context, suppression *Suppression) error {
+	created, err := createSuppression(r.db.WithContext(ctx), suppression)
+	if err != nil {
+		return fmt.Errorf("failed to create suppression: %w", err)
+	}
+	if !created {
+		return fmt.Errorf("failed to create suppression: %w", ErrSuppressionExists)
+	}
+	return nil
+}
+
+// createSuppression inserts a suppression using the given transaction, reporting false
+// if the address already has an active one
+func createSuppression(tx *gorm.DB, suppression *Suppression) (bool, error) {
+	suppression.Email = strings.ToLower(strings.TrimSpace(suppression.Email))
+	result := tx.Omit("ID", "CreatedAt", "LiftedAt", "LiftedBy").
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "email"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "lifted_at IS NULL"}}},
+			DoNothing:   true,
+		}).
+		Create(suppression)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// LiftSuppression lifts a suppression so the address can be emailed again.
+// Returns ErrSuppressionLifted if it has already been lifted.
+func (r *repository) LiftSuppression(ctx context.Context, id int64, liftedBy *string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Suppression{}).
+			Where("id = ? AND lifted_at IS NULL", id).
+			Updates(map[string]any{"lifted_at": tx.NowFunc(), "lifted_by": liftedBy})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+
+		var count int64
+		if err := tx.Model(&Suppression{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return ErrSuppressionLifted
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lift suppression: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTemplateExists is returned when a template already exists for an email type and locale
+var ErrTemplateExists = errors.New("template already exists for this email type and locale")
+
+// ErrTemplateInUse is returned when deleting a template whose versions emails were rendered from
+var ErrTemplateInUse = errors.New("template versions are referenced by emails")
+
+// EmailTemplate is a database-managed template for an email type and locale.
+// While ActiveVersionID is set, SendEmail renders it instead of the built-in template.
+// An empty Locale is the default used when no locale in the request's fallback chain matches.
+type EmailTemplate struct {
+	ID              int64                 `json:"id" gorm:"primaryKey"`
+	Type            string                `json:"type" gorm:"column:type"`
+	Locale          string                `json:"locale" gorm:"column:locale"`
+	ActiveVersionID *int64                `json:"activeVersionId,omitempty" gorm:"column:active_version_id"`
+	ActiveVersion   *EmailTemplateVersion `json:"activeVersion,omitempty" gorm:"foreignKey:ActiveVersionID"`
+	CreatedAt       time.Time             `json:"createdAt" gorm:"column:created_at"`
+	UpdatedAt       time.Time             `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+func (EmailTemplate) TableName() string {
+	return "messaging.email_templates"
+}
+
+// EmailTemplateVersion is an immutable revision of a template. Edits always create a new version.
+type EmailTemplateVersion struct {
+	ID         int64     `json:"id" gorm:"primaryKey"`
+	TemplateID int64     `json:"templateId" gorm:"column:template_id"`
+	Version    int       `json:"version" gorm:"column:version"`
+	Subject    string    `json:"subject" gorm:"column:subject"`
+	HTMLBody   string    `json:"htmlBody" gorm:"column:html_body"`
+	TextBody   *string   `json:"textBody,omitempty" gorm:"column:text_body"`
+	CreatedBy  *string   `json:"createdBy,omitempty" gorm:"column:created_by"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (EmailTemplateVersion) TableName() string {
+	return "messaging.email_template_versions"
+}
+
+// GetTemplates retrieves all templates with their active version
+func (r *repository) GetTemplates(ctx context.Context) ([]EmailTemplate, error) {
+	var templates []EmailTemplate
+	err := r.db.WithContext(ctx).
+		Preload("ActiveVersion").
+		Order("type ASC, locale ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplateByID retrieves a template with its active version
+func (r *repository) GetTemplateByID(ctx context.Context, id int64) (*EmailTemplate, error) {
+	var template EmailTemplate
+	err := r.db.WithContext(ctx).Preload("ActiveVersion").First(&template, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template by id %d: %w", id, err)
+	}
+	return &template, nil
+}
+
+// CreateTemplate creates a template with its first version, optionally activating it.
+// Returns ErrTemplateExists if the email type already has a template for the locale.
+func (r *repository) CreateTemplate(ctx context.Context, template *EmailTemplate, version *EmailTemplateVersion, activate bool) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&EmailTemplate{}).Where("type = ? AND locale = ?", template.Type, template.Locale).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTemplateExists
+		}
+
+		if err := tx.Omit("ID", "ActiveVersionID", "ActiveVersion", "CreatedAt", "UpdatedAt").Create(template).Error; err != nil {
+			return err
+		}
+		return addTemplateVersion(tx, template, version, activate)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	return nil
+}
+
+// CreateTemplateVersion appends the next version to a template, optionally activating it
+func (r *repository) CreateTemplateVersion(ctx context.Context, templateID int64, version *EmailTemplateVersion, activate bool) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the template so concurrent edits get distinct version numbers
+		var template EmailTemplate
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&template, templateID).Error; err != nil {
+			return err
+		}
+		return addTemplateVersion(tx, &template, version, activate)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create template version: %w", err)
+	}
+	return nil
+}
+
+// addTemplateVersion inserts the next version number for a locked or new template
+func addTemplateVersion(tx *gorm.DB, template *EmailTemplate, version *EmailTemplateVersion, activate bool) error {
+	var latest int
+	err := tx.Model(&EmailTemplateVersion{}).
+		Where("template_id = ?", template.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	version.TemplateID = template.ID
+	version.Version = latest + 1
+	if err := tx.Omit("ID", "CreatedAt").Create(version).Error; err != nil {
+		return err
+	}
+
+	if !activate {
+		return nil
+	}
+	template.ActiveVersionID = &version.ID
+	return tx.Model(&EmailTemplate{}).
+		Where("id = ?", template.ID).
+		Updates(map[string]interface{}{"active_version_id": version.ID, "updated_at": tx.NowFunc()}).Error
+}
+
+// GetTemplateVersions retrieves every version of a template, newest first
+func (r *repository) GetTemplateVersions(ctx context.Context, templateID int64) ([]EmailTemplateVersion, error) {
+	var versions []EmailTemplateVersion
+	err := r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// SetActiveTemplateVersion points a template at one of its versions, or at none when version is nil.
+// Returns gorm.ErrRecordNotFound if the template or version does not exist.
+func (r *repository) SetActiveTemplateVersion(ctx context.Context, templateID int64, version *int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var activeID *int64
+		if version != nil {
+			var v EmailTemplateVersion
+			err := tx.Where("template_id = ? AND version = ?", templateID, *version).First(&v).Error
+			if err != nil {
+				return err
+			}
+			activeID = &v.ID
+		}
+
+		result := tx.Model(&EmailTemplate{}).
+			Where("id = ?", templateID).
+			Updates(map[string]interface{}{"active_version_id": activeID, "updated_at": tx.NowFunc()})
+		return checkRowsAffected(result)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set active template version: %w", err)
+	}
+	return nil
+}
+
+// DeleteTemplate deletes a template and its versions; the type falls back to the built-in renderer.
+// Returns ErrTemplateInUse if any email was rendered from one of its versions.
+func (r *repository) DeleteTemplate(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var inUse int64
+		err := tx.Model(&Email{}).
+			Where("template_version_id IN (?)", tx.Model(&EmailTemplateVersion{}).Select("id").Where("template_id = ?", id)).
+			Limit(1).
+			Count(&inUse).Error
+		if err != nil {
+			return err
+		}
+		if inUse > 0 {
+			return ErrTemplateInUse
+		}
+		return checkRowsAffected(tx.Delete(&EmailTemplate{}, id))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// localizedVersion is a template version joined with its template's locale
+type localizedVersion struct {
+	EmailTemplateVersion
+	Locale string
+}
+
+// GetActiveTemplateVersion retrieves the active version for an email type, taking the first locale
+// in the fallback chain that has one. Returns nil if none is active.
+func (r *repository) GetActiveTemplateVersion(ctx context.Context, emailType string, locales []string) (*EmailTemplateVersion, error) {
+	if len(locales) == 0 {
+		return nil, nil
+	}
+
+	var candidates []localizedVersion
+	err := r.db.WithContext(ctx).
+		Model(&EmailTemplateVersion{}).
+		Select("email_template_versions.*, t.locale").
+		Joins("JOIN messaging.email_templates t ON t.active_version_id = email_template_versions.id").
+		Where("t.type = ? AND t.locale IN ?", emailType, locales).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active template for %s: %w", emailType, err)
+	}
+
+	for _, l := range locales {
+		for i := range candidates {
+			if candidates[i].Locale == l {
+				return &candidates[i].EmailTemplateVersion, nil
+			}
+		}
+	}
+	return nil, nil
+}
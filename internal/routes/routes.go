@@ -11,19 +11,20 @@ import (
 	"github.com/GunarsK-portfolio/messaging-api/docs"
 	"github.com/GunarsK-portfolio/messaging-api/internal/config"
 	"github.com/GunarsK-portfolio/messaging-api/internal/handlers"
+	"github.com/GunarsK-portfolio/messaging-api/internal/ratelimit"
 	"github.com/GunarsK-portfolio/portfolio-common/health"
 	"github.com/GunarsK-portfolio/portfolio-common/jwt"
 	"github.com/GunarsK-portfolio/portfolio-common/metrics"
 	common "github.com/GunarsK-portfolio/portfolio-common/middleware"
 )
 
-// Setup configures all routes for the service
-func Setup(router *gin.Engine, handler *handlers.Handler, cfg *config.Config, metricsCollector *metrics.Metrics, healthAgg *health.Aggregator) {
+// Setup configures all routes for the service. contactLimit, if not nil, rate-limits POST /contact per client IP.
+func Setup(router *gin.Engine, handler *handlers.Handler, cfg *config.Config, metricsCollector *metrics.Metrics, healthAgg *health.Aggregator, contactLimit *ratelimit.Limiter) {
 	// Security middleware with CORS validation
 	securityMiddleware := common.NewSecurityMiddleware(
 		cfg.AllowedOrigins,
 		"GET,POST,PUT,DELETE,OPTIONS",
-		"Content-Type,Authorization",
+		"Content-Type,Authorization,Idempotency-Key",
 		true,
 	)
 	router.Use(securityMiddleware.Apply())
@@ -38,11 +39,23 @@ func Setup(router *gin.Engine, handler *handlers.Handler, cfg *config.Config, me
 	v1 := router.Group("/api/v1")
 
 	// Public routes (no auth required)
+	// POST is rate-limited per client IP here and per sender address in the handler, once the body is parsed
 	public := v1.Group("/contact")
 	{
-		public.POST("", handler.CreateContactMessage)
+		if contactLimit != nil {
+			public.POST("", contactLimit.ByClientIP(), handler.CreateContactMessage)
+		} else {
+			public.POST("", handler.CreateContactMessage)
+		}
+		public.GET("/token", handler.GetFormToken)
 	}
 
+	// Signed attachment downloads (local storage backend; the link itself is the credential)
+	v1.GET("/attachments/files", handler.ServeAttachmentFile)
+
+	// Provider delivery events (authenticated by each provider's signature or credentials)
+	v1.POST("/webhooks/delivery/:provider", handler.HandleDeliveryWebhook)
+
 	// Protected routes (require JWT auth)
 	jwtService, err := jwt.NewValidatorOnly(cfg.JWTSecret)
 	if err != nil {
@@ -60,7 +73,49 @@ func Setup(router *gin.Engine, handler *handlers.Handler, cfg *config.Config, me
 		{
 			emails.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.SendEmail)
 			emails.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmails)
+			emails.GET("/search", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.SearchEmails)
+			emails.GET("/quarantine", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetQuarantinedEmails)
+			emails.POST("/preview", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.PreviewEmail)
 			emails.GET("/:id", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmail)
+			emails.GET("/:id/events", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmailEvents)
+			emails.POST("/:id/retry", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.RetryEmail)
+			emails.POST("/:id/cancel", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CancelEmail)
+			emails.POST("/:id/approve", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.ApproveEmail)
+			emails.POST("/:id/reject", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.RejectEmail)
+			emails.GET("/:id/attachments/:attachmentId/url", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetAttachmentURL)
+		}
+
+		// Email type catalog
+		protected.GET("/email-types", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmailTypes)
+
+		// Database-managed email templates
+		templates := protected.Group("/templates")
+		{
+			templates.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetTemplates)
+			templates.GET("/:id", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetTemplate)
+			templates.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateTemplate)
+			templates.POST("/:id/versions", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateTemplateVersion)
+			templates.PUT("/:id/active", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.ActivateTemplateVersion)
+			templates.DELETE("/:id/active", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.DeactivateTemplate)
+			templates.DELETE("/:id", common.RequirePermission(common.ResourceEmails, common.LevelDelete), handler.DeleteTemplate)
+		}
+
+		// Contact form blocklist (senders, domains, patterns and client IP ranges)
+		blocklist := protected.Group("/blocklist")
+		{
+			blocklist.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetBlocklist)
+			blocklist.GET("/:id", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetBlocklistEntry)
+			blocklist.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateBlocklistEntry)
+			blocklist.PUT("/:id", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.UpdateBlocklistEntry)
+			blocklist.DELETE("/:id", common.RequirePermission(common.ResourceEmails, common.LevelDelete), handler.DeleteBlocklistEntry)
+		}
+
+		// Recipient suppressions (bounces, complaints, unsubscribes and manual)
+		suppressions := protected.Group("/suppressions")
+		{
+			suppressions.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetSuppressions)
+			suppressions.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateSuppression)
+			suppressions.POST("/:id/lift", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.LiftSuppression)
 		}
 
 		// Legacy messages route (backward compat, same data)
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/GunarsK-portfolio/messaging-api/internal/handlers"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
 	common "github.com/GunarsK-portfolio/portfolio-common/middleware"
 	"github.com/GunarsK-portfolio/portfolio-common/models"
 	"github.com/gin-gonic/gin"
@@ -23,10 +25,47 @@ func init() {
 // =============================================================================
 
 type mockRepository struct {
-	createEmailFunc         func(ctx context.Context, email *models.Email) error
-	getEmailsFunc           func(ctx context.Context) ([]models.Email, error)
-	getEmailByIDFunc        func(ctx context.Context, id int64) (*models.Email, error)
+	createEmailFunc         func(ctx context.Context, email *repository.Email) error
+	createThrottledFunc     func(ctx context.Context, email *repository.Email, since time.Time) (bool, error)
+	createDedupedFunc       func(ctx context.Context, email *repository.Email, since time.Time) (bool, error)
+	createWithKeyFunc       func(ctx context.Context, email *repository.Email, key *repository.IdempotencyKey) error
+	getIdempotencyKeyFunc   func(ctx context.Context, caller, key string) (*repository.IdempotencyKey, error)
+	getEmailsFunc           func(ctx context.Context, filter repository.EmailFilter) (*repository.EmailPage, error)
+	getEmailByIDFunc        func(ctx context.Context, id int64) (*repository.Email, error)
+	searchEmailsFunc        func(ctx context.Context, filter repository.EmailSearchFilter) ([]repository.EmailSearchResult, error)
 	updateEmailStatusFunc   func(ctx context.Context, id int64, status string, lastError *string) error
+	retryEmailFunc          func(ctx context.Context, id int64) error
+	cancelEmailFunc         func(ctx context.Context, id int64) error
+	approveEmailFunc        func(ctx context.Context, id int64) error
+	rejectEmailFunc         func(ctx context.Context, id int64, block *repository.BlocklistEntry) error
+	getBlocklistFunc        func(ctx context.Context) ([]repository.BlocklistEntry, error)
+	getBlocklistEntryFunc   func(ctx context.Context, id int64) (*repository.BlocklistEntry, error)
+	createBlocklistFunc     func(ctx context.Context, entry *repository.BlocklistEntry) error
+	updateBlocklistFunc     func(ctx context.Context, entry *repository.BlocklistEntry) error
+	deleteBlocklistFunc     func(ctx context.Context, id int64) error
+	getSuppressionsFunc     func(ctx context.Context, filter repository.SuppressionFilter) ([]repository.Suppression, error)
+	getSuppressionFunc      func(ctx context.Context, id int64) (*repository.Suppression, error)
+	getActiveSuppressFunc   func(ctx context.Context, address string) (*repository.Suppression, error)
+	createSuppressionFunc   func(ctx context.Context, suppression *repository.Suppression) error
+	liftSuppressionFunc     func(ctx context.Context, id int64, liftedBy *string) error
+	claimStalePendingFunc   func(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error)
+	getAttachmentFunc       func(ctx context.Context, emailID, attachmentID int64) (*repository.Attachment, error)
+	getEmailEventsFunc      func(ctx context.Context, emailID int64) ([]repository.EmailEvent, error)
+	createEmailEventFunc    func(ctx context.Context, emailID int64, eventType string, details map[string]any) error
+	createDeliveryEventFunc func(ctx context.Context, event *repository.EmailEvent, status string, lastError *string) (bool, error)
+	claimDueOutboxFunc      func(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]repository.OutboxEntry, error)
+	markOutboxFunc          func(ctx context.Context, emailID int64) error
+	recordOutboxFailureFunc func(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	releaseScheduledFunc    func(ctx context.Context, now time.Time, limit int) ([]repository.Email, error)
+	countScheduledFunc      func(ctx context.Context) (int64, error)
+	getTemplatesFunc        func(ctx context.Context) ([]repository.EmailTemplate, error)
+	getTemplateByIDFunc     func(ctx context.Context, id int64) (*repository.EmailTemplate, error)
+	createTemplateFunc      func(ctx context.Context, template *repository.EmailTemplate, version *repository.EmailTemplateVersion, activate bool) error
+	createVersionFunc       func(ctx context.Context, templateID int64, version *repository.EmailTemplateVersion, activate bool) error
+	getVersionsFunc         func(ctx context.Context, templateID int64) ([]repository.EmailTemplateVersion, error)
+	setActiveVersionFunc    func(ctx context.Context, templateID int64, version *int) error
+	deleteTemplateFunc      func(ctx context.Context, id int64) error
+	getActiveTemplateFunc   func(ctx context.Context, emailType string, locales []string) (*repository.EmailTemplateVersion, error)
 	getAllRecipientsFunc    func(ctx context.Context) ([]models.Recipient, error)
 	getActiveRecipientsFunc func(ctx context.Context) ([]models.Recipient, error)
 	getRecipientByIDFunc    func(ctx context.Context, id int64) (*models.Recipient, error)
@@ -35,25 +74,64 @@ type mockRepository struct {
 	deleteRecipientFunc     func(ctx context.Context, id int64) error
 }
 
-func (m *mockRepository) CreateEmail(ctx context.Context, email *models.Email) error {
+func (m *mockRepository) CreateEmail(ctx context.Context, email *repository.Email) error {
 	if m.createEmailFunc != nil {
 		return m.createEmailFunc(ctx, email)
 	}
 	return nil
 }
 
-func (m *mockRepository) GetEmails(ctx context.Context) ([]models.Email, error) {
+func (m *mockRepository) CreateThrottledEmail(ctx context.Context, email *repository.Email, since time.Time) (bool, error) {
+	if m.createThrottledFunc != nil {
+		return m.createThrottledFunc(ctx, email, since)
+	}
+	return true, nil
+}
+
+func (m *mockRepository) CreateDedupedEmail(ctx context.Context, email *repository.Email, since time.Time) (bool, error) {
+	if m.createDedupedFunc != nil {
+		return m.createDedupedFunc(ctx, email, since)
+	}
+	return true, nil
+}
+
+func (m *mockRepository) CreateEmailWithIdempotencyKey(ctx context.Context, email *repository.Email, key *repository.IdempotencyKey) error {
+	if m.createWithKeyFunc != nil {
+		return m.createWithKeyFunc(ctx, email, key)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetIdempotencyKey(ctx context.Context, caller, key string) (*repository.IdempotencyKey, error) {
+	if m.getIdempotencyKeyFunc != nil {
+		return m.getIdempotencyKeyFunc(ctx, caller, key)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) DeleteExpiredIdempotencyKeys(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) GetEmails(ctx context.Context, filter repository.EmailFilter) (*repository.EmailPage, error) {
 	if m.getEmailsFunc != nil {
-		return m.getEmailsFunc(ctx)
+		return m.getEmailsFunc(ctx, filter)
 	}
-	return []models.Email{}, nil
+	return &repository.EmailPage{Emails: []repository.Email{}}, nil
 }
 
-func (m *mockRepository) GetEmailByID(ctx context.Context, id int64) (*models.Email, error) {
+func (m *mockRepository) GetEmailByID(ctx context.Context, id int64) (*repository.Email, error) {
 	if m.getEmailByIDFunc != nil {
 		return m.getEmailByIDFunc(ctx, id)
 	}
-	return &models.Email{ID: id}, nil
+	return &repository.Email{Email: models.Email{ID: id}}, nil
+}
+
+func (m *mockRepository) SearchEmails(ctx context.Context, filter repository.EmailSearchFilter) ([]repository.EmailSearchResult, error) {
+	if m.searchEmailsFunc != nil {
+		return m.searchEmailsFunc(ctx, filter)
+	}
+	return []repository.EmailSearchResult{}, nil
 }
 
 func (m *mockRepository) UpdateEmailStatus(ctx context.Context, id int64, status string, lastError *string) error {
@@ -63,6 +141,230 @@ func (m *mockRepository) UpdateEmailStatus(ctx context.Context, id int64, status
 	return nil
 }
 
+func (m *mockRepository) RetryEmail(ctx context.Context, id int64) error {
+	if m.retryEmailFunc != nil {
+		return m.retryEmailFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) CancelEmail(ctx context.Context, id int64) error {
+	if m.cancelEmailFunc != nil {
+		return m.cancelEmailFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) ApproveEmail(ctx context.Context, id int64) error {
+	if m.approveEmailFunc != nil {
+		return m.approveEmailFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) RejectEmail(ctx context.Context, id int64, block *repository.BlocklistEntry) error {
+	if m.rejectEmailFunc != nil {
+		return m.rejectEmailFunc(ctx, id, block)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetBlocklistEntries(ctx context.Context) ([]repository.BlocklistEntry, error) {
+	if m.getBlocklistFunc != nil {
+		return m.getBlocklistFunc(ctx)
+	}
+	return []repository.BlocklistEntry{}, nil
+}
+
+func (m *mockRepository) GetBlocklistEntryByID(ctx context.Context, id int64) (*repository.BlocklistEntry, error) {
+	if m.getBlocklistEntryFunc != nil {
+		return m.getBlocklistEntryFunc(ctx, id)
+	}
+	return &repository.BlocklistEntry{ID: id, Kind: repository.BlocklistKindDomain, Value: "example.com"}, nil
+}
+
+func (m *mockRepository) CreateBlocklistEntry(ctx context.Context, entry *repository.BlocklistEntry) error {
+	if m.createBlocklistFunc != nil {
+		return m.createBlocklistFunc(ctx, entry)
+	}
+	return nil
+}
+
+func (m *mockRepository) UpdateBlocklistEntry(ctx context.Context, entry *repository.BlocklistEntry) error {
+	if m.updateBlocklistFunc != nil {
+		return m.updateBlocklistFunc(ctx, entry)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteBlocklistEntry(ctx context.Context, id int64) error {
+	if m.deleteBlocklistFunc != nil {
+		return m.deleteBlocklistFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetSuppressions(ctx context.Context, filter repository.SuppressionFilter) ([]repository.Suppression, error) {
+	if m.getSuppressionsFunc != nil {
+		return m.getSuppressionsFunc(ctx, filter)
+	}
+	return []repository.Suppression{}, nil
+}
+
+func (m *mockRepository) GetSuppressionByID(ctx context.Context, id int64) (*repository.Suppression, error) {
+	if m.getSuppressionFunc != nil {
+		return m.getSuppressionFunc(ctx, id)
+	}
+	return &repository.Suppression{ID: id}, nil
+}
+
+func (m *mockRepository) GetActiveSuppression(ctx context.Context, address string) (*repository.Suppression, error) {
+	if m.getActiveSuppressFunc != nil {
+		return m.getActiveSuppressFunc(ctx, address)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CreateSuppression(ctx context.Context, suppression *repository.Suppression) error {
+	if m.createSuppressionFunc != nil {
+		return m.createSuppressionFunc(ctx, suppression)
+	}
+	return nil
+}
+
+func (m *mockRepository) LiftSuppression(ctx context.Context, id int64, liftedBy *string) error {
+	if m.liftSuppressionFunc != nil {
+		return m.liftSuppressionFunc(ctx, id, liftedBy)
+	}
+	return nil
+}
+
+func (m *mockRepository) ClaimStalePendingEmails(ctx context.Context, olderThan time.Time, limit int) ([]models.Email, error) {
+	if m.claimStalePendingFunc != nil {
+		return m.claimStalePendingFunc(ctx, olderThan, limit)
+	}
+	return []models.Email{}, nil
+}
+
+func (m *mockRepository) GetAttachment(ctx context.Context, emailID, attachmentID int64) (*repository.Attachment, error) {
+	if m.getAttachmentFunc != nil {
+		return m.getAttachmentFunc(ctx, emailID, attachmentID)
+	}
+	return &repository.Attachment{ID: attachmentID, EmailID: emailID}, nil
+}
+
+func (m *mockRepository) GetEmailEvents(ctx context.Context, emailID int64) ([]repository.EmailEvent, error) {
+	if m.getEmailEventsFunc != nil {
+		return m.getEmailEventsFunc(ctx, emailID)
+	}
+	return []repository.EmailEvent{}, nil
+}
+
+func (m *mockRepository) CreateEmailEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) error {
+	if m.createEmailEventFunc != nil {
+		return m.createEmailEventFunc(ctx, emailID, eventType, details)
+	}
+	return nil
+}
+
+func (m *mockRepository) CreateDeliveryEvent(ctx context.Context, event *repository.EmailEvent, status string, lastError *string) (bool, error) {
+	if m.createDeliveryEventFunc != nil {
+		return m.createDeliveryEventFunc(ctx, event, status, lastError)
+	}
+	return true, nil
+}
+
+func (m *mockRepository) ClaimDueOutboxEntries(ctx context.Context, createdBefore time.Time, limit int, claimUntil time.Time) ([]repository.OutboxEntry, error) {
+	if m.claimDueOutboxFunc != nil {
+		return m.claimDueOutboxFunc(ctx, createdBefore, limit, claimUntil)
+	}
+	return []repository.OutboxEntry{}, nil
+}
+
+func (m *mockRepository) MarkOutboxDispatched(ctx context.Context, emailID int64) error {
+	if m.markOutboxFunc != nil {
+		return m.markOutboxFunc(ctx, emailID)
+	}
+	return nil
+}
+
+func (m *mockRepository) RecordOutboxFailure(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	if m.recordOutboxFailureFunc != nil {
+		return m.recordOutboxFailureFunc(ctx, id, lastError, nextAttemptAt)
+	}
+	return nil
+}
+
+func (m *mockRepository) ReleaseDueScheduledEmails(ctx context.Context, now time.Time, limit int) ([]repository.Email, error) {
+	if m.releaseScheduledFunc != nil {
+		return m.releaseScheduledFunc(ctx, now, limit)
+	}
+	return []repository.Email{}, nil
+}
+
+func (m *mockRepository) CountScheduledEmails(ctx context.Context) (int64, error) {
+	if m.countScheduledFunc != nil {
+		return m.countScheduledFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) GetTemplates(ctx context.Context) ([]repository.EmailTemplate, error) {
+	if m.getTemplatesFunc != nil {
+		return m.getTemplatesFunc(ctx)
+	}
+	return []repository.EmailTemplate{}, nil
+}
+
+func (m *mockRepository) GetTemplateByID(ctx context.Context, id int64) (*repository.EmailTemplate, error) {
+	if m.getTemplateByIDFunc != nil {
+		return m.getTemplateByIDFunc(ctx, id)
+	}
+	return &repository.EmailTemplate{ID: id}, nil
+}
+
+func (m *mockRepository) CreateTemplate(ctx context.Context, template *repository.EmailTemplate, version *repository.EmailTemplateVersion, activate bool) error {
+	if m.createTemplateFunc != nil {
+		return m.createTemplateFunc(ctx, template, version, activate)
+	}
+	return nil
+}
+
+func (m *mockRepository) CreateTemplateVersion(ctx context.Context, templateID int64, version *repository.EmailTemplateVersion, activate bool) error {
+	if m.createVersionFunc != nil {
+		return m.createVersionFunc(ctx, templateID, version, activate)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetTemplateVersions(ctx context.Context, templateID int64) ([]repository.EmailTemplateVersion, error) {
+	if m.getVersionsFunc != nil {
+		return m.getVersionsFunc(ctx, templateID)
+	}
+	return []repository.EmailTemplateVersion{}, nil
+}
+
+func (m *mockRepository) SetActiveTemplateVersion(ctx context.Context, templateID int64, version *int) error {
+	if m.setActiveVersionFunc != nil {
+		return m.setActiveVersionFunc(ctx, templateID, version)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteTemplate(ctx context.Context, id int64) error {
+	if m.deleteTemplateFunc != nil {
+		return m.deleteTemplateFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetActiveTemplateVersion(ctx context.Context, emailType string, locales []string) (*repository.EmailTemplateVersion, error) {
+	if m.getActiveTemplateFunc != nil {
+		return m.getActiveTemplateFunc(ctx, emailType, locales)
+	}
+	return nil, nil
+}
+
 func (m *mockRepository) GetAllRecipients(ctx context.Context) ([]models.Recipient, error) {
 	if m.getAllRecipientsFunc != nil {
 		return m.getAllRecipientsFunc(ctx)
@@ -156,7 +458,31 @@ func setupRouterWithScopes(t *testing.T, scopes map[string]string) *gin.Engine {
 		{
 			emails.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.SendEmail)
 			emails.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmails)
+			emails.GET("/search", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.SearchEmails)
+			emails.GET("/quarantine", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetQuarantinedEmails)
+			emails.POST("/preview", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.PreviewEmail)
 			emails.GET("/:id", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmail)
+			emails.GET("/:id/events", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmailEvents)
+			emails.POST("/:id/retry", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.RetryEmail)
+			emails.POST("/:id/cancel", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CancelEmail)
+			emails.POST("/:id/approve", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.ApproveEmail)
+			emails.POST("/:id/reject", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.RejectEmail)
+			emails.GET("/:id/attachments/:attachmentId/url", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetAttachmentURL)
+		}
+
+		// Email type catalog
+		v1.GET("/email-types", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetEmailTypes)
+
+		// Database-managed email templates
+		templates := v1.Group("/templates")
+		{
+			templates.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetTemplates)
+			templates.GET("/:id", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetTemplate)
+			templates.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateTemplate)
+			templates.POST("/:id/versions", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateTemplateVersion)
+			templates.PUT("/:id/active", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.ActivateTemplateVersion)
+			templates.DELETE("/:id/active", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.DeactivateTemplate)
+			templates.DELETE("/:id", common.RequirePermission(common.ResourceEmails, common.LevelDelete), handler.DeleteTemplate)
 		}
 
 		// Legacy messages route
@@ -166,6 +492,24 @@ func setupRouterWithScopes(t *testing.T, scopes map[string]string) *gin.Engine {
 			messages.GET("/:id", common.RequirePermission(common.ResourceMessages, common.LevelRead), handler.GetEmail)
 		}
 
+		// Contact form blocklist (senders, domains, patterns and client IP ranges)
+		blocklist := v1.Group("/blocklist")
+		{
+			blocklist.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetBlocklist)
+			blocklist.GET("/:id", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetBlocklistEntry)
+			blocklist.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateBlocklistEntry)
+			blocklist.PUT("/:id", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.UpdateBlocklistEntry)
+			blocklist.DELETE("/:id", common.RequirePermission(common.ResourceEmails, common.LevelDelete), handler.DeleteBlocklistEntry)
+		}
+
+		// Recipient suppressions (bounces, complaints, unsubscribes and manual)
+		suppressions := v1.Group("/suppressions")
+		{
+			suppressions.GET("", common.RequirePermission(common.ResourceEmails, common.LevelRead), handler.GetSuppressions)
+			suppressions.POST("", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.CreateSuppression)
+			suppressions.POST("/:id/lift", common.RequirePermission(common.ResourceEmails, common.LevelEdit), handler.LiftSuppression)
+		}
+
 		// Recipients (full CRUD)
 		recipients := v1.Group("/recipients")
 		{
@@ -182,7 +526,7 @@ func setupRouterWithScopes(t *testing.T, scopes map[string]string) *gin.Engine {
 
 func performRequest(t *testing.T, router *gin.Engine, method, path string) *httptest.ResponseRecorder {
 	t.Helper()
-	req, err := http.NewRequest(method, path, nil)
+	req, err := http.NewRequest(method, path, http.NoBody)
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
@@ -205,7 +549,32 @@ type routePermission struct {
 var emailsRoutes = []routePermission{
 	{"GET", "/api/v1/emails", common.ResourceEmails, common.LevelRead},
 	{"GET", "/api/v1/emails/1", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/emails/1/events", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/emails/search?q=freelance", common.ResourceEmails, common.LevelRead},
 	{"POST", "/api/v1/emails", common.ResourceEmails, common.LevelEdit},
+	{"POST", "/api/v1/emails/preview", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/email-types", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/templates", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/templates/1", common.ResourceEmails, common.LevelRead},
+	{"POST", "/api/v1/templates", common.ResourceEmails, common.LevelEdit},
+	{"POST", "/api/v1/templates/1/versions", common.ResourceEmails, common.LevelEdit},
+	{"PUT", "/api/v1/templates/1/active", common.ResourceEmails, common.LevelEdit},
+	{"DELETE", "/api/v1/templates/1/active", common.ResourceEmails, common.LevelEdit},
+	{"DELETE", "/api/v1/templates/1", common.ResourceEmails, common.LevelDelete},
+	{"POST", "/api/v1/emails/1/retry", common.ResourceEmails, common.LevelEdit},
+	{"POST", "/api/v1/emails/1/cancel", common.ResourceEmails, common.LevelEdit},
+	{"GET", "/api/v1/emails/quarantine", common.ResourceEmails, common.LevelRead},
+	{"POST", "/api/v1/emails/1/approve", common.ResourceEmails, common.LevelEdit},
+	{"POST", "/api/v1/emails/1/reject", common.ResourceEmails, common.LevelEdit},
+	{"GET", "/api/v1/emails/1/attachments/1/url", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/blocklist", common.ResourceEmails, common.LevelRead},
+	{"GET", "/api/v1/blocklist/1", common.ResourceEmails, common.LevelRead},
+	{"POST", "/api/v1/blocklist", common.ResourceEmails, common.LevelEdit},
+	{"PUT", "/api/v1/blocklist/1", common.ResourceEmails, common.LevelEdit},
+	{"DELETE", "/api/v1/blocklist/1", common.ResourceEmails, common.LevelDelete},
+	{"GET", "/api/v1/suppressions", common.ResourceEmails, common.LevelRead},
+	{"POST", "/api/v1/suppressions", common.ResourceEmails, common.LevelEdit},
+	{"POST", "/api/v1/suppressions/1/lift", common.ResourceEmails, common.LevelEdit},
 }
 
 var messagesRoutes = []routePermission{
@@ -0,0 +1,125 @@
+// Package scheduler releases scheduled emails for delivery once their send_at time is due.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+	"github.com/GunarsK-portfolio/portfolio-common/queue"
+)
+
+// Store is the subset of the repository used by the scheduler
+type Store interface {
+	ReleaseDueScheduledEmails(ctx context.Context, now time.Time, limit int) ([]repository.Email, error)
+	CountScheduledEmails(ctx context.Context) (int64, error)
+	MarkOutboxDispatched(ctx context.Context, emailID int64) error
+	CreateEmailEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) error
+}
+
+// Scheduler periodically moves due scheduled emails to pending and publishes their EmailEvents
+type Scheduler struct {
+	store     Store
+	publisher queue.Publisher
+	cfg       config.SchedulerConfig
+	metrics   *metrics.Metrics
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// New creates a new Scheduler instance
+func New(store Store, publisher queue.Publisher, cfg config.SchedulerConfig, m *metrics.Metrics, logger *slog.Logger) *Scheduler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+		metrics:   m,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run releases due emails every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.Info("Scheduler started", "interval", s.cfg.Interval.String())
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Scheduler stopped")
+			return
+		case <-ticker.C:
+			s.ReleaseOnce(ctx)
+		}
+	}
+}
+
+// ReleaseOnce releases one batch of due scheduled emails and refreshes the backlog gauge.
+// Returns the number of emails released; those that fail to publish are left to the outbox relay.
+func (s *Scheduler) ReleaseOnce(ctx context.Context) int {
+	now := s.now()
+	emails, err := s.store.ReleaseDueScheduledEmails(ctx, now, s.cfg.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to release scheduled emails", "error", err)
+		return 0
+	}
+
+	for _, email := range emails {
+		if s.metrics != nil {
+			s.metrics.ScheduledReleased.Inc()
+			if email.SendAt != nil {
+				s.metrics.ScheduledLateness.Observe(max(now.Sub(*email.SendAt), 0).Seconds())
+			}
+		}
+		if ctx.Err() != nil {
+			continue
+		}
+
+		if err := s.publisher.Publish(ctx, models.EmailEvent{EmailID: email.ID}); err != nil {
+			s.logger.Warn("Failed to publish released email, outbox relay will retry", "error", err, "emailId", email.ID)
+			s.recordEvent(ctx, email.ID, repository.EmailEventPublishFailed, map[string]any{"source": eventSource, "error": err.Error()})
+			continue
+		}
+		if err := s.store.MarkOutboxDispatched(ctx, email.ID); err != nil {
+			s.logger.Error("Failed to mark outbox entry dispatched", "error", err, "emailId", email.ID)
+		}
+	}
+
+	if len(emails) > 0 {
+		s.logger.Info("Scheduled emails released", "count", len(emails))
+	}
+	s.updateBacklog(ctx)
+	return len(emails)
+}
+
+// updateBacklog sets the scheduled backlog gauge
+func (s *Scheduler) updateBacklog(ctx context.Context) {
+	if s.metrics == nil {
+		return
+	}
+	count, err := s.store.CountScheduledEmails(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to count scheduled emails", "error", err)
+		return
+	}
+	s.metrics.ScheduledBacklog.Set(float64(count))
+}
+
+// eventSource marks timeline events written by the scheduler
+const eventSource = "scheduler"
+
+// recordEvent appends to an email's timeline; failures are only logged
+func (s *Scheduler) recordEvent(ctx context.Context, emailID int64, eventType string, details map[string]any) {
+	if err := s.store.CreateEmailEvent(ctx, emailID, eventType, details); err != nil {
+		s.logger.Warn("Failed to record email event", "error", err, "emailId", emailID, "type", eventType)
+	}
+}
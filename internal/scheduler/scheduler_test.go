@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/GunarsK-portfolio/messaging-api/internal/config"
+	"github.com/GunarsK-portfolio/messaging-api/internal/metrics"
+	"github.com/GunarsK-portfolio/messaging-api/internal/repository"
+	"github.com/GunarsK-portfolio/portfolio-common/models"
+)
+
+// =============================================================================
+// Mocks
+// =============================================================================
+
+type mockStore struct {
+	due        []repository.Email
+	releaseErr error
+	releasedAt time.Time
+	backlog    int64
+	dispatched []int64
+	events     []string
+}
+
+func (m *mockStore) ReleaseDueScheduledEmails(_ context.Context, now time.Time, _ int) ([]repository.Email, error) {
+	m.releasedAt = now
+	return m.due, m.releaseErr
+}
+
+func (m *mockStore) CountScheduledEmails(_ context.Context) (int64, error) {
+	return m.backlog, nil
+}
+
+func (m *mockStore) MarkOutboxDispatched(_ context.Context, emailID int64) error {
+	m.dispatched = append(m.dispatched, emailID)
+	return nil
+}
+
+func (m *mockStore) CreateEmailEvent(_ context.Context, emailID int64, eventType string, _ map[string]any) error {
+	m.events = append(m.events, fmt.Sprintf("%d:%s", emailID, eventType))
+	return nil
+}
+
+type mockPublisher struct {
+	publishFunc func(ctx context.Context, message interface{}) error
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, message interface{}) error {
+	if m.publishFunc != nil {
+		return m.publishFunc(ctx, message)
+	}
+	return nil
+}
+
+func (m *mockPublisher) PublishToRetry(_ context.Context, _ int, _ []byte, _ string, _ amqp.Table) error {
+	return nil
+}
+
+func (m *mockPublisher) PublishToDLQ(_ context.Context, _ []byte, _ string) error {
+	return nil
+}
+
+func (m *mockPublisher) MaxRetries() int {
+	return 0
+}
+
+func (m *mockPublisher) Close() error {
+	return nil
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+var fixedNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestMetrics() *metrics.Metrics {
+	return &metrics.Metrics{
+		ScheduledBacklog:  prometheus.NewGauge(prometheus.GaugeOpts{Name: "backlog"}),
+		ScheduledReleased: prometheus.NewCounter(prometheus.CounterOpts{Name: "released"}),
+		ScheduledLateness: prometheus.NewHistogram(prometheus.HistogramOpts{Name: "lateness"}),
+	}
+}
+
+func newTestScheduler(store Store, pub *mockPublisher, m *metrics.Metrics) *Scheduler {
+	cfg := config.SchedulerConfig{
+		Enabled:   true,
+		Interval:  15 * time.Second,
+		BatchSize: 50,
+		MaxAhead:  24 * time.Hour,
+	}
+	s := New(store, pub, cfg, m, nil)
+	s.now = func() time.Time { return fixedNow }
+	return s
+}
+
+func scheduledEmail(id int64, sendAt time.Time) repository.Email {
+	return repository.Email{Email: models.Email{ID: id}, SendAt: &sendAt}
+}
+
+func metricValue(t *testing.T, c prometheus.Metric) *dto.Metric {
+	t.Helper()
+	var metric dto.Metric
+	if err := c.Write(&metric); err != nil {
+		t.Fatalf("failed to read metric: %v", err)
+	}
+	return &metric
+}
+
+// =============================================================================
+// ReleaseOnce Tests
+// =============================================================================
+
+func TestReleaseOnce_PublishesDueEmails(t *testing.T) {
+	store := &mockStore{
+		due: []repository.Email{
+			scheduledEmail(1, fixedNow.Add(-30*time.Second)),
+			scheduledEmail(2, fixedNow.Add(-90*time.Second)),
+		},
+		backlog: 5,
+	}
+	var published []int64
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		published = append(published, msg.(models.EmailEvent).EmailID)
+		return nil
+	}}
+	m := newTestMetrics()
+
+	released := newTestScheduler(store, pub, m).ReleaseOnce(context.Background())
+
+	if released != 2 {
+		t.Errorf("released = %d, want 2", released)
+	}
+	if !store.releasedAt.Equal(fixedNow) {
+		t.Errorf("releasedAt = %v, want %v", store.releasedAt, fixedNow)
+	}
+	if !slices.Equal(published, []int64{1, 2}) || !slices.Equal(store.dispatched, []int64{1, 2}) {
+		t.Errorf("published = %v, dispatched = %v, want [1 2]", published, store.dispatched)
+	}
+	if got := metricValue(t, m.ScheduledReleased).GetCounter().GetValue(); got != 2 {
+		t.Errorf("released counter = %v, want 2", got)
+	}
+	lateness := metricValue(t, m.ScheduledLateness).GetHistogram()
+	if lateness.GetSampleCount() != 2 || lateness.GetSampleSum() != 120 {
+		t.Errorf("lateness count=%d sum=%v, want 2/120", lateness.GetSampleCount(), lateness.GetSampleSum())
+	}
+	if got := metricValue(t, m.ScheduledBacklog).GetGauge().GetValue(); got != 5 {
+		t.Errorf("backlog gauge = %v, want 5", got)
+	}
+}
+
+func TestReleaseOnce_PublishFailureLeftToRelay(t *testing.T) {
+	store := &mockStore{due: []repository.Email{scheduledEmail(1, fixedNow), scheduledEmail(2, fixedNow)}}
+	pub := &mockPublisher{publishFunc: func(_ context.Context, msg interface{}) error {
+		if msg.(models.EmailEvent).EmailID == 2 {
+			return errors.New("mq down")
+		}
+		return nil
+	}}
+
+	released := newTestScheduler(store, pub, nil).ReleaseOnce(context.Background())
+
+	if released != 2 {
+		t.Errorf("released = %d, want 2", released)
+	}
+	if !slices.Equal(store.dispatched, []int64{1}) {
+		t.Errorf("dispatched = %v, want [1]", store.dispatched)
+	}
+	if want := []string{"2:publish_failed"}; !slices.Equal(store.events, want) {
+		t.Errorf("events = %v, want %v", store.events, want)
+	}
+}
+
+func TestReleaseOnce_StoreError(t *testing.T) {
+	store := &mockStore{releaseErr: errors.New("database error"), backlog: 3}
+	m := newTestMetrics()
+
+	released := newTestScheduler(store, &mockPublisher{}, m).ReleaseOnce(context.Background())
+
+	if released != 0 {
+		t.Errorf("released = %d, want 0", released)
+	}
+	if got := metricValue(t, m.ScheduledBacklog).GetGauge().GetValue(); got != 0 {
+		t.Errorf("backlog gauge = %v, want it untouched", got)
+	}
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	s := newTestScheduler(&mockStore{}, &mockPublisher{}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,229 @@
+package spam
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// linkPattern matches http(s) and www. links and captures their host
+var linkPattern = regexp.MustCompile(`(?i)(?:\bhttps?://|\bwww\.)([a-z0-9](?:[a-z0-9.-]*[a-z0-9])?)`)
+
+// links returns the lower-cased hosts of all links in text
+func links(text string) []string {
+	matches := linkPattern.FindAllStringSubmatch(text, -1)
+	hosts := make([]string, 0, len(matches))
+	for _, m := range matches {
+		hosts = append(hosts, strings.TrimPrefix(strings.ToLower(m[1]), "www."))
+	}
+	return hosts
+}
+
+// emailDomain returns the lower-cased domain of an address ("" if it has none)
+func emailDomain(address string) string {
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(address[at+1:]))
+}
+
+// domainSet matches a domain and all of its subdomains
+type domainSet map[string]struct{}
+
+func newDomainSet(lists ...[]string) domainSet {
+	set := domainSet{}
+	for _, list := range lists {
+		for _, d := range list {
+			if d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), "."); d != "" {
+				set[d] = struct{}{}
+			}
+		}
+	}
+	return set
+}
+
+// match returns the listed domain that host equals or is a subdomain of ("" if none)
+func (s domainSet) match(host string) string {
+	for host != "" {
+		if _, ok := s[host]; ok {
+			return host
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return ""
+}
+
+// LinkCount scores submissions that carry more links than a visitor usually needs
+type LinkCount struct {
+	// Allowed links are free; each link beyond them adds PerLink
+	Allowed int
+	PerLink int
+}
+
+// Name implements Scorer
+func (LinkCount) Name() string { return "links" }
+
+// Score implements Scorer
+func (l LinkCount) Score(_ context.Context, s Submission) Result {
+	n := len(links(s.Subject)) + len(links(s.Message))
+	if n <= l.Allowed {
+		return Result{}
+	}
+	return Result{Score: (n - l.Allowed) * l.PerLink, Reason: fmt.Sprintf("%d links", n)}
+}
+
+// Blocklist scores senders and links on blocked domains and text containing blocked keywords
+type Blocklist struct {
+	domains  domainSet
+	keywords []string
+
+	domainScore  int
+	keywordScore int
+}
+
+// NewBlocklist creates a blocklist scorer. A blocked sender or link domain adds domainScore once;
+// each distinct blocked keyword (matched case-insensitively) adds keywordScore.
+func NewBlocklist(domains, keywords []string, domainScore, keywordScore int) *Blocklist {
+	b := &Blocklist{
+		domains:      newDomainSet(domains),
+		domainScore:  domainScore,
+		keywordScore: keywordScore,
+	}
+	for _, k := range keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			b.keywords = append(b.keywords, k)
+		}
+	}
+	return b
+}
+
+// Name implements Scorer
+func (*Blocklist) Name() string { return "blocklist" }
+
+// Score implements Scorer
+func (b *Blocklist) Score(_ context.Context, s Submission) Result {
+	var r Result
+	var reasons []string
+
+	hosts := append([]string{emailDomain(s.Email)}, links(s.Subject+" "+s.Message)...)
+	for _, host := range hosts {
+		if d := b.domains.match(host); d != "" {
+			r.Score += b.domainScore
+			reasons = append(reasons, "blocked domain "+d)
+			break
+		}
+	}
+
+	text := strings.ToLower(s.Name + " " + s.Subject + " " + s.Message)
+	for _, k := range b.keywords {
+		if strings.Contains(text, k) {
+			r.Score += b.keywordScore
+			reasons = append(reasons, fmt.Sprintf("blocked keyword %q", k))
+		}
+	}
+
+	r.Reason = strings.Join(reasons, ", ")
+	return r
+}
+
+// defaultDisposableDomains are common throwaway mailbox providers
+var defaultDisposableDomains = []string{
+	"10minutemail.com",
+	"discard.email",
+	"dispostable.com",
+	"fakeinbox.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"maildrop.cc",
+	"mailinator.com",
+	"mailnesia.com",
+	"mintemail.com",
+	"mohmal.com",
+	"sharklasers.com",
+	"temp-mail.org",
+	"tempmail.com",
+	"throwawaymail.com",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// Disposable scores senders using a throwaway mailbox provider
+type Disposable struct {
+	domains domainSet
+	score   int
+}
+
+// NewDisposable creates a disposable-domain scorer over the built-in list plus extra domains
+func NewDisposable(extra []string, score int) *Disposable {
+	return &Disposable{domains: newDomainSet(defaultDisposableDomains, extra), score: score}
+}
+
+// Name implements Scorer
+func (*Disposable) Name() string { return "disposable" }
+
+// Score implements Scorer
+func (d *Disposable) Score(_ context.Context, s Submission) Result {
+	domain := d.domains.match(emailDomain(s.Email))
+	if domain == "" {
+		return Result{}
+	}
+	return Result{Score: d.score, Reason: "disposable address at " + domain}
+}
+
+// Heuristics scores shapes of message typical of form spam: a message no longer than its subject,
+// a shouting message, and links or addresses in the name field
+type Heuristics struct{}
+
+// Name implements Scorer
+func (Heuristics) Name() string { return "heuristics" }
+
+// Score implements Scorer
+func (Heuristics) Score(_ context.Context, s Submission) Result {
+	var r Result
+	var reasons []string
+
+	subject := strings.TrimSpace(s.Subject)
+	message := strings.TrimSpace(s.Message)
+	switch {
+	case strings.EqualFold(subject, message):
+		r.Score += 2
+		reasons = append(reasons, "message repeats subject")
+	case len(message) < len(subject):
+		r.Score++
+		reasons = append(reasons, "message shorter than subject")
+	}
+
+	if len(links(s.Name)) > 0 || strings.Contains(s.Name, "@") {
+		r.Score += 3
+		reasons = append(reasons, "link or address in name")
+	}
+
+	if shouting(message) {
+		r.Score += 2
+		reasons = append(reasons, "message in capitals")
+	}
+
+	r.Reason = strings.Join(reasons, ", ")
+	return r
+}
+
+// shouting reports whether text has at least 20 letters and over 70% of them upper case
+func shouting(text string) bool {
+	letters, upper := 0, 0
+	for _, c := range text {
+		if unicode.IsLetter(c) {
+			letters++
+			if unicode.IsUpper(c) {
+				upper++
+			}
+		}
+	}
+	return letters >= 20 && upper*10 > letters*7
+}
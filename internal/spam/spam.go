@@ -0,0 +1,111 @@
+// Package spam scores contact form submissions so obvious spam can be dropped and
+// borderline submissions held for review instead of being delivered.
+package spam
+
+import (
+	"context"
+	"strings"
+)
+
+// Submission is the part of a contact form submission the scorers look at
+type Submission struct {
+	Name     string
+	Email    string
+	Subject  string
+	Message  string
+	Honeypot string
+	// FormToken is the timing token issued when the form was loaded ("" if the client sent none)
+	FormToken string
+}
+
+// Result is one scorer's opinion of a submission
+type Result struct {
+	// Score adds to the submission's total; 0 means the scorer found nothing
+	Score int
+	// Reason explains a non-zero score to the admin reviewing the submission
+	Reason string
+	// Flag holds the submission for review even if the total stays below the quarantine score
+	Flag bool
+}
+
+// Scorer rates one aspect of a submission
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, s Submission) Result
+}
+
+// Action is what to do with a scored submission
+type Action string
+
+const (
+	// ActionAccept stores and delivers the submission
+	ActionAccept Action = "accept"
+	// ActionQuarantine stores the submission for review without delivering it
+	ActionQuarantine Action = "quarantine"
+	// ActionReject silently drops the submission
+	ActionReject Action = "reject"
+)
+
+// Verdict is the aggregated outcome of all scorers
+type Verdict struct {
+	Score   int
+	Reasons []string
+	Action  Action
+}
+
+// Pipeline runs scorers and sums their scores into a verdict
+type Pipeline struct {
+	scorers      []Scorer
+	quarantineAt int
+	rejectAt     int
+}
+
+// NewPipeline creates a pipeline that quarantines submissions scoring at least quarantineAt
+// and rejects those scoring at least rejectAt
+func NewPipeline(quarantineAt, rejectAt int, scorers ...Scorer) *Pipeline {
+	return &Pipeline{
+		scorers:      scorers,
+		quarantineAt: quarantineAt,
+		rejectAt:     rejectAt,
+	}
+}
+
+// Evaluate scores a submission with every scorer and decides what to do with it
+func (p *Pipeline) Evaluate(ctx context.Context, s Submission) Verdict {
+	var v Verdict
+	flagged := false
+	for _, scorer := range p.scorers {
+		r := scorer.Score(ctx, s)
+		if r.Score == 0 && !r.Flag {
+			continue
+		}
+		v.Score += r.Score
+		flagged = flagged || r.Flag
+		v.Reasons = append(v.Reasons, scorer.Name()+": "+r.Reason)
+	}
+
+	switch {
+	case v.Score >= p.rejectAt:
+		v.Action = ActionReject
+	case flagged || v.Score >= p.quarantineAt:
+		v.Action = ActionQuarantine
+	default:
+		v.Action = ActionAccept
+	}
+	return v
+}
+
+// Honeypot flags submissions that filled in the hidden honeypot field.
+// Browser autofill trips it too, so flagged submissions are held for review rather than dropped.
+type Honeypot struct{}
+
+// Name implements Scorer
+func (Honeypot) Name() string { return "honeypot" }
+
+// Score implements Scorer
+func (Honeypot) Score(_ context.Context, s Submission) Result {
+	if strings.TrimSpace(s.Honeypot) == "" {
+		return Result{}
+	}
+	return Result{Flag: true, Reason: "hidden field filled in"}
+}
@@ -0,0 +1,168 @@
+package spam
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ham() Submission {
+	return Submission{
+		Name:    "Jane Doe",
+		Email:   "jane@example.com",
+		Subject: "Project enquiry",
+		Message: "Hi, I liked your portfolio and would like to talk about a project.",
+	}
+}
+
+type fixedScorer struct {
+	name string
+	r    Result
+}
+
+func (f fixedScorer) Name() string                             { return f.name }
+func (f fixedScorer) Score(context.Context, Submission) Result { return f.r }
+
+func TestPipeline_Evaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		scorers     []Scorer
+		wantScore   int
+		wantAction  Action
+		wantReasons []string
+	}{
+		{"no scorers", nil, 0, ActionAccept, nil},
+		{"below threshold", []Scorer{fixedScorer{"a", Result{Score: 2, Reason: "x"}}}, 2, ActionAccept, []string{"a: x"}},
+		{
+			"quarantine",
+			[]Scorer{fixedScorer{"a", Result{Score: 3, Reason: "x"}}, fixedScorer{"b", Result{}}, fixedScorer{"c", Result{Score: 2, Reason: "y"}}},
+			5, ActionQuarantine, []string{"a: x", "c: y"},
+		},
+		{"reject", []Scorer{fixedScorer{"a", Result{Score: 10, Reason: "x"}}}, 10, ActionReject, []string{"a: x"}},
+		{"flag", []Scorer{fixedScorer{"a", Result{Flag: true, Reason: "x"}}}, 0, ActionQuarantine, []string{"a: x"}},
+		{"flag over reject score", []Scorer{fixedScorer{"a", Result{Flag: true, Reason: "x"}}, fixedScorer{"b", Result{Score: 12, Reason: "y"}}}, 12, ActionReject, []string{"a: x", "b: y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewPipeline(5, 10, tt.scorers...).Evaluate(context.Background(), ham())
+			if v.Score != tt.wantScore || v.Action != tt.wantAction {
+				t.Errorf("Evaluate() = %d/%s, want %d/%s", v.Score, v.Action, tt.wantScore, tt.wantAction)
+			}
+			if !reflect.DeepEqual(v.Reasons, tt.wantReasons) {
+				t.Errorf("reasons = %v, want %v", v.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestHoneypot(t *testing.T) {
+	s := ham()
+	if r := (Honeypot{}).Score(context.Background(), s); r.Flag {
+		t.Error("expected empty honeypot to pass")
+	}
+	s.Honeypot = "http://spam.example"
+	if r := (Honeypot{}).Score(context.Background(), s); !r.Flag {
+		t.Error("expected filled honeypot to be flagged")
+	}
+}
+
+func TestLinkCount(t *testing.T) {
+	scorer := LinkCount{Allowed: 1, PerLink: 2}
+	tests := []struct {
+		message string
+		want    int
+	}{
+		{"no links here", 0},
+		{"see https://example.com", 0},
+		{"http://a.example and www.b.example", 2},
+		{"HTTPS://a.example http://b.example https://c.example/x?y", 4},
+	}
+
+	for _, tt := range tests {
+		s := ham()
+		s.Message = tt.message
+		if got := scorer.Score(context.Background(), s).Score; got != tt.want {
+			t.Errorf("Score(%q) = %d, want %d", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestBlocklist(t *testing.T) {
+	scorer := NewBlocklist([]string{"spam.example", " Casino.Example "}, []string{"SEO services", "crypto"}, 5, 3)
+	tests := []struct {
+		name   string
+		mutate func(*Submission)
+		want   int
+	}{
+		{"clean", func(*Submission) {}, 0},
+		{"sender domain", func(s *Submission) { s.Email = "bot@spam.example" }, 5},
+		{"sender subdomain", func(s *Submission) { s.Email = "bot@mail.spam.example" }, 5},
+		{"lookalike domain", func(s *Submission) { s.Email = "bot@notspam.example" }, 0},
+		{"link domain", func(s *Submission) { s.Message = "visit https://www.casino.example/win" }, 5},
+		{"sender and link count once", func(s *Submission) {
+			s.Email = "bot@spam.example"
+			s.Message = "http://spam.example"
+		}, 5},
+		{"keywords", func(s *Submission) {
+			s.Subject = "Cheap seo Services"
+			s.Message = "and CRYPTO"
+		}, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ham()
+			tt.mutate(&s)
+			r := scorer.Score(context.Background(), s)
+			if r.Score != tt.want {
+				t.Errorf("Score() = %d (%s), want %d", r.Score, r.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisposable(t *testing.T) {
+	scorer := NewDisposable([]string{"burner.example"}, 4)
+	for email, want := range map[string]int{
+		"jane@example.com":       0,
+		"bot@mailinator.com":     4,
+		"bot@EU.Mailinator.com":  4,
+		"bot@burner.example":     4,
+		"no-at-sign.example.com": 0,
+	} {
+		s := ham()
+		s.Email = email
+		if got := scorer.Score(context.Background(), s).Score; got != want {
+			t.Errorf("Score(%q) = %d, want %d", email, got, want)
+		}
+	}
+}
+
+func TestHeuristics(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Submission)
+		want   int
+		reason string
+	}{
+		{"clean", func(*Submission) {}, 0, ""},
+		{"repeats subject", func(s *Submission) { s.Message = " project ENQUIRY " }, 2, "message repeats subject"},
+		{"shorter than subject", func(s *Submission) { s.Message = "Hi" }, 1, "message shorter than subject"},
+		{"link in name", func(s *Submission) { s.Name = "Buy at www.shop.example" }, 3, "link or address in name"},
+		{"address in name", func(s *Submission) { s.Name = "bot@example.com" }, 3, "link or address in name"},
+		{"shouting", func(s *Submission) { s.Message = strings.ToUpper(s.Message) }, 2, "message in capitals"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ham()
+			tt.mutate(&s)
+			r := (Heuristics{}).Score(context.Background(), s)
+			if r.Score != tt.want || r.Reason != tt.reason {
+				t.Errorf("Score() = %d %q, want %d %q", r.Score, r.Reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package spam
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimingToken issues signed tokens recording when the contact form was loaded and scores
+// submissions that arrive without one, with a forged one, or faster than a person could type
+type TimingToken struct {
+	secret   []byte
+	minDelay time.Duration
+	maxAge   time.Duration
+
+	// missingScore applies to absent or expired tokens, invalidScore to forged or too-fast ones
+	missingScore int
+	invalidScore int
+
+	now func() time.Time
+}
+
+// NewTimingToken creates a timing token scorer signing with secret. Submissions sooner than
+// minDelay after the token was issued, or later than maxAge, are scored.
+func NewTimingToken(secret []byte, minDelay, maxAge time.Duration, missingScore, invalidScore int) *TimingToken {
+	return &TimingToken{
+		secret:       secret,
+		minDelay:     minDelay,
+		maxAge:       maxAge,
+		missingScore: missingScore,
+		invalidScore: invalidScore,
+		now:          time.Now,
+	}
+}
+
+// Issue returns a new token and the time it stops being accepted
+func (t *TimingToken) Issue() (string, time.Time) {
+	issued := t.now()
+	payload := strconv.FormatInt(issued.Unix(), 10)
+	return payload + "." + t.sign(payload), issued.Add(t.maxAge)
+}
+
+func (t *TimingToken) sign(payload string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Name implements Scorer
+func (*TimingToken) Name() string { return "timing" }
+
+// Score implements Scorer
+func (t *TimingToken) Score(_ context.Context, s Submission) Result {
+	if s.FormToken == "" {
+		return Result{Score: t.missingScore, Reason: "no form token"}
+	}
+
+	payload, sig, ok := strings.Cut(s.FormToken, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(t.sign(payload))) {
+		return Result{Score: t.invalidScore, Reason: "invalid form token"}
+	}
+	unix, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		return Result{Score: t.invalidScore, Reason: "invalid form token"}
+	}
+
+	elapsed := t.now().Sub(time.Unix(unix, 0))
+	switch {
+	case elapsed < t.minDelay:
+		return Result{Score: t.invalidScore, Reason: "submitted " + elapsed.Round(time.Second).String() + " after the form loaded"}
+	case elapsed > t.maxAge:
+		return Result{Score: t.missingScore, Reason: "form token expired"}
+	}
+	return Result{}
+}
@@ -0,0 +1,51 @@
+package spam
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestTimingToken(now *time.Time) *TimingToken {
+	tt := NewTimingToken([]byte("test-secret-key-at-least-32-chars!"), 3*time.Second, time.Hour, 2, 5)
+	tt.now = func() time.Time { return *now }
+	return tt
+}
+
+func TestTimingToken_Score(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	scorer := newTestTimingToken(&now)
+	token, expires := scorer.Issue()
+	if !expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected expiry %v, got %v", now.Add(time.Hour), expires)
+	}
+
+	other := NewTimingToken([]byte("another-secret-key-of-32-chars!!"), 0, time.Hour, 2, 5)
+	forged, _ := other.Issue()
+
+	tests := []struct {
+		name  string
+		token string
+		after time.Duration
+		want  int
+	}{
+		{"human", token, 30 * time.Second, 0},
+		{"missing", "", 30 * time.Second, 2},
+		{"too fast", token, time.Second, 5},
+		{"expired", token, 2 * time.Hour, 2},
+		{"wrong secret", forged, 30 * time.Second, 5},
+		{"malformed", "not-a-token", 30 * time.Second, 5},
+		{"tampered time", "1." + token[len(token)-43:], 30 * time.Second, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := now.Add(tt.after)
+			scorer.now = func() time.Time { return at }
+			r := scorer.Score(context.Background(), Submission{FormToken: tt.token})
+			if r.Score != tt.want {
+				t.Errorf("Score() = %d (%s), want %d", r.Score, r.Reason, tt.want)
+			}
+		})
+	}
+}
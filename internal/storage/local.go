@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// localKeyPattern matches keys from NewKey, so a key can never escape the storage directory
+var localKeyPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// LocalStore keeps blobs in a directory for development and tests.
+// Download links point back at this service and are signed with an HMAC instead of a presigned URL.
+type LocalStore struct {
+	dir         string
+	downloadURL string
+	secret      []byte
+	now         func() time.Time
+}
+
+// NewLocalStore creates dir if needed. downloadURL is the public route that calls ServeSigned.
+func NewLocalStore(dir, downloadURL string, secret []byte) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+	return &LocalStore{dir: dir, downloadURL: downloadURL, secret: secret, now: time.Now}, nil
+}
+
+// Put writes a blob to disk
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", key, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return f.Close()
+}
+
+// Delete removes a blob; a missing blob is not an error
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL links to ServeSigned with an expiry and HMAC over the key, filename and expiry
+func (s *LocalStore) SignedURL(_ context.Context, key, filename string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	query := url.Values{
+		"key":       {key},
+		"filename":  {filename},
+		"expires":   {expires},
+		"signature": {s.sign(key, filename, expires)},
+	}
+	return s.downloadURL + "?" + query.Encode(), nil
+}
+
+// ServeSigned serves a blob if the link's signature is valid and has not expired
+func (s *LocalStore) ServeSigned(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	key, filename, expires := q.Get("key"), q.Get("filename"), q.Get("expires")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(q.Get("signature")), []byte(s.sign(key, filename, expires))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	if s.now().Unix() > expiresAt {
+		http.Error(w, "link expired", http.StatusForbidden)
+		return
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(path) // #nosec G304 -- key is validated against localKeyPattern
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "failed to read attachment", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Disposition", contentDisposition(filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, filename, info.ModTime(), f)
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !localKeyPattern.MatchString(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+func (s *LocalStore) sign(key, filename, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + filename + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
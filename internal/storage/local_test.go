@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+	store, err := NewLocalStore(t.TempDir(), "http://localhost/api/v1/attachments/files", []byte("test-secret-key-at-least-32-chars!"))
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	return store
+}
+
+func putTestBlob(t *testing.T, store *LocalStore, content string) string {
+	t.Helper()
+	key, err := NewKey()
+	if err != nil {
+		t.Fatalf("NewKey() error = %v", err)
+	}
+	if err := store.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	return key
+}
+
+func serve(store *LocalStore, rawURL string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	store.ServeSigned(w, httptest.NewRequest(http.MethodGet, rawURL, nil))
+	return w
+}
+
+func TestLocalStore_SignedURLRoundTrip(t *testing.T) {
+	store := newTestLocalStore(t)
+	key := putTestBlob(t, store, "hello attachment")
+
+	signed, err := store.SignedURL(context.Background(), key, "brief.txt", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+	if !strings.HasPrefix(signed, "http://localhost/api/v1/attachments/files?") {
+		t.Errorf("unexpected URL %q", signed)
+	}
+
+	w := serve(store, signed)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	body, _ := io.ReadAll(w.Body)
+	if string(body) != "hello attachment" {
+		t.Errorf("body = %q", body)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename=brief.txt` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+}
+
+func TestLocalStore_RejectsTamperedOrExpiredLinks(t *testing.T) {
+	store := newTestLocalStore(t)
+	key := putTestBlob(t, store, "secret")
+
+	signed, err := store.SignedURL(context.Background(), key, "cv.pdf", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+
+	u, _ := url.Parse(signed)
+	q := u.Query()
+	q.Set("filename", "other.pdf")
+	u.RawQuery = q.Encode()
+	if w := serve(store, u.String()); w.Code != http.StatusForbidden {
+		t.Errorf("tampered link: expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if w := serve(store, signed); w.Code != http.StatusForbidden {
+		t.Errorf("expired link: expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestLocalStore_Delete(t *testing.T) {
+	store := newTestLocalStore(t)
+	key := putTestBlob(t, store, "gone soon")
+
+	if err := store.Delete(context.Background(), key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(context.Background(), key); err != nil {
+		t.Errorf("Delete() of missing blob error = %v", err)
+	}
+
+	signed, _ := store.SignedURL(context.Background(), key, "x.txt", time.Minute)
+	if w := serve(store, signed); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestLocalStore_RejectsUnsafeKeys(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	for _, key := range []string{"../etc/passwd", "", "ABCDEF", "a/b"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) expected error", key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	commonconfig "github.com/GunarsK-portfolio/portfolio-common/config"
+)
+
+// MinIOStore stores blobs in a MinIO or S3 bucket and signs downloads with presigned GET URLs
+type MinIOStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewMinIOStore connects to the S3-compatible endpoint in cfg. Without static keys the
+// IAM role credentials of the host are used.
+func NewMinIOStore(cfg commonconfig.S3Config, bucket string) (*MinIOStore, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	creds := credentials.NewIAM("")
+	if cfg.AccessKey != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:  creds,
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	return &MinIOStore{client: client, bucket: bucket}, nil
+}
+
+// Client exposes the underlying client for health checks
+func (s *MinIOStore) Client() *minio.Client {
+	return s.client
+}
+
+// Put uploads a blob
+func (s *MinIOStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes a blob
+func (s *MinIOStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL presigns a GET that downloads the blob as filename
+func (s *MinIOStore) SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error) {
+	params := url.Values{"response-content-disposition": {contentDisposition(filename)}}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return u.String(), nil
+}
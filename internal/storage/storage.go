@@ -0,0 +1,39 @@
+// Package storage persists email attachment blobs in object storage or on the local filesystem.
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// Store persists attachment blobs under opaque keys
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited download link that serves the blob as filename
+	SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+}
+
+// SignedFileServer is implemented by stores that serve their own signed links (the local backend)
+type SignedFileServer interface {
+	ServeSigned(w http.ResponseWriter, r *http.Request)
+}
+
+// NewKey returns a random object key for a new blob
+func NewKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// contentDisposition formats an attachment Content-Disposition header for filename
+func contentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
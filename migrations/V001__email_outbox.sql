@@ -0,0 +1,19 @@
+-- Transactional outbox: one row per EmailEvent, written in the same
+-- transaction as the email and drained by the API's outbox relay.
+CREATE TABLE IF NOT EXISTS messaging.email_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    email_id        BIGINT NOT NULL REFERENCES messaging.emails (id) ON DELETE CASCADE,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at   TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_due
+    ON messaging.email_outbox (next_attempt_at, id)
+    WHERE dispatched_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_email_id
+    ON messaging.email_outbox (email_id)
+    WHERE dispatched_at IS NULL;
@@ -0,0 +1,4 @@
+-- Supports the reconciler's scan for stale pending emails.
+CREATE INDEX IF NOT EXISTS idx_emails_pending_updated_at
+    ON messaging.emails (updated_at, id)
+    WHERE status = 'pending';
@@ -0,0 +1,16 @@
+-- Keyset pagination for GET /emails (ORDER BY created_at DESC, id DESC)
+CREATE INDEX IF NOT EXISTS idx_emails_created_at_id
+    ON messaging.emails (created_at DESC, id DESC);
+
+-- Listing filters
+CREATE INDEX IF NOT EXISTS idx_emails_status_created_at
+    ON messaging.emails (status, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_emails_type_created_at
+    ON messaging.emails (type, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_emails_sender_lower
+    ON messaging.emails (LOWER(email));
+
+CREATE INDEX IF NOT EXISTS idx_emails_recipient_lower
+    ON messaging.emails (LOWER(recipient_email));
@@ -0,0 +1,13 @@
+-- Full-text search for GET /emails/search.
+-- Uses the "simple" configuration because messages arrive in several languages.
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(subject, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(name, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(email, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(message, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_emails_search_vector
+    ON messaging.emails USING GIN (search_vector);
@@ -0,0 +1,21 @@
+-- Allow the service-local "cancelled" status (POST /emails/{id}/cancel).
+-- Replaces any existing status CHECK with a named constraint that later
+-- migrations can extend.
+DO $$
+DECLARE
+    con RECORD;
+BEGIN
+    FOR con IN
+        SELECT conname
+        FROM pg_constraint
+        WHERE conrelid = 'messaging.emails'::regclass
+          AND contype = 'c'
+          AND pg_get_constraintdef(oid) LIKE '%status%'
+    LOOP
+        EXECUTE format('ALTER TABLE messaging.emails DROP CONSTRAINT %I', con.conname);
+    END LOOP;
+END $$;
+
+ALTER TABLE messaging.emails
+    ADD CONSTRAINT emails_status_check
+    CHECK (status IN ('pending', 'queued', 'sent', 'failed', 'cancelled'));
@@ -0,0 +1,12 @@
+-- Idempotency-Key values for POST /emails. Each key remembers the hash of
+-- its first request body and the email it created until expires_at.
+CREATE TABLE IF NOT EXISTS messaging.email_idempotency_keys (
+    key          VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    email_id     BIGINT NOT NULL REFERENCES messaging.emails (id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_idempotency_keys_expires_at
+    ON messaging.email_idempotency_keys (expires_at);
@@ -0,0 +1,42 @@
+-- Database-managed email templates. Versions are immutable; an edit inserts
+-- the next version and the template's active_version_id selects which one
+-- POST /emails renders. Without an active version the built-in template is used.
+CREATE TABLE IF NOT EXISTS messaging.email_templates (
+    id                BIGSERIAL PRIMARY KEY,
+    type              VARCHAR(50) NOT NULL UNIQUE,
+    active_version_id BIGINT,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS messaging.email_template_versions (
+    id          BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES messaging.email_templates (id) ON DELETE CASCADE,
+    version     INTEGER NOT NULL,
+    subject     VARCHAR(500) NOT NULL,
+    html_body   TEXT NOT NULL,
+    text_body   TEXT,
+    created_by  VARCHAR(255),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (template_id, version)
+);
+
+ALTER TABLE messaging.email_templates
+    ADD CONSTRAINT fk_email_templates_active_version
+    FOREIGN KEY (active_version_id) REFERENCES messaging.email_template_versions (id);
+
+-- Versions are immutable once written
+CREATE OR REPLACE FUNCTION messaging.reject_template_version_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'email template versions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_email_template_versions_immutable
+    BEFORE UPDATE ON messaging.email_template_versions
+    FOR EACH ROW EXECUTE FUNCTION messaging.reject_template_version_update();
+
+-- Emails record the template version they were rendered from
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS template_version_id BIGINT
+    REFERENCES messaging.email_template_versions (id) ON DELETE SET NULL;
@@ -0,0 +1,14 @@
+-- Templates are selected per locale. An empty locale is the default that a
+-- request falls back to when neither its tag nor a parent (lv-LV -> lv) has one.
+ALTER TABLE messaging.email_templates
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+
+ALTER TABLE messaging.email_templates
+    DROP CONSTRAINT IF EXISTS email_templates_type_key;
+
+ALTER TABLE messaging.email_templates
+    ADD CONSTRAINT email_templates_type_locale_key UNIQUE (type, locale);
+
+-- Emails record the locale they were requested in
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(35);
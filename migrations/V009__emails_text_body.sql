@@ -0,0 +1,33 @@
+-- Plain-text alternative body. message holds the HTML part; text_body the
+-- text/plain part so the sender can deliver multipart/alternative.
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS text_body TEXT;
+
+-- Contact messages used to store the visitor's raw text in message. Move it to
+-- text_body and store the escaped HTML rendering (same as plaintext.ToHTML).
+-- Templated emails keep text_body NULL; the sender falls back to HTML only.
+UPDATE messaging.emails
+SET text_body = replace(replace(message, E'\r\n', E'\n'), E'\r', E'\n'),
+    message = replace(
+        replace(replace(replace(replace(replace(
+            replace(replace(message, E'\r\n', E'\n'), E'\r', E'\n'),
+            '&', '&amp;'), '''', '&#39;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'),
+        E'\n', E'<br>\n')
+WHERE type = 'contact_form' AND text_body IS NULL;
+
+-- Index the text body instead of HTML markup where one exists
+DROP INDEX IF EXISTS messaging.idx_emails_search_vector;
+
+ALTER TABLE messaging.emails DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE messaging.emails
+    ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(subject, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(name, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(email, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(text_body, message, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_emails_search_vector
+    ON messaging.emails USING GIN (search_vector);
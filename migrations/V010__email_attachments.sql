@@ -0,0 +1,14 @@
+-- Files attached to contact messages and S2S emails. Blobs live in object
+-- storage (or the local dev directory) under storage_key.
+CREATE TABLE IF NOT EXISTS messaging.email_attachments (
+    id           BIGSERIAL PRIMARY KEY,
+    email_id     BIGINT NOT NULL REFERENCES messaging.emails (id) ON DELETE CASCADE,
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes   BIGINT NOT NULL CHECK (size_bytes >= 0),
+    storage_key  VARCHAR(255) NOT NULL UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_attachments_email_id
+    ON messaging.email_attachments (email_id);
@@ -0,0 +1,14 @@
+-- Automatic replies (e.g. the contact_ack sent to contact form senders) point
+-- at the email that triggered them so admins can see both together.
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS in_reply_to_id BIGINT
+        REFERENCES messaging.emails (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_emails_in_reply_to_id
+    ON messaging.emails (in_reply_to_id)
+    WHERE in_reply_to_id IS NOT NULL;
+
+-- Per-recipient throttling looks up the latest email of a type to an address
+CREATE INDEX IF NOT EXISTS idx_emails_type_recipient_created_at
+    ON messaging.emails (type, LOWER(recipient_email), created_at DESC)
+    WHERE recipient_email IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Contact messages scored by the spam pipeline: borderline submissions are
+-- stored as "quarantined" for review instead of being delivered or dropped.
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS spam_score INTEGER,
+    ADD COLUMN IF NOT EXISTS spam_reasons JSONB;
+
+ALTER TABLE messaging.emails DROP CONSTRAINT IF EXISTS emails_status_check;
+
+ALTER TABLE messaging.emails
+    ADD CONSTRAINT emails_status_check
+    CHECK (status IN ('pending', 'queued', 'sent', 'failed', 'cancelled', 'quarantined'));
+
+-- Quarantine review lists the newest held messages first
+CREATE INDEX IF NOT EXISTS idx_emails_quarantined_created_at
+    ON messaging.emails (created_at DESC, id DESC)
+    WHERE status = 'quarantined';
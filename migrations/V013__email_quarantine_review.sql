@@ -0,0 +1,17 @@
+-- Quarantine review: admins approve quarantined contact messages (back to
+-- "pending") or reject them as "spam", optionally blocking the sender.
+ALTER TABLE messaging.emails DROP CONSTRAINT IF EXISTS emails_status_check;
+
+ALTER TABLE messaging.emails
+    ADD CONSTRAINT emails_status_check
+    CHECK (status IN ('pending', 'queued', 'sent', 'failed', 'cancelled', 'quarantined', 'spam'));
+
+CREATE TABLE IF NOT EXISTS messaging.blocklist_entries (
+    id         BIGSERIAL PRIMARY KEY,
+    kind       VARCHAR(20) NOT NULL CHECK (kind IN ('address', 'domain')),
+    value      VARCHAR(255) NOT NULL CHECK (value = LOWER(value)),
+    reason     TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (kind, value)
+);
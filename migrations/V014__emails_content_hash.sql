@@ -0,0 +1,8 @@
+-- Contact messages record a hash of sender, subject and text so that a repeated
+-- submission (e.g. a double-clicked submit button) is not stored twice.
+ALTER TABLE messaging.emails
+    ADD COLUMN IF NOT EXISTS content_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_emails_content_hash_created_at
+    ON messaging.emails (content_hash, created_at DESC)
+    WHERE content_hash IS NOT NULL;
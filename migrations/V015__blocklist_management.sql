@@ -0,0 +1,10 @@
+-- Blocklist management API: besides exact addresses and domains, entries can
+-- be wildcard patterns over the whole address or CIDR ranges of client IPs.
+ALTER TABLE messaging.blocklist_entries DROP CONSTRAINT IF EXISTS blocklist_entries_kind_check;
+
+ALTER TABLE messaging.blocklist_entries
+    ADD CONSTRAINT blocklist_entries_kind_check
+    CHECK (kind IN ('address', 'domain', 'pattern', 'cidr'));
+
+ALTER TABLE messaging.blocklist_entries
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
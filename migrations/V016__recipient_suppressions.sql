@@ -0,0 +1,21 @@
+-- Recipient suppression list: POST /emails refuses addresses that hard-bounced,
+-- complained, unsubscribed or were suppressed by an admin. Lifted suppressions
+-- are kept as history; an address has at most one active suppression.
+CREATE TABLE IF NOT EXISTS messaging.suppressions (
+    id         BIGSERIAL PRIMARY KEY,
+    email      VARCHAR(255) NOT NULL CHECK (email = LOWER(email)),
+    reason     VARCHAR(20) NOT NULL
+               CHECK (reason IN ('hard_bounce', 'complaint', 'manual', 'unsubscribe')),
+    note       TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lifted_at  TIMESTAMPTZ,
+    lifted_by  VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppressions_active_email
+    ON messaging.suppressions (email)
+    WHERE lifted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_suppressions_created_at
+    ON messaging.suppressions (created_at DESC, id DESC);
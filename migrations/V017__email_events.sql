@@ -0,0 +1,34 @@
+-- Per-email timeline: an append-only record of every change to an email
+-- (created, published, sent, failed, retried, cancelled, bounced, ...) with
+-- type-specific details. Rows are only removed along with their email.
+CREATE TABLE IF NOT EXISTS messaging.email_events (
+    id         BIGSERIAL PRIMARY KEY,
+    email_id   BIGINT NOT NULL REFERENCES messaging.emails (id) ON DELETE CASCADE,
+    type       VARCHAR(30) NOT NULL
+               CHECK (type IN ('created', 'published', 'publish_failed', 'sending', 'sent',
+                               'failed', 'pending', 'retried', 'cancelled', 'approved', 'rejected',
+                               'delivered', 'bounced', 'complained')),
+    details    JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_events_email_id
+    ON messaging.email_events (email_id, created_at, id);
+
+CREATE OR REPLACE FUNCTION messaging.email_events_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'messaging.email_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS email_events_append_only ON messaging.email_events;
+CREATE TRIGGER email_events_append_only
+    BEFORE UPDATE ON messaging.email_events
+    FOR EACH ROW EXECUTE FUNCTION messaging.email_events_append_only();
+
+-- Existing emails start their timeline with a backfilled "created" event
+INSERT INTO messaging.email_events (email_id, type, details, created_at)
+SELECT id, 'created', jsonb_build_object('status', status, 'backfilled', true), created_at
+FROM messaging.emails e
+WHERE NOT EXISTS (SELECT 1 FROM messaging.email_events ev WHERE ev.email_id = e.id);
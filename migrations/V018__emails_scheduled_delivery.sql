@@ -0,0 +1,29 @@
+-- Scheduled delivery: POST /emails with a future send_at stores the email as
+-- "scheduled" until the in-API scheduler releases it to "pending" with an
+-- outbox entry (recorded as a "released" event in the email's timeline).
+ALTER TABLE messaging.emails ADD COLUMN IF NOT EXISTS send_at TIMESTAMPTZ;
+
+ALTER TABLE messaging.emails DROP CONSTRAINT IF EXISTS emails_status_check;
+
+ALTER TABLE messaging.emails
+    ADD CONSTRAINT emails_status_check
+    CHECK (status IN ('pending', 'queued', 'sent', 'failed', 'cancelled', 'quarantined', 'spam', 'scheduled'));
+
+ALTER TABLE messaging.emails DROP CONSTRAINT IF EXISTS emails_scheduled_send_at_check;
+
+ALTER TABLE messaging.emails
+    ADD CONSTRAINT emails_scheduled_send_at_check
+    CHECK (status <> 'scheduled' OR send_at IS NOT NULL);
+
+-- Serves the scheduler's due-email scan and the backlog count
+CREATE INDEX IF NOT EXISTS idx_emails_scheduled_send_at
+    ON messaging.emails (send_at, id)
+    WHERE status = 'scheduled';
+
+ALTER TABLE messaging.email_events DROP CONSTRAINT IF EXISTS email_events_type_check;
+
+ALTER TABLE messaging.email_events
+    ADD CONSTRAINT email_events_type_check
+    CHECK (type IN ('created', 'published', 'publish_failed', 'sending', 'sent',
+                    'failed', 'pending', 'retried', 'cancelled', 'approved', 'rejected',
+                    'released', 'delivered', 'bounced', 'complained'));
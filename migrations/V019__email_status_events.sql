@@ -0,0 +1,29 @@
+-- Record delivery status changes in the email timeline from the database, so
+-- updates made by the worker (through portfolio-common) are captured along
+-- with the API's own. Other transitions (retried, cancelled, approved,
+-- rejected, released) are recorded by the API with their dedicated events.
+CREATE OR REPLACE FUNCTION messaging.record_email_status_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    event_type VARCHAR(30);
+BEGIN
+    event_type := CASE NEW.status
+        WHEN 'queued' THEN 'sending'
+        WHEN 'sent'   THEN 'sent'
+        WHEN 'failed' THEN 'failed'
+    END;
+    IF event_type IS NOT NULL THEN
+        INSERT INTO messaging.email_events (email_id, type, details)
+        VALUES (NEW.id, event_type,
+                jsonb_strip_nulls(jsonb_build_object('from', OLD.status, 'lastError', NEW.last_error)));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS emails_record_status_event ON messaging.emails;
+CREATE TRIGGER emails_record_status_event
+    AFTER UPDATE OF status ON messaging.emails
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION messaging.record_email_status_event();
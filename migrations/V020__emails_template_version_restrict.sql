@@ -0,0 +1,10 @@
+-- Emails keep the template version they were rendered from: refuse to delete
+-- a version (or, through the cascade, its template) while emails reference it,
+-- instead of clearing template_version_id. Unused templates can still be
+-- deleted; used ones are deactivated instead.
+ALTER TABLE messaging.emails DROP CONSTRAINT IF EXISTS emails_template_version_id_fkey;
+
+ALTER TABLE messaging.emails
+    ADD CONSTRAINT emails_template_version_id_fkey
+    FOREIGN KEY (template_version_id)
+    REFERENCES messaging.email_template_versions (id) ON DELETE RESTRICT;
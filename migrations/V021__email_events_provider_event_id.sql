@@ -0,0 +1,8 @@
+-- Delivery webhooks are retried by providers, so the same event can arrive
+-- more than once. Provider events record the provider's event ID and a
+-- redelivery is ignored instead of adding a second timeline entry.
+ALTER TABLE messaging.email_events ADD COLUMN IF NOT EXISTS provider_event_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_events_provider_event
+    ON messaging.email_events (email_id, type, provider_event_id)
+    WHERE provider_event_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Scope Idempotency-Key values to the caller (the authenticated username), so
+-- two services that pick the same key cannot replay each other's emails.
+ALTER TABLE messaging.email_idempotency_keys
+    ADD COLUMN IF NOT EXISTS caller VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE messaging.email_idempotency_keys DROP CONSTRAINT IF EXISTS email_idempotency_keys_pkey;
+
+ALTER TABLE messaging.email_idempotency_keys
+    ADD CONSTRAINT email_idempotency_keys_pkey PRIMARY KEY (caller, key);